@@ -0,0 +1,17 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type CommentMention struct {
+	CommentID uuid.UUID `sql:"primary_key"`
+	UserName  string    `sql:"primary_key"`
+}
@@ -17,4 +17,5 @@ type Impact struct {
 	Description string
 	Value       int16
 	Category    int16
+	Confidence  *int16
 }
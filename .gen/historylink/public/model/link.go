@@ -12,8 +12,9 @@ import (
 )
 
 type Link struct {
-	ID        uuid.UUID `sql:"primary_key"`
-	RecordID  uuid.UUID
-	RecordId2 uuid.UUID
-	Strength  int16
+	ID         uuid.UUID `sql:"primary_key"`
+	RecordID   uuid.UUID
+	RecordId2  uuid.UUID
+	Strength   int16
+	Confidence *int16
 }
@@ -0,0 +1,25 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Notification struct {
+	ID              uuid.UUID `sql:"primary_key"`
+	UserName        string
+	RecordID        uuid.UUID
+	Kind            int16
+	RelatedRecordID *uuid.UUID
+	CreatedAt       time.Time
+	ReadAt          *time.Time
+	CommentID       *uuid.UUID
+	Txid            *int64
+}
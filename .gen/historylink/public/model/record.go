@@ -23,4 +23,6 @@ type Record struct {
 	EndDate      *time.Time
 	Type         int16
 	Status       int16
+	Attributes   string
+	Confidence   *int16
 }
@@ -26,4 +26,5 @@ type RecordHistory struct {
 	Status       int16
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	Seq          int64
 }
@@ -12,10 +12,14 @@ import (
 )
 
 type Source struct {
-	ID          uuid.UUID `sql:"primary_key"`
-	RecordID    uuid.UUID
-	Title       string
-	Type        int16
-	URL         string
-	Description *string
+	ID             uuid.UUID `sql:"primary_key"`
+	RecordID       uuid.UUID
+	Title          string
+	Type           int16
+	URL            string
+	Description    *string
+	Authors        string
+	Year           *int16
+	Publisher      *string
+	ContainerTitle *string
 }
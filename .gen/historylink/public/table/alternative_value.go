@@ -0,0 +1,87 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var AlternativeValue = newAlternativeValueTable("public", "alternative_value", "")
+
+type alternativeValueTable struct {
+	postgres.Table
+
+	// Columns
+	ID        postgres.ColumnString
+	RecordID  postgres.ColumnString
+	Field     postgres.ColumnString
+	Value     postgres.ColumnString
+	CreatedAt postgres.ColumnTimestamp
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type AlternativeValueTable struct {
+	alternativeValueTable
+
+	EXCLUDED alternativeValueTable
+}
+
+// AS creates new AlternativeValueTable with assigned alias
+func (a AlternativeValueTable) AS(alias string) *AlternativeValueTable {
+	return newAlternativeValueTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new AlternativeValueTable with assigned schema name
+func (a AlternativeValueTable) FromSchema(schemaName string) *AlternativeValueTable {
+	return newAlternativeValueTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new AlternativeValueTable with assigned table prefix
+func (a AlternativeValueTable) WithPrefix(prefix string) *AlternativeValueTable {
+	return newAlternativeValueTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new AlternativeValueTable with assigned table suffix
+func (a AlternativeValueTable) WithSuffix(suffix string) *AlternativeValueTable {
+	return newAlternativeValueTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newAlternativeValueTable(schemaName, tableName, alias string) *AlternativeValueTable {
+	return &AlternativeValueTable{
+		alternativeValueTable: newAlternativeValueTableImpl(schemaName, tableName, alias),
+		EXCLUDED:              newAlternativeValueTableImpl("", "excluded", ""),
+	}
+}
+
+func newAlternativeValueTableImpl(schemaName, tableName, alias string) alternativeValueTable {
+	var (
+		IDColumn        = postgres.StringColumn("id")
+		RecordIDColumn  = postgres.StringColumn("record_id")
+		FieldColumn     = postgres.StringColumn("field")
+		ValueColumn     = postgres.StringColumn("value")
+		CreatedAtColumn = postgres.TimestampColumn("created_at")
+		allColumns      = postgres.ColumnList{IDColumn, RecordIDColumn, FieldColumn, ValueColumn, CreatedAtColumn}
+		mutableColumns  = postgres.ColumnList{RecordIDColumn, FieldColumn, ValueColumn, CreatedAtColumn}
+	)
+
+	return alternativeValueTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:        IDColumn,
+		RecordID:  RecordIDColumn,
+		Field:     FieldColumn,
+		Value:     ValueColumn,
+		CreatedAt: CreatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
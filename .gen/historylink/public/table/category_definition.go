@@ -0,0 +1,81 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var CategoryDefinition = newCategoryDefinitionTable("public", "category_definition", "")
+
+type categoryDefinitionTable struct {
+	postgres.Table
+
+	// Columns
+	ID      postgres.ColumnInteger
+	Name    postgres.ColumnString
+	Builtin postgres.ColumnBool
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type CategoryDefinitionTable struct {
+	categoryDefinitionTable
+
+	EXCLUDED categoryDefinitionTable
+}
+
+// AS creates new CategoryDefinitionTable with assigned alias
+func (a CategoryDefinitionTable) AS(alias string) *CategoryDefinitionTable {
+	return newCategoryDefinitionTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new CategoryDefinitionTable with assigned schema name
+func (a CategoryDefinitionTable) FromSchema(schemaName string) *CategoryDefinitionTable {
+	return newCategoryDefinitionTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new CategoryDefinitionTable with assigned table prefix
+func (a CategoryDefinitionTable) WithPrefix(prefix string) *CategoryDefinitionTable {
+	return newCategoryDefinitionTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new CategoryDefinitionTable with assigned table suffix
+func (a CategoryDefinitionTable) WithSuffix(suffix string) *CategoryDefinitionTable {
+	return newCategoryDefinitionTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newCategoryDefinitionTable(schemaName, tableName, alias string) *CategoryDefinitionTable {
+	return &CategoryDefinitionTable{
+		categoryDefinitionTable: newCategoryDefinitionTableImpl(schemaName, tableName, alias),
+		EXCLUDED:                newCategoryDefinitionTableImpl("", "excluded", ""),
+	}
+}
+
+func newCategoryDefinitionTableImpl(schemaName, tableName, alias string) categoryDefinitionTable {
+	var (
+		IDColumn       = postgres.IntegerColumn("id")
+		NameColumn     = postgres.StringColumn("name")
+		BuiltinColumn  = postgres.BoolColumn("builtin")
+		allColumns     = postgres.ColumnList{IDColumn, NameColumn, BuiltinColumn}
+		mutableColumns = postgres.ColumnList{NameColumn, BuiltinColumn}
+	)
+
+	return categoryDefinitionTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:      IDColumn,
+		Name:    NameColumn,
+		Builtin: BuiltinColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
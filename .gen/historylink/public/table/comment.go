@@ -0,0 +1,102 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Comment = newCommentTable("public", "comment", "")
+
+type commentTable struct {
+	postgres.Table
+
+	// Columns
+	ID         postgres.ColumnString
+	RecordID   postgres.ColumnString
+	LinkID     postgres.ColumnString
+	ParentID   postgres.ColumnString
+	Author     postgres.ColumnString
+	Body       postgres.ColumnString
+	ResolvedAt postgres.ColumnTimestamp
+	ResolvedBy postgres.ColumnString
+	CreatedAt  postgres.ColumnTimestamp
+	UpdatedAt  postgres.ColumnTimestamp
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type CommentTable struct {
+	commentTable
+
+	EXCLUDED commentTable
+}
+
+// AS creates new CommentTable with assigned alias
+func (a CommentTable) AS(alias string) *CommentTable {
+	return newCommentTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new CommentTable with assigned schema name
+func (a CommentTable) FromSchema(schemaName string) *CommentTable {
+	return newCommentTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new CommentTable with assigned table prefix
+func (a CommentTable) WithPrefix(prefix string) *CommentTable {
+	return newCommentTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new CommentTable with assigned table suffix
+func (a CommentTable) WithSuffix(suffix string) *CommentTable {
+	return newCommentTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newCommentTable(schemaName, tableName, alias string) *CommentTable {
+	return &CommentTable{
+		commentTable: newCommentTableImpl(schemaName, tableName, alias),
+		EXCLUDED:     newCommentTableImpl("", "excluded", ""),
+	}
+}
+
+func newCommentTableImpl(schemaName, tableName, alias string) commentTable {
+	var (
+		IDColumn         = postgres.StringColumn("id")
+		RecordIDColumn   = postgres.StringColumn("record_id")
+		LinkIDColumn     = postgres.StringColumn("link_id")
+		ParentIDColumn   = postgres.StringColumn("parent_id")
+		AuthorColumn     = postgres.StringColumn("author")
+		BodyColumn       = postgres.StringColumn("body")
+		ResolvedAtColumn = postgres.TimestampColumn("resolved_at")
+		ResolvedByColumn = postgres.StringColumn("resolved_by")
+		CreatedAtColumn  = postgres.TimestampColumn("created_at")
+		UpdatedAtColumn  = postgres.TimestampColumn("updated_at")
+		allColumns       = postgres.ColumnList{IDColumn, RecordIDColumn, LinkIDColumn, ParentIDColumn, AuthorColumn, BodyColumn, ResolvedAtColumn, ResolvedByColumn, CreatedAtColumn, UpdatedAtColumn}
+		mutableColumns   = postgres.ColumnList{RecordIDColumn, LinkIDColumn, ParentIDColumn, AuthorColumn, BodyColumn, ResolvedAtColumn, ResolvedByColumn, CreatedAtColumn, UpdatedAtColumn}
+	)
+
+	return commentTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:         IDColumn,
+		RecordID:   RecordIDColumn,
+		LinkID:     LinkIDColumn,
+		ParentID:   ParentIDColumn,
+		Author:     AuthorColumn,
+		Body:       BodyColumn,
+		ResolvedAt: ResolvedAtColumn,
+		ResolvedBy: ResolvedByColumn,
+		CreatedAt:  CreatedAtColumn,
+		UpdatedAt:  UpdatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
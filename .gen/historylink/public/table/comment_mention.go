@@ -0,0 +1,78 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var CommentMention = newCommentMentionTable("public", "comment_mention", "")
+
+type commentMentionTable struct {
+	postgres.Table
+
+	// Columns
+	CommentID postgres.ColumnString
+	UserName  postgres.ColumnString
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type CommentMentionTable struct {
+	commentMentionTable
+
+	EXCLUDED commentMentionTable
+}
+
+// AS creates new CommentMentionTable with assigned alias
+func (a CommentMentionTable) AS(alias string) *CommentMentionTable {
+	return newCommentMentionTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new CommentMentionTable with assigned schema name
+func (a CommentMentionTable) FromSchema(schemaName string) *CommentMentionTable {
+	return newCommentMentionTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new CommentMentionTable with assigned table prefix
+func (a CommentMentionTable) WithPrefix(prefix string) *CommentMentionTable {
+	return newCommentMentionTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new CommentMentionTable with assigned table suffix
+func (a CommentMentionTable) WithSuffix(suffix string) *CommentMentionTable {
+	return newCommentMentionTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newCommentMentionTable(schemaName, tableName, alias string) *CommentMentionTable {
+	return &CommentMentionTable{
+		commentMentionTable: newCommentMentionTableImpl(schemaName, tableName, alias),
+		EXCLUDED:            newCommentMentionTableImpl("", "excluded", ""),
+	}
+}
+
+func newCommentMentionTableImpl(schemaName, tableName, alias string) commentMentionTable {
+	var (
+		CommentIDColumn = postgres.StringColumn("comment_id")
+		UserNameColumn  = postgres.StringColumn("user_name")
+		allColumns      = postgres.ColumnList{CommentIDColumn, UserNameColumn}
+		mutableColumns  = postgres.ColumnList{}
+	)
+
+	return commentMentionTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		CommentID: CommentIDColumn,
+		UserName:  UserNameColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
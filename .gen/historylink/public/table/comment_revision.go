@@ -0,0 +1,84 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var CommentRevision = newCommentRevisionTable("public", "comment_revision", "")
+
+type commentRevisionTable struct {
+	postgres.Table
+
+	// Columns
+	ID        postgres.ColumnString
+	CommentID postgres.ColumnString
+	Body      postgres.ColumnString
+	CreatedAt postgres.ColumnTimestamp
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type CommentRevisionTable struct {
+	commentRevisionTable
+
+	EXCLUDED commentRevisionTable
+}
+
+// AS creates new CommentRevisionTable with assigned alias
+func (a CommentRevisionTable) AS(alias string) *CommentRevisionTable {
+	return newCommentRevisionTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new CommentRevisionTable with assigned schema name
+func (a CommentRevisionTable) FromSchema(schemaName string) *CommentRevisionTable {
+	return newCommentRevisionTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new CommentRevisionTable with assigned table prefix
+func (a CommentRevisionTable) WithPrefix(prefix string) *CommentRevisionTable {
+	return newCommentRevisionTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new CommentRevisionTable with assigned table suffix
+func (a CommentRevisionTable) WithSuffix(suffix string) *CommentRevisionTable {
+	return newCommentRevisionTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newCommentRevisionTable(schemaName, tableName, alias string) *CommentRevisionTable {
+	return &CommentRevisionTable{
+		commentRevisionTable: newCommentRevisionTableImpl(schemaName, tableName, alias),
+		EXCLUDED:             newCommentRevisionTableImpl("", "excluded", ""),
+	}
+}
+
+func newCommentRevisionTableImpl(schemaName, tableName, alias string) commentRevisionTable {
+	var (
+		IDColumn        = postgres.StringColumn("id")
+		CommentIDColumn = postgres.StringColumn("comment_id")
+		BodyColumn      = postgres.StringColumn("body")
+		CreatedAtColumn = postgres.TimestampColumn("created_at")
+		allColumns      = postgres.ColumnList{IDColumn, CommentIDColumn, BodyColumn, CreatedAtColumn}
+		mutableColumns  = postgres.ColumnList{CommentIDColumn, BodyColumn, CreatedAtColumn}
+	)
+
+	return commentRevisionTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:        IDColumn,
+		CommentID: CommentIDColumn,
+		Body:      BodyColumn,
+		CreatedAt: CreatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
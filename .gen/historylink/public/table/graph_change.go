@@ -0,0 +1,81 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var GraphChange = newGraphChangeTable("public", "graph_change", "")
+
+type graphChangeTable struct {
+	postgres.Table
+
+	// Columns
+	ID                    postgres.ColumnInteger
+	LinkChanges           postgres.ColumnInteger
+	InfluenceInputChanges postgres.ColumnInteger
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type GraphChangeTable struct {
+	graphChangeTable
+
+	EXCLUDED graphChangeTable
+}
+
+// AS creates new GraphChangeTable with assigned alias
+func (a GraphChangeTable) AS(alias string) *GraphChangeTable {
+	return newGraphChangeTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new GraphChangeTable with assigned schema name
+func (a GraphChangeTable) FromSchema(schemaName string) *GraphChangeTable {
+	return newGraphChangeTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new GraphChangeTable with assigned table prefix
+func (a GraphChangeTable) WithPrefix(prefix string) *GraphChangeTable {
+	return newGraphChangeTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new GraphChangeTable with assigned table suffix
+func (a GraphChangeTable) WithSuffix(suffix string) *GraphChangeTable {
+	return newGraphChangeTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newGraphChangeTable(schemaName, tableName, alias string) *GraphChangeTable {
+	return &GraphChangeTable{
+		graphChangeTable: newGraphChangeTableImpl(schemaName, tableName, alias),
+		EXCLUDED:         newGraphChangeTableImpl("", "excluded", ""),
+	}
+}
+
+func newGraphChangeTableImpl(schemaName, tableName, alias string) graphChangeTable {
+	var (
+		IDColumn                    = postgres.IntegerColumn("id")
+		LinkChangesColumn           = postgres.IntegerColumn("link_changes")
+		InfluenceInputChangesColumn = postgres.IntegerColumn("influence_input_changes")
+		allColumns                  = postgres.ColumnList{IDColumn, LinkChangesColumn, InfluenceInputChangesColumn}
+		mutableColumns              = postgres.ColumnList{LinkChangesColumn, InfluenceInputChangesColumn}
+	)
+
+	return graphChangeTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:                    IDColumn,
+		LinkChanges:           LinkChangesColumn,
+		InfluenceInputChanges: InfluenceInputChangesColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
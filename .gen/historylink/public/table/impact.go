@@ -22,6 +22,7 @@ type impactTable struct {
 	Description postgres.ColumnString
 	Value       postgres.ColumnInteger
 	Category    postgres.ColumnInteger
+	Confidence  postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -67,8 +68,9 @@ func newImpactTableImpl(schemaName, tableName, alias string) impactTable {
 		DescriptionColumn = postgres.StringColumn("description")
 		ValueColumn       = postgres.IntegerColumn("value")
 		CategoryColumn    = postgres.IntegerColumn("category")
-		allColumns        = postgres.ColumnList{IDColumn, RecordIDColumn, DescriptionColumn, ValueColumn, CategoryColumn}
-		mutableColumns    = postgres.ColumnList{RecordIDColumn, DescriptionColumn, ValueColumn, CategoryColumn}
+		ConfidenceColumn  = postgres.IntegerColumn("confidence")
+		allColumns        = postgres.ColumnList{IDColumn, RecordIDColumn, DescriptionColumn, ValueColumn, CategoryColumn, ConfidenceColumn}
+		mutableColumns    = postgres.ColumnList{RecordIDColumn, DescriptionColumn, ValueColumn, CategoryColumn, ConfidenceColumn}
 	)
 
 	return impactTable{
@@ -80,6 +82,7 @@ func newImpactTableImpl(schemaName, tableName, alias string) impactTable {
 		Description: DescriptionColumn,
 		Value:       ValueColumn,
 		Category:    CategoryColumn,
+		Confidence:  ConfidenceColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -17,10 +17,11 @@ type linkTable struct {
 	postgres.Table
 
 	// Columns
-	ID        postgres.ColumnString
-	RecordID  postgres.ColumnString
-	RecordId2 postgres.ColumnString
-	Strength  postgres.ColumnInteger
+	ID         postgres.ColumnString
+	RecordID   postgres.ColumnString
+	RecordId2  postgres.ColumnString
+	Strength   postgres.ColumnInteger
+	Confidence postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -61,22 +62,24 @@ func newLinkTable(schemaName, tableName, alias string) *LinkTable {
 
 func newLinkTableImpl(schemaName, tableName, alias string) linkTable {
 	var (
-		IDColumn        = postgres.StringColumn("id")
-		RecordIDColumn  = postgres.StringColumn("record_id")
-		RecordId2Column = postgres.StringColumn("record_id2")
-		StrengthColumn  = postgres.IntegerColumn("strength")
-		allColumns      = postgres.ColumnList{IDColumn, RecordIDColumn, RecordId2Column, StrengthColumn}
-		mutableColumns  = postgres.ColumnList{RecordIDColumn, RecordId2Column, StrengthColumn}
+		IDColumn         = postgres.StringColumn("id")
+		RecordIDColumn   = postgres.StringColumn("record_id")
+		RecordId2Column  = postgres.StringColumn("record_id2")
+		StrengthColumn   = postgres.IntegerColumn("strength")
+		ConfidenceColumn = postgres.IntegerColumn("confidence")
+		allColumns       = postgres.ColumnList{IDColumn, RecordIDColumn, RecordId2Column, StrengthColumn, ConfidenceColumn}
+		mutableColumns   = postgres.ColumnList{RecordIDColumn, RecordId2Column, StrengthColumn, ConfidenceColumn}
 	)
 
 	return linkTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:        IDColumn,
-		RecordID:  RecordIDColumn,
-		RecordId2: RecordId2Column,
-		Strength:  StrengthColumn,
+		ID:         IDColumn,
+		RecordID:   RecordIDColumn,
+		RecordId2:  RecordId2Column,
+		Strength:   StrengthColumn,
+		Confidence: ConfidenceColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -0,0 +1,99 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Notification = newNotificationTable("public", "notification", "")
+
+type notificationTable struct {
+	postgres.Table
+
+	// Columns
+	ID              postgres.ColumnString
+	UserName        postgres.ColumnString
+	RecordID        postgres.ColumnString
+	Kind            postgres.ColumnInteger
+	RelatedRecordID postgres.ColumnString
+	CreatedAt       postgres.ColumnTimestamp
+	ReadAt          postgres.ColumnTimestamp
+	CommentID       postgres.ColumnString
+	Txid            postgres.ColumnInteger
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type NotificationTable struct {
+	notificationTable
+
+	EXCLUDED notificationTable
+}
+
+// AS creates new NotificationTable with assigned alias
+func (a NotificationTable) AS(alias string) *NotificationTable {
+	return newNotificationTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new NotificationTable with assigned schema name
+func (a NotificationTable) FromSchema(schemaName string) *NotificationTable {
+	return newNotificationTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new NotificationTable with assigned table prefix
+func (a NotificationTable) WithPrefix(prefix string) *NotificationTable {
+	return newNotificationTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new NotificationTable with assigned table suffix
+func (a NotificationTable) WithSuffix(suffix string) *NotificationTable {
+	return newNotificationTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newNotificationTable(schemaName, tableName, alias string) *NotificationTable {
+	return &NotificationTable{
+		notificationTable: newNotificationTableImpl(schemaName, tableName, alias),
+		EXCLUDED:          newNotificationTableImpl("", "excluded", ""),
+	}
+}
+
+func newNotificationTableImpl(schemaName, tableName, alias string) notificationTable {
+	var (
+		IDColumn              = postgres.StringColumn("id")
+		UserNameColumn        = postgres.StringColumn("user_name")
+		RecordIDColumn        = postgres.StringColumn("record_id")
+		KindColumn            = postgres.IntegerColumn("kind")
+		RelatedRecordIDColumn = postgres.StringColumn("related_record_id")
+		CreatedAtColumn       = postgres.TimestampColumn("created_at")
+		ReadAtColumn          = postgres.TimestampColumn("read_at")
+		CommentIDColumn       = postgres.StringColumn("comment_id")
+		TxidColumn            = postgres.IntegerColumn("txid")
+		allColumns            = postgres.ColumnList{IDColumn, UserNameColumn, RecordIDColumn, KindColumn, RelatedRecordIDColumn, CreatedAtColumn, ReadAtColumn, CommentIDColumn, TxidColumn}
+		mutableColumns        = postgres.ColumnList{UserNameColumn, RecordIDColumn, KindColumn, RelatedRecordIDColumn, CreatedAtColumn, ReadAtColumn, CommentIDColumn, TxidColumn}
+	)
+
+	return notificationTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:              IDColumn,
+		UserName:        UserNameColumn,
+		RecordID:        RecordIDColumn,
+		Kind:            KindColumn,
+		RelatedRecordID: RelatedRecordIDColumn,
+		CreatedAt:       CreatedAtColumn,
+		ReadAt:          ReadAtColumn,
+		CommentID:       CommentIDColumn,
+		Txid:            TxidColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -27,6 +27,8 @@ type recordTable struct {
 	EndDate      postgres.ColumnTimestamp
 	Type         postgres.ColumnInteger
 	Status       postgres.ColumnInteger
+	Attributes   postgres.ColumnString
+	Confidence   postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -77,8 +79,10 @@ func newRecordTableImpl(schemaName, tableName, alias string) recordTable {
 		EndDateColumn      = postgres.TimestampColumn("end_date")
 		TypeColumn         = postgres.IntegerColumn("type")
 		StatusColumn       = postgres.IntegerColumn("status")
-		allColumns         = postgres.ColumnList{IDColumn, TitleColumn, DescriptionColumn, LocationColumn, SignificanceColumn, URLColumn, StartDateColumn, EndDateColumn, TypeColumn, StatusColumn}
-		mutableColumns     = postgres.ColumnList{TitleColumn, DescriptionColumn, LocationColumn, SignificanceColumn, URLColumn, StartDateColumn, EndDateColumn, TypeColumn, StatusColumn}
+		AttributesColumn   = postgres.StringColumn("attributes")
+		ConfidenceColumn   = postgres.IntegerColumn("confidence")
+		allColumns         = postgres.ColumnList{IDColumn, TitleColumn, DescriptionColumn, LocationColumn, SignificanceColumn, URLColumn, StartDateColumn, EndDateColumn, TypeColumn, StatusColumn, AttributesColumn, ConfidenceColumn}
+		mutableColumns     = postgres.ColumnList{TitleColumn, DescriptionColumn, LocationColumn, SignificanceColumn, URLColumn, StartDateColumn, EndDateColumn, TypeColumn, StatusColumn, AttributesColumn, ConfidenceColumn}
 	)
 
 	return recordTable{
@@ -95,6 +99,8 @@ func newRecordTableImpl(schemaName, tableName, alias string) recordTable {
 		EndDate:      EndDateColumn,
 		Type:         TypeColumn,
 		Status:       StatusColumn,
+		Attributes:   AttributesColumn,
+		Confidence:   ConfidenceColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
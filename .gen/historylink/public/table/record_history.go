@@ -30,6 +30,7 @@ type recordHistoryTable struct {
 	Status       postgres.ColumnInteger
 	CreatedAt    postgres.ColumnTimestamp
 	UpdatedAt    postgres.ColumnTimestamp
+	Seq          postgres.ColumnInteger
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -83,8 +84,9 @@ func newRecordHistoryTableImpl(schemaName, tableName, alias string) recordHistor
 		StatusColumn       = postgres.IntegerColumn("status")
 		CreatedAtColumn    = postgres.TimestampColumn("created_at")
 		UpdatedAtColumn    = postgres.TimestampColumn("updated_at")
-		allColumns         = postgres.ColumnList{IDColumn, RecordIDColumn, TitleColumn, DescriptionColumn, LocationColumn, SignificanceColumn, URLColumn, StartDateColumn, EndDateColumn, TypeColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns     = postgres.ColumnList{RecordIDColumn, TitleColumn, DescriptionColumn, LocationColumn, SignificanceColumn, URLColumn, StartDateColumn, EndDateColumn, TypeColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn}
+		SeqColumn          = postgres.IntegerColumn("seq")
+		allColumns         = postgres.ColumnList{IDColumn, RecordIDColumn, TitleColumn, DescriptionColumn, LocationColumn, SignificanceColumn, URLColumn, StartDateColumn, EndDateColumn, TypeColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, SeqColumn}
+		mutableColumns     = postgres.ColumnList{RecordIDColumn, TitleColumn, DescriptionColumn, LocationColumn, SignificanceColumn, URLColumn, StartDateColumn, EndDateColumn, TypeColumn, StatusColumn, CreatedAtColumn, UpdatedAtColumn, SeqColumn}
 	)
 
 	return recordHistoryTable{
@@ -104,6 +106,7 @@ func newRecordHistoryTableImpl(schemaName, tableName, alias string) recordHistor
 		Status:       StatusColumn,
 		CreatedAt:    CreatedAtColumn,
 		UpdatedAt:    UpdatedAtColumn,
+		Seq:          SeqColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
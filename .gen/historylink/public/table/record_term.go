@@ -0,0 +1,78 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var RecordTerm = newRecordTermTable("public", "record_term", "")
+
+type recordTermTable struct {
+	postgres.Table
+
+	// Columns
+	RecordID postgres.ColumnString
+	TermID   postgres.ColumnString
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type RecordTermTable struct {
+	recordTermTable
+
+	EXCLUDED recordTermTable
+}
+
+// AS creates new RecordTermTable with assigned alias
+func (a RecordTermTable) AS(alias string) *RecordTermTable {
+	return newRecordTermTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RecordTermTable with assigned schema name
+func (a RecordTermTable) FromSchema(schemaName string) *RecordTermTable {
+	return newRecordTermTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RecordTermTable with assigned table prefix
+func (a RecordTermTable) WithPrefix(prefix string) *RecordTermTable {
+	return newRecordTermTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RecordTermTable with assigned table suffix
+func (a RecordTermTable) WithSuffix(suffix string) *RecordTermTable {
+	return newRecordTermTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRecordTermTable(schemaName, tableName, alias string) *RecordTermTable {
+	return &RecordTermTable{
+		recordTermTable: newRecordTermTableImpl(schemaName, tableName, alias),
+		EXCLUDED:        newRecordTermTableImpl("", "excluded", ""),
+	}
+}
+
+func newRecordTermTableImpl(schemaName, tableName, alias string) recordTermTable {
+	var (
+		RecordIDColumn = postgres.StringColumn("record_id")
+		TermIDColumn   = postgres.StringColumn("term_id")
+		allColumns     = postgres.ColumnList{RecordIDColumn, TermIDColumn}
+		mutableColumns = postgres.ColumnList{}
+	)
+
+	return recordTermTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		RecordID: RecordIDColumn,
+		TermID:   TermIDColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -17,12 +17,16 @@ type sourceTable struct {
 	postgres.Table
 
 	// Columns
-	ID          postgres.ColumnString
-	RecordID    postgres.ColumnString
-	Title       postgres.ColumnString
-	Type        postgres.ColumnInteger
-	URL         postgres.ColumnString
-	Description postgres.ColumnString
+	ID             postgres.ColumnString
+	RecordID       postgres.ColumnString
+	Title          postgres.ColumnString
+	Type           postgres.ColumnInteger
+	URL            postgres.ColumnString
+	Description    postgres.ColumnString
+	Authors        postgres.ColumnString
+	Year           postgres.ColumnInteger
+	Publisher      postgres.ColumnString
+	ContainerTitle postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -63,26 +67,34 @@ func newSourceTable(schemaName, tableName, alias string) *SourceTable {
 
 func newSourceTableImpl(schemaName, tableName, alias string) sourceTable {
 	var (
-		IDColumn          = postgres.StringColumn("id")
-		RecordIDColumn    = postgres.StringColumn("record_id")
-		TitleColumn       = postgres.StringColumn("title")
-		TypeColumn        = postgres.IntegerColumn("type")
-		URLColumn         = postgres.StringColumn("url")
-		DescriptionColumn = postgres.StringColumn("description")
-		allColumns        = postgres.ColumnList{IDColumn, RecordIDColumn, TitleColumn, TypeColumn, URLColumn, DescriptionColumn}
-		mutableColumns    = postgres.ColumnList{RecordIDColumn, TitleColumn, TypeColumn, URLColumn, DescriptionColumn}
+		IDColumn             = postgres.StringColumn("id")
+		RecordIDColumn       = postgres.StringColumn("record_id")
+		TitleColumn          = postgres.StringColumn("title")
+		TypeColumn           = postgres.IntegerColumn("type")
+		URLColumn            = postgres.StringColumn("url")
+		DescriptionColumn    = postgres.StringColumn("description")
+		AuthorsColumn        = postgres.StringColumn("authors")
+		YearColumn           = postgres.IntegerColumn("year")
+		PublisherColumn      = postgres.StringColumn("publisher")
+		ContainerTitleColumn = postgres.StringColumn("container_title")
+		allColumns           = postgres.ColumnList{IDColumn, RecordIDColumn, TitleColumn, TypeColumn, URLColumn, DescriptionColumn, AuthorsColumn, YearColumn, PublisherColumn, ContainerTitleColumn}
+		mutableColumns       = postgres.ColumnList{RecordIDColumn, TitleColumn, TypeColumn, URLColumn, DescriptionColumn, AuthorsColumn, YearColumn, PublisherColumn, ContainerTitleColumn}
 	)
 
 	return sourceTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:          IDColumn,
-		RecordID:    RecordIDColumn,
-		Title:       TitleColumn,
-		Type:        TypeColumn,
-		URL:         URLColumn,
-		Description: DescriptionColumn,
+		ID:             IDColumn,
+		RecordID:       RecordIDColumn,
+		Title:          TitleColumn,
+		Type:           TypeColumn,
+		URL:            URLColumn,
+		Description:    DescriptionColumn,
+		Authors:        AuthorsColumn,
+		Year:           YearColumn,
+		Publisher:      PublisherColumn,
+		ContainerTitle: ContainerTitleColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
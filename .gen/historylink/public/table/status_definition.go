@@ -0,0 +1,81 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var StatusDefinition = newStatusDefinitionTable("public", "status_definition", "")
+
+type statusDefinitionTable struct {
+	postgres.Table
+
+	// Columns
+	ID      postgres.ColumnInteger
+	Name    postgres.ColumnString
+	Builtin postgres.ColumnBool
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type StatusDefinitionTable struct {
+	statusDefinitionTable
+
+	EXCLUDED statusDefinitionTable
+}
+
+// AS creates new StatusDefinitionTable with assigned alias
+func (a StatusDefinitionTable) AS(alias string) *StatusDefinitionTable {
+	return newStatusDefinitionTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new StatusDefinitionTable with assigned schema name
+func (a StatusDefinitionTable) FromSchema(schemaName string) *StatusDefinitionTable {
+	return newStatusDefinitionTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new StatusDefinitionTable with assigned table prefix
+func (a StatusDefinitionTable) WithPrefix(prefix string) *StatusDefinitionTable {
+	return newStatusDefinitionTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new StatusDefinitionTable with assigned table suffix
+func (a StatusDefinitionTable) WithSuffix(suffix string) *StatusDefinitionTable {
+	return newStatusDefinitionTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newStatusDefinitionTable(schemaName, tableName, alias string) *StatusDefinitionTable {
+	return &StatusDefinitionTable{
+		statusDefinitionTable: newStatusDefinitionTableImpl(schemaName, tableName, alias),
+		EXCLUDED:              newStatusDefinitionTableImpl("", "excluded", ""),
+	}
+}
+
+func newStatusDefinitionTableImpl(schemaName, tableName, alias string) statusDefinitionTable {
+	var (
+		IDColumn       = postgres.IntegerColumn("id")
+		NameColumn     = postgres.StringColumn("name")
+		BuiltinColumn  = postgres.BoolColumn("builtin")
+		allColumns     = postgres.ColumnList{IDColumn, NameColumn, BuiltinColumn}
+		mutableColumns = postgres.ColumnList{NameColumn, BuiltinColumn}
+	)
+
+	return statusDefinitionTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:      IDColumn,
+		Name:    NameColumn,
+		Builtin: BuiltinColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
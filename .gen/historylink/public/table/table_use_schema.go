@@ -10,11 +10,38 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	AlternativeValue = AlternativeValue.FromSchema(schema)
+	ArcMember = ArcMember.FromSchema(schema)
+	CategoryDefinition = CategoryDefinition.FromSchema(schema)
+	Claim = Claim.FromSchema(schema)
+	Comment = Comment.FromSchema(schema)
+	CommentMention = CommentMention.FromSchema(schema)
+	CommentRevision = CommentRevision.FromSchema(schema)
+	ExternalIdentifier = ExternalIdentifier.FromSchema(schema)
+	GraphChange = GraphChange.FromSchema(schema)
+	GraphRevision = GraphRevision.FromSchema(schema)
 	Impact = Impact.FromSchema(schema)
 	ImpactHistory = ImpactHistory.FromSchema(schema)
+	Involvement = Involvement.FromSchema(schema)
 	Link = Link.FromSchema(schema)
+	Notification = Notification.FromSchema(schema)
+	Outbox = Outbox.FromSchema(schema)
+	PersonName = PersonName.FromSchema(schema)
+	PersonOccupation = PersonOccupation.FromSchema(schema)
+	PersonProfile = PersonProfile.FromSchema(schema)
 	Record = Record.FromSchema(schema)
+	RecordAlias = RecordAlias.FromSchema(schema)
 	RecordHistory = RecordHistory.FromSchema(schema)
+	RecordInfluence = RecordInfluence.FromSchema(schema)
+	RecordLabel = RecordLabel.FromSchema(schema)
+	RecordRedirect = RecordRedirect.FromSchema(schema)
+	RecordTerm = RecordTerm.FromSchema(schema)
 	SchemaMigrations = SchemaMigrations.FromSchema(schema)
 	Source = Source.FromSchema(schema)
+	StatusDefinition = StatusDefinition.FromSchema(schema)
+	Term = Term.FromSchema(schema)
+	TypeDefinition = TypeDefinition.FromSchema(schema)
+	Watch = Watch.FromSchema(schema)
+	Webhook = Webhook.FromSchema(schema)
+	WebhookDelivery = WebhookDelivery.FromSchema(schema)
 }
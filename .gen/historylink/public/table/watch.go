@@ -0,0 +1,81 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/postgres"
+)
+
+var Watch = newWatchTable("public", "watch", "")
+
+type watchTable struct {
+	postgres.Table
+
+	// Columns
+	UserName  postgres.ColumnString
+	RecordID  postgres.ColumnString
+	CreatedAt postgres.ColumnTimestamp
+
+	AllColumns     postgres.ColumnList
+	MutableColumns postgres.ColumnList
+}
+
+type WatchTable struct {
+	watchTable
+
+	EXCLUDED watchTable
+}
+
+// AS creates new WatchTable with assigned alias
+func (a WatchTable) AS(alias string) *WatchTable {
+	return newWatchTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new WatchTable with assigned schema name
+func (a WatchTable) FromSchema(schemaName string) *WatchTable {
+	return newWatchTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new WatchTable with assigned table prefix
+func (a WatchTable) WithPrefix(prefix string) *WatchTable {
+	return newWatchTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new WatchTable with assigned table suffix
+func (a WatchTable) WithSuffix(suffix string) *WatchTable {
+	return newWatchTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newWatchTable(schemaName, tableName, alias string) *WatchTable {
+	return &WatchTable{
+		watchTable: newWatchTableImpl(schemaName, tableName, alias),
+		EXCLUDED:   newWatchTableImpl("", "excluded", ""),
+	}
+}
+
+func newWatchTableImpl(schemaName, tableName, alias string) watchTable {
+	var (
+		UserNameColumn  = postgres.StringColumn("user_name")
+		RecordIDColumn  = postgres.StringColumn("record_id")
+		CreatedAtColumn = postgres.TimestampColumn("created_at")
+		allColumns      = postgres.ColumnList{UserNameColumn, RecordIDColumn, CreatedAtColumn}
+		mutableColumns  = postgres.ColumnList{CreatedAtColumn}
+	)
+
+	return watchTable{
+		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		UserName:  UserNameColumn,
+		RecordID:  RecordIDColumn,
+		CreatedAt: CreatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"historylink/internal/features/analytics"
+	"historylink/internal/features/export"
+	"historylink/internal/features/importer"
+	"historylink/internal/features/record"
+	"historylink/internal/features/wikidata"
+
+	"github.com/spf13/cobra"
+)
+
+func importCommand(connStr string, logger *slog.Logger) *cobra.Command {
+	var format string
+	var chunkSize int
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import records, impacts and links from a CSV or NDJSON file",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			file, err := os.Open(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			defer file.Close()
+
+			if format == "" {
+				format = strings.TrimPrefix(strings.ToLower(filepath.Ext(args[0])), ".")
+			}
+
+			conn := openDatabase(connStr)
+			defer conn.Close()
+
+			is := importer.NewImportResources(conn, logger)
+			report, err := is.ImportService.Import(cmd.Context(), file, importer.ImportOptions{
+				Format:    importer.Format(format),
+				ChunkSize: chunkSize,
+				DryRun:    dryRun,
+			})
+			for _, rowError := range report.Errors {
+				fmt.Fprintln(os.Stderr, rowError.Error())
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("records: %d, impacts: %d, links: %d, chunks committed: %d\n", report.Records, report.Impacts, report.Links, report.Chunks)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "csv or ndjson, taken from the file extension when omitted")
+	cmd.Flags().IntVar(&chunkSize, "chunk-size", 0, "commit every n rows in their own transaction, 0 commits everything at once")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "validate the file without writing anything")
+
+	return cmd
+}
+
+func exportCommand(connStr string, logger *slog.Logger) *cobra.Command {
+	var format string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all records with their impacts, sources and links as NDJSON or CSV",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			w := os.Stdout
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				defer file.Close()
+				w = file
+			}
+
+			conn := openDatabase(connStr)
+			defer conn.Close()
+
+			es := export.NewExportResources(conn, logger)
+			if err := es.ExportService.Export(cmd.Context(), w, export.Format(format)); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "ndjson", "ndjson or csv")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write to instead of stdout")
+
+	return cmd
+}
+
+func wikidataImportCommand(connStr string, logger *slog.Logger) *cobra.Command {
+	var classes []string
+	var properties []string
+	var language string
+	var strength int16
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "wikidata-import <dump>",
+		Short: "Create draft records from a local Wikidata JSON dump or filtered extract (.json, .json.gz or .json.bz2)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			options := wikidata.ImportOptions{
+				Classes:        wikidata.DefaultClasses,
+				LinkProperties: wikidata.DefaultLinkProperties,
+				Language:       language,
+				LinkStrength:   strength,
+				DryRun:         dryRun,
+			}
+			// Record types are stored in the database, so it is opened before
+			// checking the classes mapped onto them
+			conn := openDatabase(connStr)
+			defer conn.Close()
+
+			if len(classes) > 0 {
+				options.Classes = nil
+				for _, class := range classes {
+					qid, recordType, _ := strings.Cut(class, "=")
+					if recordType == "" {
+						recordType = string(record.Event)
+					}
+					if record.Type(recordType).ToInt16() < 0 {
+						fmt.Fprintf(os.Stderr, "invalid record type %q for class %s\n", recordType, qid)
+						os.Exit(1)
+					}
+					options.Classes = append(options.Classes, wikidata.ClassMapping{Class: qid, Type: record.Type(recordType)})
+				}
+			}
+			if cmd.Flags().Changed("link") {
+				options.LinkProperties = properties
+			}
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			defer file.Close()
+
+			var dump io.Reader = file
+			switch filepath.Ext(args[0]) {
+			case ".gz":
+				if dump, err = gzip.NewReader(file); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+			case ".bz2":
+				dump = bzip2.NewReader(file)
+			}
+
+			ws := wikidata.NewWikidataService(wikidata.NewRepository(conn, logger), logger)
+			report, err := ws.Import(cmd.Context(), dump, options)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("scanned: %d, matched: %d, created: %d, already imported: %d, links: %d\n", report.Scanned, report.Matched, report.Created, report.Existing, report.Links)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&classes, "class", nil, "class QID to import with the record type to use, e.g. Q5=person (repeatable, defaults to humans, wars, battles, events, tools and machines)")
+	cmd.Flags().StringSliceVar(&properties, "link", nil, "item properties to turn into links, e.g. P607,P710 (defaults to a set of participation and part-of properties)")
+	cmd.Flags().StringVar(&language, "language", "en", "preferred language of titles and descriptions")
+	cmd.Flags().Int16Var(&strength, "strength", 5, "strength of the created links")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "read the dump and report what would be created without writing anything")
+
+	return cmd
+}
+
+func analyticsCommand(connStr string, logger *slog.Logger) *cobra.Command {
+	var metric string
+	var limit int
+	var communities bool
+
+	cmd := &cobra.Command{
+		Use:   "analytics",
+		Short: "Rank records by centrality in the link graph or list its communities",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			switch analytics.Metric(metric) {
+			case analytics.Degree, analytics.WeightedDegree, analytics.Betweenness, analytics.PageRank:
+			default:
+				fmt.Fprintf(os.Stderr, "invalid metric %q\n", metric)
+				os.Exit(1)
+			}
+
+			conn := openDatabase(connStr)
+			defer conn.Close()
+
+			as := analytics.NewAnalyticsResources(conn, logger)
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			defer w.Flush()
+
+			if communities {
+				response, err := as.AnalyticsService.GetCommunities(cmd.Context(), limit, 5)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+
+				fmt.Fprintf(w, "revision %d, modularity %.4f\n", response.Revision, response.Modularity)
+				fmt.Fprintln(w, "COMMUNITY\tSIZE\tMOST CENTRAL RECORDS")
+				for _, community := range response.Communities {
+					titles := make([]string, len(community.Records))
+					for i, member := range community.Records {
+						titles[i] = member.Title
+					}
+					fmt.Fprintf(w, "%d\t%d\t%s\n", community.ID, community.Size, strings.Join(titles, "; "))
+				}
+				return
+			}
+
+			response, err := as.AnalyticsService.GetCentrality(cmd.Context(), analytics.Metric(metric), limit)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			fmt.Fprintf(w, "revision %d, ranked by %s\n", response.Revision, response.Metric)
+			fmt.Fprintln(w, "ID\tTITLE\tDEGREE\tWEIGHTED\tBETWEENNESS\tPAGERANK\tCOMMUNITY")
+			for _, r := range response.Records {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%.0f\t%.4f\t%.4f\t%d\n", r.RecordID, r.Title, r.Degree, r.WeightedDegree, r.Betweenness, r.PageRank, r.Community)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&metric, "metric", string(analytics.PageRank), "degree, weighted-degree, betweenness or pagerank")
+	cmd.Flags().IntVar(&limit, "limit", 20, "number of records or communities to list")
+	cmd.Flags().BoolVar(&communities, "communities", false, "list communities instead of ranking records")
+
+	return cmd
+}
@@ -1,32 +1,52 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
-
+	"sync"
+	"sync/atomic"
+
+	"historylink/internal/features/analytics"
+	"historylink/internal/features/arc"
+	"historylink/internal/features/citation"
+	"historylink/internal/features/comment"
+	"historylink/internal/features/event"
+	"historylink/internal/features/export"
+	"historylink/internal/features/importer"
 	"historylink/internal/features/link"
+	"historylink/internal/features/notification"
+	"historylink/internal/features/person"
 	"historylink/internal/features/record"
+	"historylink/internal/features/source"
+	"historylink/internal/features/term"
+	"historylink/internal/features/vocabulary"
+	"historylink/internal/features/webhook"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
 	"github.com/danielgtaylor/huma/v2/humacli"
 	_ "github.com/joho/godotenv/autoload"
 	_ "github.com/lib/pq"
+	"golang.org/x/text/language"
 )
 
 type Options struct {
-	Port int `help:"Port to listen on" short:"p" default:"8888"`
+	Port            int     `help:"Port to listen on" short:"p" default:"8888"`
+	BaseURL         string  `help:"Public URL of the API, used to identify records in linked data and permalinks" default:"http://localhost:8888"`
+	InfluenceDecay  float64 `help:"Share of influence passed on per link followed, between 0 and 1" default:"0.5"`
+	DefaultLanguage string  `help:"Language of record titles and descriptions, others are given as labels" default:"en"`
 }
 
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-User")
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
@@ -38,35 +58,107 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+func openDatabase(connStr string) *sql.DB {
+	conn, err := sql.Open("postgres", connStr)
+	if err != nil {
+		panic(err)
+	}
+
+	err = conn.Ping()
+	if err != nil {
+		panic(err)
+	}
+
+	if err = vocabulary.Load(context.Background(), conn); err != nil {
+		panic(err)
+	}
+
+	return conn
+}
+
 func main() {
 	//port := os.Getenv("PORT")
 	connStr := os.Getenv("DATABASE_URL")
 
+	// Configure the logger to include source information
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
+
 	cli := humacli.New(func(hooks humacli.Hooks, options *Options) {
 
 		// Tell the CLI how to start your router.
 		hooks.OnStart(func() {
-			// Create a new router & API
-			router := http.NewServeMux()
-			config := huma.DefaultConfig("history-link", "1.0.0")
-			config.DocsPath = ""
-			api := humago.New(router, config)
-
-			conn, err := sql.Open("postgres", connStr)
+			if options.InfluenceDecay <= 0 || options.InfluenceDecay > 1 {
+				log.Fatalf("influence decay must be between 0 and 1, got %v", options.InfluenceDecay)
+			}
+			defaultLanguage, err := language.Parse(options.DefaultLanguage)
 			if err != nil {
-				panic(err)
+				log.Fatalf("invalid default language %q: %v", options.DefaultLanguage, err)
 			}
 
+			conn := openDatabase(connStr)
 			defer conn.Close()
 
-			err = conn.Ping()
-			if err != nil {
-				panic(err)
+			rs := record.NewRecordResources(conn, logger, options.BaseURL, options.InfluenceDecay, defaultLanguage.String())
+			ls := link.NewLinkResources(conn, logger)
+			is := importer.NewImportResources(conn, logger)
+			es := export.NewExportResources(conn, logger)
+			as := analytics.NewAnalyticsResources(conn, logger)
+			ars := arc.NewArcResources(conn, logger)
+			ps := person.NewPersonResources(conn, logger)
+			ts := term.NewTermResources(conn, logger)
+			ss := source.NewSourceResources(conn, logger)
+			cs := citation.NewCitationResources(conn, logger, options.BaseURL)
+			evs := event.NewEventResources(connStr, logger)
+			whs := webhook.NewWebhookResources(conn, logger)
+			ns := notification.NewNotificationResources(conn, logger)
+			cms := comment.NewCommentResources(conn, logger)
+			go func() {
+				if err := rs.RecordService.RunInfluence(context.Background()); err != nil {
+					logger.Error(err.Error())
+				}
+			}()
+			go func() {
+				if err := evs.EventService.Run(context.Background()); err != nil {
+					logger.Error(err.Error())
+				}
+			}()
+			go func() {
+				if err := whs.WebhookService.Run(context.Background()); err != nil {
+					logger.Error(err.Error())
+				}
+			}()
+
+			vs := vocabulary.NewVocabularyResources(conn, connStr, logger)
+			go func() {
+				if err := vs.VocabularyService.Run(context.Background()); err != nil {
+					logger.Error(err.Error())
+				}
+			}()
+
+			// The schemas and the OpenAPI document list the values of the
+			// vocabularies, so the API is mounted again once they changed
+			type mountedAPI struct {
+				revision int64
+				handler  http.Handler
 			}
-
-			router.HandleFunc("GET /docs", func(w http.ResponseWriter, r *http.Request) {
-				w.Header().Set("Content-Type", "text/html")
-				w.Write([]byte(`<!doctype html>
+			var mounted atomic.Pointer[mountedAPI]
+			var mu sync.Mutex
+			mount := func(revision int64) *mountedAPI {
+				mu.Lock()
+				defer mu.Unlock()
+				if current := mounted.Load(); current != nil && current.revision == revision {
+					return current
+				}
+
+				// Create a new router & API
+				router := http.NewServeMux()
+				config := huma.DefaultConfig("history-link", "1.0.0")
+				config.DocsPath = ""
+				api := humago.New(router, config)
+
+				router.HandleFunc("GET /docs", func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html")
+					w.Write([]byte(`<!doctype html>
 <html>
   <head>
     <title>API Reference</title>
@@ -82,21 +174,45 @@ func main() {
     <script src="https://cdn.jsdelivr.net/npm/@scalar/api-reference"></script>
   </body>
 </html>`))
-			})
-
-			// Configure the logger to include source information
-			logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{AddSource: true}))
-			rs := record.NewRecordResources(conn, logger)
-			ls := link.NewLinkResources(conn, logger)
-			rs.MountRoutes(api)
-			ls.MountRoutes(api)
-
-			corsRouter := corsMiddleware(router)
-
-			http.ListenAndServe(fmt.Sprintf(":%d", options.Port), corsRouter)
+				})
+
+				rs.MountRoutes(api)
+				ls.MountRoutes(api)
+				is.MountRoutes(api)
+				es.MountRoutes(api)
+				as.MountRoutes(api)
+				ars.MountRoutes(api)
+				ps.MountRoutes(api)
+				ts.MountRoutes(api)
+				ss.MountRoutes(api)
+				cs.MountRoutes(api)
+				evs.MountRoutes(api)
+				whs.MountRoutes(api)
+				ns.MountRoutes(api)
+				cms.MountRoutes(api)
+				vs.MountRoutes(api)
+
+				current := &mountedAPI{revision: revision, handler: corsMiddleware(router)}
+				mounted.Store(current)
+				return current
+			}
+			mount(vocabulary.Revision())
+
+			http.ListenAndServe(fmt.Sprintf(":%d", options.Port), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				current := mounted.Load()
+				if revision := vocabulary.Revision(); current.revision != revision {
+					current = mount(revision)
+				}
+				current.handler.ServeHTTP(w, r)
+			}))
 		})
 	})
 
+	cli.Root().AddCommand(importCommand(connStr, logger))
+	cli.Root().AddCommand(exportCommand(connStr, logger))
+	cli.Root().AddCommand(wikidataImportCommand(connStr, logger))
+	cli.Root().AddCommand(analyticsCommand(connStr, logger))
+
 	// Run the CLI. When passed no commands, it starts the server.
 	cli.Run()
 	log.Println("Shutdown...")
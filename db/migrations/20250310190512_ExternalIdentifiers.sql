@@ -0,0 +1,13 @@
+-- migrate:up
+create table external_identifier (
+    id uuid primary key default gen_random_uuid (),
+    record_id uuid not null references record (id) on delete cascade,
+    scheme smallint not null,
+    value varchar(255) not null,
+    unique (scheme, value)
+);
+
+create index idx_external_identifier_record_id on external_identifier (record_id);
+
+-- migrate:down
+drop table external_identifier;
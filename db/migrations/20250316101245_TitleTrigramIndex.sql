@@ -0,0 +1,9 @@
+-- migrate:up
+create extension if not exists pg_trgm;
+
+create index idx_record_title_trgm on record using gin (title gin_trgm_ops);
+
+-- migrate:down
+drop index idx_record_title_trgm;
+
+drop extension if exists pg_trgm;
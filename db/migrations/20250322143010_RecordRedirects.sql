@@ -0,0 +1,11 @@
+-- migrate:up
+create table record_redirect (
+    old_record_id uuid primary key references record (id) on delete cascade,
+    record_id uuid not null references record (id) on delete cascade,
+    merged_at timestamp not null default now()
+);
+
+create index idx_record_redirect_record_id on record_redirect (record_id);
+
+-- migrate:down
+drop table record_redirect;
@@ -0,0 +1,24 @@
+-- migrate:up
+create table graph_revision (
+    id boolean primary key default true check (id),
+    revision bigint not null default 0
+);
+
+insert into graph_revision default values;
+
+CREATE OR REPLACE FUNCTION bump_graph_revision() RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE graph_revision SET revision = revision + 1;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tr_link_graph_revision
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON link
+FOR EACH STATEMENT EXECUTE FUNCTION bump_graph_revision();
+
+-- migrate:down
+DROP TRIGGER tr_link_graph_revision ON link;
+DROP FUNCTION bump_graph_revision();
+
+drop table graph_revision;
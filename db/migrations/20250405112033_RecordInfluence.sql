@@ -0,0 +1,30 @@
+-- migrate:up
+alter table graph_revision
+    add column influence_revision bigint,
+    add column influence_decay double precision;
+
+create table record_influence (
+    record_id uuid primary key references record(id) on delete cascade,
+    influence double precision not null
+);
+
+create index idx_record_influence_influence on record_influence (influence);
+
+CREATE TRIGGER tr_impact_graph_revision
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON impact
+FOR EACH STATEMENT EXECUTE FUNCTION bump_graph_revision();
+
+CREATE TRIGGER tr_record_status_graph_revision
+AFTER UPDATE OF status ON record
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+EXECUTE FUNCTION bump_graph_revision();
+
+-- migrate:down
+DROP TRIGGER tr_record_status_graph_revision ON record;
+DROP TRIGGER tr_impact_graph_revision ON impact;
+
+drop table record_influence;
+
+alter table graph_revision
+    drop column influence_revision,
+    drop column influence_decay;
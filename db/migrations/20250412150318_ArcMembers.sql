@@ -0,0 +1,16 @@
+-- migrate:up
+create table arc_member (
+    arc_id uuid not null references record (id) on delete cascade,
+    record_id uuid not null references record (id) on delete cascade,
+    position integer not null,
+    chapter character varying(255),
+    role character varying(255),
+    primary key (arc_id, record_id),
+    unique (arc_id, position),
+    check (arc_id <> record_id)
+);
+
+create index idx_arc_member_record_id on arc_member (record_id);
+
+-- migrate:down
+drop table arc_member;
@@ -0,0 +1,41 @@
+-- migrate:up
+create table person_profile (
+    record_id uuid primary key references record (id) on delete cascade,
+    birth_date timestamp,
+    birth_place character varying(255),
+    death_date timestamp,
+    death_place character varying(255)
+);
+
+create table person_name (
+    id uuid primary key default gen_random_uuid(),
+    record_id uuid not null references person_profile (record_id) on delete cascade,
+    name character varying(255) not null,
+    unique (record_id, name)
+);
+
+create table person_occupation (
+    id uuid primary key default gen_random_uuid(),
+    record_id uuid not null references person_profile (record_id) on delete cascade,
+    occupation character varying(255) not null,
+    unique (record_id, occupation)
+);
+
+create table involvement (
+    id uuid primary key default gen_random_uuid(),
+    person_id uuid not null references record (id) on delete cascade,
+    record_id uuid not null references record (id) on delete cascade,
+    role smallint not null,
+    start_date timestamp,
+    end_date timestamp,
+    unique (person_id, record_id, role),
+    check (person_id <> record_id)
+);
+
+create index idx_involvement_record_id on involvement (record_id);
+
+-- migrate:down
+drop table involvement;
+drop table person_occupation;
+drop table person_name;
+drop table person_profile;
@@ -0,0 +1,24 @@
+-- migrate:up
+create table record_label (
+    id uuid primary key default gen_random_uuid(),
+    record_id uuid not null references record (id) on delete cascade,
+    language character varying(35) not null,
+    title character varying(255) not null,
+    description character varying(255),
+    unique (record_id, language)
+);
+
+create table record_alias (
+    id uuid primary key default gen_random_uuid(),
+    record_id uuid not null references record (id) on delete cascade,
+    name character varying(255) not null,
+    language character varying(35),
+    unique (record_id, name)
+);
+
+create index idx_record_label_title_trgm on record_label using gin (title gin_trgm_ops);
+create index idx_record_alias_name_trgm on record_alias using gin (name gin_trgm_ops);
+
+-- migrate:down
+drop table record_alias;
+drop table record_label;
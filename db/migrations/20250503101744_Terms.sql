@@ -0,0 +1,24 @@
+-- migrate:up
+create table term (
+    id uuid primary key default gen_random_uuid(),
+    kind smallint not null,
+    name character varying(255) not null,
+    description character varying(255),
+    parent_id uuid references term (id),
+    unique (kind, name),
+    check (parent_id <> id)
+);
+
+create index idx_term_parent_id on term (parent_id);
+
+create table record_term (
+    record_id uuid not null references record (id) on delete cascade,
+    term_id uuid not null references term (id) on delete cascade,
+    primary key (record_id, term_id)
+);
+
+create index idx_record_term_term_id on record_term (term_id);
+
+-- migrate:down
+drop table record_term;
+drop table term;
@@ -0,0 +1,50 @@
+-- migrate:up
+create table category_definition (
+    id smallint primary key,
+    name character varying(50) not null unique,
+    builtin boolean not null default false
+);
+
+create table type_definition (
+    id smallint primary key,
+    name character varying(50) not null unique,
+    builtin boolean not null default false
+);
+
+create table status_definition (
+    id smallint primary key,
+    name character varying(50) not null unique,
+    builtin boolean not null default false
+);
+
+insert into category_definition (id, name, builtin) values
+    (0, 'political', true),
+    (1, 'social', true),
+    (2, 'economic', true),
+    (3, 'cultural', true),
+    (4, 'tech', true);
+
+insert into type_definition (id, name, builtin) values
+    (0, 'arc', true),
+    (1, 'event', true),
+    (2, 'person', true),
+    (3, 'object', true);
+
+insert into status_definition (id, name, builtin) values
+    (0, 'removed', true),
+    (1, 'draft', true),
+    (2, 'pending', true),
+    (3, 'reviewed', true);
+
+alter table impact add constraint impact_category_fkey foreign key (category) references category_definition (id);
+alter table record add constraint record_type_fkey foreign key (type) references type_definition (id);
+alter table record add constraint record_status_fkey foreign key (status) references status_definition (id);
+
+-- migrate:down
+alter table record drop constraint record_status_fkey;
+alter table record drop constraint record_type_fkey;
+alter table impact drop constraint impact_category_fkey;
+
+drop table status_definition;
+drop table type_definition;
+drop table category_definition;
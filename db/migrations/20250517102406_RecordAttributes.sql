@@ -0,0 +1,11 @@
+-- migrate:up
+alter table record add column attributes jsonb not null default '{}'::jsonb;
+alter table type_definition add column attribute_schema jsonb;
+
+create index idx_record_attributes on record using gin (attributes jsonb_path_ops);
+
+-- migrate:down
+drop index idx_record_attributes;
+
+alter table type_definition drop column attribute_schema;
+alter table record drop column attributes;
@@ -0,0 +1,18 @@
+-- migrate:up
+create table claim (
+    id uuid primary key default gen_random_uuid(),
+    source_id uuid not null references source (id) on delete cascade,
+    field character varying(50),
+    impact_id uuid references impact (id) on delete cascade,
+    confidence smallint not null,
+    page character varying(50),
+    quote character varying(1000),
+    created_at timestamp without time zone not null default now(),
+    check ((field is null) <> (impact_id is null))
+);
+
+create index idx_claim_source_id on claim (source_id);
+create index idx_claim_impact_id on claim (impact_id);
+
+-- migrate:down
+drop table claim;
@@ -0,0 +1,29 @@
+-- migrate:up
+alter table record add column confidence smallint;
+alter table impact add column confidence smallint;
+alter table link add column confidence smallint;
+
+create table alternative_value (
+    id uuid primary key default gen_random_uuid(),
+    record_id uuid not null references record (id) on delete cascade,
+    field character varying(50) not null,
+    value character varying(255) not null,
+    created_at timestamp without time zone not null default now(),
+    unique (record_id, field, value)
+);
+
+alter table claim
+    add column alternative_id uuid references alternative_value (id) on delete cascade,
+    add constraint claim_alternative_check check (alternative_id is null or field is not null);
+
+create index idx_claim_alternative_id on claim (alternative_id);
+
+-- migrate:down
+drop index idx_claim_alternative_id;
+alter table claim
+    drop constraint claim_alternative_check,
+    drop column alternative_id;
+drop table alternative_value;
+alter table link drop column confidence;
+alter table impact drop column confidence;
+alter table record drop column confidence;
@@ -0,0 +1,13 @@
+-- migrate:up
+alter table source
+    add column authors jsonb not null default '[]',
+    add column year smallint,
+    add column publisher character varying(255),
+    add column container_title character varying(255);
+
+-- migrate:down
+alter table source
+    drop column container_title,
+    drop column publisher,
+    drop column year,
+    drop column authors;
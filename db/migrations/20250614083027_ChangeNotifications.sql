@@ -0,0 +1,40 @@
+-- migrate:up
+CREATE OR REPLACE FUNCTION notify_change() RETURNS TRIGGER AS $$
+DECLARE
+  changed jsonb;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := to_jsonb(OLD);
+  ELSE
+    changed := to_jsonb(NEW);
+  END IF;
+
+  -- Only ids are sent, as payloads are limited to 8000 bytes
+  PERFORM pg_notify('change', jsonb_build_object(
+    'entity', TG_TABLE_NAME,
+    'action', CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END,
+    'id', changed->'id',
+    'recordId', changed->'record_id',
+    'recordId2', changed->'record_id2'
+  )::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tr_record_notify
+AFTER INSERT OR UPDATE OR DELETE ON record
+FOR EACH ROW EXECUTE FUNCTION notify_change();
+
+CREATE TRIGGER tr_impact_notify
+AFTER INSERT OR UPDATE OR DELETE ON impact
+FOR EACH ROW EXECUTE FUNCTION notify_change();
+
+CREATE TRIGGER tr_link_notify
+AFTER INSERT OR UPDATE OR DELETE ON link
+FOR EACH ROW EXECUTE FUNCTION notify_change();
+
+-- migrate:down
+DROP TRIGGER tr_link_notify ON link;
+DROP TRIGGER tr_impact_notify ON impact;
+DROP TRIGGER tr_record_notify ON record;
+DROP FUNCTION notify_change();
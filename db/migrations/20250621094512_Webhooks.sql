@@ -0,0 +1,83 @@
+-- migrate:up
+create table webhook (
+    id uuid primary key default gen_random_uuid(),
+    url character varying(2048) not null,
+    event_types jsonb not null default '[]',
+    secret character varying(255) not null,
+    active boolean not null default true,
+    created_at timestamp without time zone not null default now()
+);
+
+-- Changes are written to the outbox in the transaction making them, and
+-- handed to the webhooks subscribed to them afterwards. clock_timestamp keeps
+-- changes made in one transaction in order.
+create table outbox (
+    id uuid primary key default gen_random_uuid(),
+    event_type character varying(50) not null,
+    payload jsonb not null,
+    created_at timestamp without time zone not null default clock_timestamp(),
+    dispatched_at timestamp without time zone
+);
+
+create index idx_outbox_pending on outbox (created_at) where dispatched_at is null;
+
+create table webhook_delivery (
+    id uuid primary key default gen_random_uuid(),
+    webhook_id uuid not null references webhook (id) on delete cascade,
+    outbox_id uuid not null references outbox (id) on delete cascade,
+    status smallint not null default 0,
+    attempts smallint not null default 0,
+    next_attempt_at timestamp without time zone not null default now(),
+    response_code smallint,
+    error character varying(1000),
+    created_at timestamp without time zone not null default now(),
+    updated_at timestamp without time zone not null default now(),
+    unique (webhook_id, outbox_id)
+);
+
+create index idx_webhook_delivery_due on webhook_delivery (next_attempt_at) where status = 0;
+create index idx_webhook_delivery_webhook_id on webhook_delivery (webhook_id, created_at);
+
+CREATE OR REPLACE FUNCTION write_outbox() RETURNS TRIGGER AS $$
+DECLARE
+  changed jsonb;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := to_jsonb(OLD);
+  ELSE
+    changed := to_jsonb(NEW);
+  END IF;
+
+  INSERT INTO outbox (event_type, payload)
+  VALUES (
+    TG_TABLE_NAME || '.' || CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END,
+    jsonb_strip_nulls(jsonb_build_object(
+      'id', changed->'id',
+      'recordId', changed->'record_id',
+      'recordId2', changed->'record_id2'
+    ))
+  );
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tr_record_outbox
+AFTER INSERT OR UPDATE OR DELETE ON record
+FOR EACH ROW EXECUTE FUNCTION write_outbox();
+
+CREATE TRIGGER tr_impact_outbox
+AFTER INSERT OR UPDATE OR DELETE ON impact
+FOR EACH ROW EXECUTE FUNCTION write_outbox();
+
+CREATE TRIGGER tr_link_outbox
+AFTER INSERT OR UPDATE OR DELETE ON link
+FOR EACH ROW EXECUTE FUNCTION write_outbox();
+
+-- migrate:down
+DROP TRIGGER tr_link_outbox ON link;
+DROP TRIGGER tr_impact_outbox ON impact;
+DROP TRIGGER tr_record_outbox ON record;
+DROP FUNCTION write_outbox();
+drop table webhook_delivery;
+drop table outbox;
+drop table webhook;
@@ -0,0 +1,125 @@
+-- migrate:up
+-- Users are known by the name the authenticating proxy passes on
+create table watch (
+    user_name character varying(255) not null,
+    record_id uuid not null references record (id) on delete cascade,
+    created_at timestamp without time zone not null default now(),
+    primary key (user_name, record_id)
+);
+
+create index idx_watch_record_id on watch (record_id);
+
+create table notification (
+    id uuid primary key default gen_random_uuid(),
+    user_name character varying(255) not null,
+    record_id uuid not null references record (id) on delete cascade,
+    kind smallint not null,
+    related_record_id uuid,
+    created_at timestamp without time zone not null default clock_timestamp(),
+    read_at timestamp without time zone
+);
+
+create index idx_notification_user_name on notification (user_name, created_at);
+create index idx_notification_unread on notification (user_name) where read_at is null;
+
+-- Changes to a record are picked up from its history, so every revision
+-- tells its watchers
+CREATE OR REPLACE FUNCTION notify_record_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  previous_status smallint;
+  kind smallint := 0;
+BEGIN
+  SELECT status INTO previous_status
+  FROM record_history
+  WHERE record_id = NEW.record_id AND id <> NEW.id
+  ORDER BY updated_at DESC, id DESC
+  LIMIT 1;
+
+  -- Records are submitted for review by making them pending
+  IF NEW.status = (SELECT id FROM status_definition WHERE name = 'pending')
+     AND previous_status IS DISTINCT FROM NEW.status THEN
+    kind := 2;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, kind
+  FROM watch w
+  WHERE w.record_id = NEW.record_id;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_impact_watchers() RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, 0
+  FROM watch w
+  WHERE w.record_id = NEW.record_id;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_link_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  changed public.link;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind, related_record_id)
+  SELECT w.user_name, w.record_id, 1,
+         CASE WHEN w.record_id = changed.record_id THEN changed.record_id2 ELSE changed.record_id END
+  FROM watch w
+  WHERE w.record_id IN (changed.record_id, changed.record_id2);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_arc_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  changed public.arc_member;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind, related_record_id)
+  SELECT w.user_name, changed.arc_id, 1, changed.record_id
+  FROM watch w
+  WHERE w.record_id = changed.arc_id;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tr_record_history_watchers
+AFTER INSERT ON record_history
+FOR EACH ROW EXECUTE FUNCTION notify_record_watchers();
+
+CREATE TRIGGER tr_impact_history_watchers
+AFTER INSERT ON impact_history
+FOR EACH ROW EXECUTE FUNCTION notify_impact_watchers();
+
+CREATE TRIGGER tr_link_watchers
+AFTER INSERT OR UPDATE OR DELETE ON link
+FOR EACH ROW EXECUTE FUNCTION notify_link_watchers();
+
+CREATE TRIGGER tr_arc_member_watchers
+AFTER INSERT OR DELETE ON arc_member
+FOR EACH ROW EXECUTE FUNCTION notify_arc_watchers();
+
+-- migrate:down
+DROP TRIGGER tr_arc_member_watchers ON arc_member;
+DROP TRIGGER tr_link_watchers ON link;
+DROP TRIGGER tr_impact_history_watchers ON impact_history;
+DROP TRIGGER tr_record_history_watchers ON record_history;
+DROP FUNCTION notify_arc_watchers();
+DROP FUNCTION notify_link_watchers();
+DROP FUNCTION notify_impact_watchers();
+DROP FUNCTION notify_record_watchers();
+drop table notification;
+drop table watch;
@@ -0,0 +1,46 @@
+-- migrate:up
+-- Comments are on either a record or a link. Replies belong to the comment
+-- starting their thread, which is the one resolved.
+create table comment (
+    id uuid primary key default gen_random_uuid(),
+    record_id uuid references record (id) on delete cascade,
+    link_id uuid references link (id) on delete cascade,
+    parent_id uuid references comment (id) on delete cascade,
+    author character varying(255) not null,
+    body character varying(4000) not null,
+    resolved_at timestamp without time zone,
+    resolved_by character varying(255),
+    created_at timestamp without time zone not null default now(),
+    updated_at timestamp without time zone not null default now(),
+    check ((record_id is null) <> (link_id is null)),
+    check (parent_id <> id)
+);
+
+create index idx_comment_record_id on comment (record_id, created_at);
+create index idx_comment_link_id on comment (link_id, created_at);
+create index idx_comment_parent_id on comment (parent_id);
+
+-- Earlier bodies of edited comments, each with the time it was written
+create table comment_revision (
+    id uuid primary key default gen_random_uuid(),
+    comment_id uuid not null references comment (id) on delete cascade,
+    body character varying(4000) not null,
+    created_at timestamp without time zone not null
+);
+
+create index idx_comment_revision_comment_id on comment_revision (comment_id, created_at);
+
+create table comment_mention (
+    comment_id uuid not null references comment (id) on delete cascade,
+    user_name character varying(255) not null,
+    primary key (comment_id, user_name)
+);
+
+alter table notification
+    add column comment_id uuid references comment (id) on delete cascade;
+
+-- migrate:down
+alter table notification drop column comment_id;
+drop table comment_mention;
+drop table comment_revision;
+drop table comment;
@@ -0,0 +1,45 @@
+-- migrate:up
+-- Impacts and statuses only feed the influence scores, so they get a counter
+-- of their own and leave the revision of the link graph, which keys the
+-- cached graph analysis, to link changes
+alter table graph_revision
+    add column influence_inputs_revision bigint not null default 0,
+    add column influence_computed_inputs_revision bigint;
+
+CREATE OR REPLACE FUNCTION bump_influence_inputs_revision() RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE graph_revision SET influence_inputs_revision = influence_inputs_revision + 1;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER tr_record_status_graph_revision ON record;
+DROP TRIGGER tr_impact_graph_revision ON impact;
+
+CREATE TRIGGER tr_impact_influence_inputs_revision
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON impact
+FOR EACH STATEMENT EXECUTE FUNCTION bump_influence_inputs_revision();
+
+CREATE TRIGGER tr_record_status_influence_inputs_revision
+AFTER UPDATE OF status ON record
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+EXECUTE FUNCTION bump_influence_inputs_revision();
+
+-- migrate:down
+DROP TRIGGER tr_record_status_influence_inputs_revision ON record;
+DROP TRIGGER tr_impact_influence_inputs_revision ON impact;
+
+CREATE TRIGGER tr_impact_graph_revision
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON impact
+FOR EACH STATEMENT EXECUTE FUNCTION bump_graph_revision();
+
+CREATE TRIGGER tr_record_status_graph_revision
+AFTER UPDATE OF status ON record
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+EXECUTE FUNCTION bump_graph_revision();
+
+DROP FUNCTION bump_influence_inputs_revision();
+
+alter table graph_revision
+    drop column influence_inputs_revision,
+    drop column influence_computed_inputs_revision;
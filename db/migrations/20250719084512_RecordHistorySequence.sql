@@ -0,0 +1,77 @@
+-- migrate:up
+-- Revisions written in the same transaction share their timestamps, so they
+-- are ordered by the sequence they were written in instead. Revisions written
+-- before keep the order they were read in until now.
+alter table record_history add column seq bigint;
+
+update record_history h
+set seq = o.seq
+from (
+    select id, row_number() over (order by updated_at, id) as seq
+    from record_history
+) o
+where h.id = o.id;
+
+alter table record_history
+    alter column seq set not null,
+    alter column seq add generated always as identity;
+
+select setval(pg_get_serial_sequence('record_history', 'seq'), coalesce(max(seq), 0) + 1, false)
+from record_history;
+
+create unique index idx_record_history_record_id_seq on record_history (record_id, seq);
+
+CREATE OR REPLACE FUNCTION notify_record_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  previous_status smallint;
+  kind smallint := 0;
+BEGIN
+  SELECT status INTO previous_status
+  FROM record_history
+  WHERE record_id = NEW.record_id AND seq < NEW.seq
+  ORDER BY seq DESC
+  LIMIT 1;
+
+  -- Records are submitted for review by making them pending
+  IF NEW.status = (SELECT id FROM status_definition WHERE name = 'pending')
+     AND previous_status IS DISTINCT FROM NEW.status THEN
+    kind := 2;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, kind
+  FROM watch w
+  WHERE w.record_id = NEW.record_id;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+CREATE OR REPLACE FUNCTION notify_record_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  previous_status smallint;
+  kind smallint := 0;
+BEGIN
+  SELECT status INTO previous_status
+  FROM record_history
+  WHERE record_id = NEW.record_id AND id <> NEW.id
+  ORDER BY updated_at DESC, id DESC
+  LIMIT 1;
+
+  -- Records are submitted for review by making them pending
+  IF NEW.status = (SELECT id FROM status_definition WHERE name = 'pending')
+     AND previous_status IS DISTINCT FROM NEW.status THEN
+    kind := 2;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, kind
+  FROM watch w
+  WHERE w.record_id = NEW.record_id;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+drop index idx_record_history_record_id_seq;
+
+alter table record_history drop column seq;
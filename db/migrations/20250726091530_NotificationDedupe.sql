@@ -0,0 +1,174 @@
+-- migrate:up
+-- A single change writes several rows watchers hear of, such as a record and
+-- each of its impacts, so watchers get one notification per record and
+-- transaction. Being submitted for review outranks being changed, which
+-- outranks gaining a link. Notifications written before keep no transaction.
+alter table notification add column txid bigint;
+alter table notification alter column txid set default txid_current();
+
+create unique index idx_notification_user_name_record_id_txid on notification (user_name, record_id, txid)
+where comment_id is null;
+
+-- Writes name the user making them in the historylink.actor setting, who is
+-- not notified of their own changes
+
+CREATE OR REPLACE FUNCTION notify_record_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  previous_status smallint;
+  kind smallint := 0;
+BEGIN
+  SELECT status INTO previous_status
+  FROM record_history
+  WHERE record_id = NEW.record_id AND seq < NEW.seq
+  ORDER BY seq DESC
+  LIMIT 1;
+
+  -- Records are submitted for review by making them pending
+  IF NEW.status = (SELECT id FROM status_definition WHERE name = 'pending')
+     AND previous_status IS DISTINCT FROM NEW.status THEN
+    kind := 2;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, kind
+  FROM watch w
+  WHERE w.record_id = NEW.record_id
+    AND w.user_name IS DISTINCT FROM nullif(current_setting('historylink.actor', true), '')
+  ON CONFLICT (user_name, record_id, txid) WHERE comment_id IS NULL DO UPDATE
+  SET kind = EXCLUDED.kind, related_record_id = NULL
+  WHERE EXCLUDED.kind = 2 OR (EXCLUDED.kind = 0 AND notification.kind = 1);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_impact_watchers() RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, 0
+  FROM watch w
+  WHERE w.record_id = NEW.record_id
+    AND w.user_name IS DISTINCT FROM nullif(current_setting('historylink.actor', true), '')
+  ON CONFLICT (user_name, record_id, txid) WHERE comment_id IS NULL DO UPDATE
+  SET kind = EXCLUDED.kind, related_record_id = NULL
+  WHERE notification.kind = 1;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_link_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  changed public.link;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind, related_record_id)
+  SELECT w.user_name, w.record_id, 1,
+         CASE WHEN w.record_id = changed.record_id THEN changed.record_id2 ELSE changed.record_id END
+  FROM watch w
+  WHERE w.record_id IN (changed.record_id, changed.record_id2)
+    AND w.user_name IS DISTINCT FROM nullif(current_setting('historylink.actor', true), '')
+  ON CONFLICT (user_name, record_id, txid) WHERE comment_id IS NULL DO NOTHING;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_arc_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  changed public.arc_member;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind, related_record_id)
+  SELECT w.user_name, changed.arc_id, 1, changed.record_id
+  FROM watch w
+  WHERE w.record_id = changed.arc_id
+    AND w.user_name IS DISTINCT FROM nullif(current_setting('historylink.actor', true), '')
+  ON CONFLICT (user_name, record_id, txid) WHERE comment_id IS NULL DO NOTHING;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+CREATE OR REPLACE FUNCTION notify_record_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  previous_status smallint;
+  kind smallint := 0;
+BEGIN
+  SELECT status INTO previous_status
+  FROM record_history
+  WHERE record_id = NEW.record_id AND seq < NEW.seq
+  ORDER BY seq DESC
+  LIMIT 1;
+
+  -- Records are submitted for review by making them pending
+  IF NEW.status = (SELECT id FROM status_definition WHERE name = 'pending')
+     AND previous_status IS DISTINCT FROM NEW.status THEN
+    kind := 2;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, kind
+  FROM watch w
+  WHERE w.record_id = NEW.record_id;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_impact_watchers() RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, 0
+  FROM watch w
+  WHERE w.record_id = NEW.record_id;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_link_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  changed public.link;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind, related_record_id)
+  SELECT w.user_name, w.record_id, 1,
+         CASE WHEN w.record_id = changed.record_id THEN changed.record_id2 ELSE changed.record_id END
+  FROM watch w
+  WHERE w.record_id IN (changed.record_id, changed.record_id2);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_arc_watchers() RETURNS TRIGGER AS $$
+DECLARE
+  changed public.arc_member;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind, related_record_id)
+  SELECT w.user_name, changed.arc_id, 1, changed.record_id
+  FROM watch w
+  WHERE w.record_id = changed.arc_id;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+drop index idx_notification_user_name_record_id_txid;
+
+alter table notification drop column txid;
@@ -0,0 +1,30 @@
+-- migrate:up
+-- Codes of deleted values live on in record and impact history, so new
+-- values take codes from a sequence instead of reusing the highest one. The
+-- sequences start after every code history still holds.
+alter table category_definition alter column id add generated by default as identity;
+alter table type_definition alter column id add generated by default as identity;
+alter table status_definition alter column id add generated by default as identity;
+
+select setval(pg_get_serial_sequence('category_definition', 'id'), greatest(
+    (select max(id) from category_definition),
+    (select max(category) from impact_history),
+    (select max(category) from impact)
+));
+
+select setval(pg_get_serial_sequence('type_definition', 'id'), greatest(
+    (select max(id) from type_definition),
+    (select max(type) from record_history),
+    (select max(type) from record)
+));
+
+select setval(pg_get_serial_sequence('status_definition', 'id'), greatest(
+    (select max(id) from status_definition),
+    (select max(status) from record_history),
+    (select max(status) from record)
+));
+
+-- migrate:down
+alter table category_definition alter column id drop identity;
+alter table type_definition alter column id drop identity;
+alter table status_definition alter column id drop identity;
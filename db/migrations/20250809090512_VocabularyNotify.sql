@@ -0,0 +1,28 @@
+-- migrate:up
+-- Every instance of the API keeps the vocabularies in memory, so changes are
+-- announced for all of them to load the values again.
+CREATE OR REPLACE FUNCTION notify_vocabulary_change() RETURNS TRIGGER AS $$
+BEGIN
+  PERFORM pg_notify('vocabulary', TG_TABLE_NAME);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+create trigger tr_category_definition_notify
+    after insert or update or delete or truncate on category_definition
+    for each statement execute function notify_vocabulary_change();
+
+create trigger tr_type_definition_notify
+    after insert or update or delete or truncate on type_definition
+    for each statement execute function notify_vocabulary_change();
+
+create trigger tr_status_definition_notify
+    after insert or update or delete or truncate on status_definition
+    for each statement execute function notify_vocabulary_change();
+
+-- migrate:down
+drop trigger tr_status_definition_notify on status_definition;
+drop trigger tr_type_definition_notify on type_definition;
+drop trigger tr_category_definition_notify on category_definition;
+
+drop function notify_vocabulary_change();
@@ -0,0 +1,45 @@
+-- migrate:up
+-- Writes to links, impacts and statuses append a change instead of bumping the
+-- graph_revision row, so concurrent writes no longer wait on each other for
+-- its lock. The revisions are the counters in graph_revision plus the changes
+-- not yet folded into them.
+create table graph_change (
+    id bigint generated always as identity primary key,
+    link_changes bigint not null default 0,
+    influence_input_changes bigint not null default 0
+);
+
+CREATE OR REPLACE FUNCTION bump_graph_revision() RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO graph_change (link_changes) VALUES (1);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION bump_influence_inputs_revision() RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO graph_change (influence_input_changes) VALUES (1);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+CREATE OR REPLACE FUNCTION bump_graph_revision() RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE graph_revision SET revision = revision + 1;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION bump_influence_inputs_revision() RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE graph_revision SET influence_inputs_revision = influence_inputs_revision + 1;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+update graph_revision set
+    revision = revision + (select coalesce(sum(link_changes), 0) from graph_change),
+    influence_inputs_revision = influence_inputs_revision + (select coalesce(sum(influence_input_changes), 0) from graph_change);
+
+drop table graph_change;
@@ -9,6 +9,203 @@ SET xmloption = content;
 SET client_min_messages = warning;
 SET row_security = off;
 
+--
+-- Name: pg_trgm; Type: EXTENSION; Schema: -; Owner: -
+--
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+
+--
+-- Name: EXTENSION pg_trgm; Type: COMMENT; Schema: -; Owner: -
+--
+
+COMMENT ON EXTENSION pg_trgm IS 'text similarity measurement and index searching based on trigrams';
+
+
+--
+-- Name: bump_graph_revision(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.bump_graph_revision() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO graph_change (link_changes) VALUES (1);
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: bump_influence_inputs_revision(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.bump_influence_inputs_revision() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO graph_change (influence_input_changes) VALUES (1);
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: notify_arc_watchers(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.notify_arc_watchers() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+  changed public.arc_member;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind, related_record_id)
+  SELECT w.user_name, changed.arc_id, 1, changed.record_id
+  FROM watch w
+  WHERE w.record_id = changed.arc_id
+    AND w.user_name IS DISTINCT FROM nullif(current_setting('historylink.actor', true), '')
+  ON CONFLICT (user_name, record_id, txid) WHERE comment_id IS NULL DO NOTHING;
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: notify_change(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.notify_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+  changed jsonb;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := to_jsonb(OLD);
+  ELSE
+    changed := to_jsonb(NEW);
+  END IF;
+
+  -- Only ids are sent, as payloads are limited to 8000 bytes
+  PERFORM pg_notify('change', jsonb_build_object(
+    'entity', TG_TABLE_NAME,
+    'action', CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END,
+    'id', changed->'id',
+    'recordId', changed->'record_id',
+    'recordId2', changed->'record_id2'
+  )::text);
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: notify_impact_watchers(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.notify_impact_watchers() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, 0
+  FROM watch w
+  WHERE w.record_id = NEW.record_id
+    AND w.user_name IS DISTINCT FROM nullif(current_setting('historylink.actor', true), '')
+  ON CONFLICT (user_name, record_id, txid) WHERE comment_id IS NULL DO UPDATE
+  SET kind = EXCLUDED.kind, related_record_id = NULL
+  WHERE notification.kind = 1;
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: notify_link_watchers(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.notify_link_watchers() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+  changed public.link;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := OLD;
+  ELSE
+    changed := NEW;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind, related_record_id)
+  SELECT w.user_name, w.record_id, 1,
+         CASE WHEN w.record_id = changed.record_id THEN changed.record_id2 ELSE changed.record_id END
+  FROM watch w
+  WHERE w.record_id IN (changed.record_id, changed.record_id2)
+    AND w.user_name IS DISTINCT FROM nullif(current_setting('historylink.actor', true), '')
+  ON CONFLICT (user_name, record_id, txid) WHERE comment_id IS NULL DO NOTHING;
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: notify_record_watchers(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.notify_record_watchers() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+  previous_status smallint;
+  kind smallint := 0;
+BEGIN
+  SELECT status INTO previous_status
+  FROM record_history
+  WHERE record_id = NEW.record_id AND seq < NEW.seq
+  ORDER BY seq DESC
+  LIMIT 1;
+
+  -- Records are submitted for review by making them pending
+  IF NEW.status = (SELECT id FROM status_definition WHERE name = 'pending')
+     AND previous_status IS DISTINCT FROM NEW.status THEN
+    kind := 2;
+  END IF;
+
+  INSERT INTO notification (user_name, record_id, kind)
+  SELECT w.user_name, NEW.record_id, kind
+  FROM watch w
+  WHERE w.record_id = NEW.record_id
+    AND w.user_name IS DISTINCT FROM nullif(current_setting('historylink.actor', true), '')
+  ON CONFLICT (user_name, record_id, txid) WHERE comment_id IS NULL DO UPDATE
+  SET kind = EXCLUDED.kind, related_record_id = NULL
+  WHERE EXCLUDED.kind = 2 OR (EXCLUDED.kind = 0 AND notification.kind = 1);
+  RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: notify_vocabulary_change(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.notify_vocabulary_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  PERFORM pg_notify('vocabulary', TG_TABLE_NAME);
+  RETURN NULL;
+END;
+$$;
+
+
 --
 -- Name: update_impact_history(); Type: FUNCTION; Schema: public; Owner: -
 --
@@ -103,206 +300,1386 @@ END;
 $$;
 
 
+--
+-- Name: write_outbox(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.write_outbox() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+  changed jsonb;
+BEGIN
+  IF (TG_OP = 'DELETE') THEN
+    changed := to_jsonb(OLD);
+  ELSE
+    changed := to_jsonb(NEW);
+  END IF;
+
+  INSERT INTO outbox (event_type, payload)
+  VALUES (
+    TG_TABLE_NAME || '.' || CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END,
+    jsonb_strip_nulls(jsonb_build_object(
+      'id', changed->'id',
+      'recordId', changed->'record_id',
+      'recordId2', changed->'record_id2'
+    ))
+  );
+  RETURN NULL;
+END;
+$$;
+
+
 SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
 --
--- Name: impact; Type: TABLE; Schema: public; Owner: -
+-- Name: alternative_value; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.impact (
+CREATE TABLE public.alternative_value (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
     record_id uuid NOT NULL,
-    description character varying(255) NOT NULL,
-    value smallint NOT NULL,
-    category smallint NOT NULL
+    field character varying(50) NOT NULL,
+    value character varying(255) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
 );
 
 
 --
--- Name: impact_history; Type: TABLE; Schema: public; Owner: -
+-- Name: arc_member; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.impact_history (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    impact_id uuid,
-    record_id uuid,
-    description character varying(255) NOT NULL,
-    value smallint NOT NULL,
-    category smallint NOT NULL,
-    created_at timestamp without time zone DEFAULT now() NOT NULL,
-    updated_at timestamp without time zone DEFAULT now() NOT NULL
+CREATE TABLE public.arc_member (
+    arc_id uuid NOT NULL,
+    record_id uuid NOT NULL,
+    "position" integer NOT NULL,
+    chapter character varying(255),
+    role character varying(255),
+    CONSTRAINT arc_member_check CHECK ((arc_id <> record_id))
 );
 
 
 --
--- Name: link; Type: TABLE; Schema: public; Owner: -
+-- Name: category_definition; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.link (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    record_id uuid NOT NULL,
-    record_id2 uuid NOT NULL,
-    strength smallint NOT NULL
+CREATE TABLE public.category_definition (
+    id smallint NOT NULL,
+    name character varying(50) NOT NULL,
+    builtin boolean DEFAULT false NOT NULL
 );
 
 
 --
--- Name: record; Type: TABLE; Schema: public; Owner: -
+-- Name: category_definition_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.record (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    title character varying(255) NOT NULL,
-    description character varying(255) NOT NULL,
-    location character varying(255) DEFAULT ''::character varying,
-    significance character varying(255) DEFAULT ''::character varying,
-    url character varying(255) NOT NULL,
-    start_date timestamp without time zone,
-    end_date timestamp without time zone,
-    type smallint NOT NULL,
-    status smallint NOT NULL
+ALTER TABLE public.category_definition ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.category_definition_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 32767
+    CACHE 1
 );
 
 
 --
--- Name: record_history; Type: TABLE; Schema: public; Owner: -
+-- Name: claim; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.record_history (
+CREATE TABLE public.claim (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
-    record_id uuid,
-    title character varying(255) NOT NULL,
-    description character varying(255) NOT NULL,
-    location character varying(255) DEFAULT ''::character varying,
-    significance character varying(255) DEFAULT ''::character varying,
-    url character varying(255) NOT NULL,
-    start_date timestamp without time zone,
-    end_date timestamp without time zone,
-    type smallint NOT NULL,
-    status smallint NOT NULL,
+    source_id uuid NOT NULL,
+    field character varying(50),
+    impact_id uuid,
+    confidence smallint NOT NULL,
+    page character varying(50),
+    quote character varying(1000),
     created_at timestamp without time zone DEFAULT now() NOT NULL,
-    updated_at timestamp without time zone DEFAULT now() NOT NULL
+    alternative_id uuid,
+    CONSTRAINT claim_alternative_check CHECK (((alternative_id IS NULL) OR (field IS NOT NULL))),
+    CONSTRAINT claim_check CHECK (((field IS NULL) <> (impact_id IS NULL)))
 );
 
 
 --
--- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
+-- Name: comment; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.schema_migrations (
-    version character varying(128) NOT NULL
+CREATE TABLE public.comment (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid,
+    link_id uuid,
+    parent_id uuid,
+    author character varying(255) NOT NULL,
+    body character varying(4000) NOT NULL,
+    resolved_at timestamp without time zone,
+    resolved_by character varying(255),
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT comment_check CHECK (((record_id IS NULL) <> (link_id IS NULL))),
+    CONSTRAINT comment_check1 CHECK ((parent_id <> id))
 );
 
 
 --
--- Name: source; Type: TABLE; Schema: public; Owner: -
+-- Name: comment_mention; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.source (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    record_id uuid NOT NULL,
-    title character varying(255) NOT NULL,
-    type smallint NOT NULL,
-    url character varying(255) NOT NULL,
-    description character varying(255)
+CREATE TABLE public.comment_mention (
+    comment_id uuid NOT NULL,
+    user_name character varying(255) NOT NULL
 );
 
 
 --
--- Name: impact_history impact_history_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: comment_revision; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.impact_history
-    ADD CONSTRAINT impact_history_pkey PRIMARY KEY (id);
+CREATE TABLE public.comment_revision (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    comment_id uuid NOT NULL,
+    body character varying(4000) NOT NULL,
+    created_at timestamp without time zone NOT NULL
+);
 
 
 --
--- Name: impact impact_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: external_identifier; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.impact
-    ADD CONSTRAINT impact_pkey PRIMARY KEY (id);
+CREATE TABLE public.external_identifier (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid NOT NULL,
+    scheme smallint NOT NULL,
+    value character varying(255) NOT NULL
+);
 
 
 --
--- Name: link link_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: graph_change; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.link
-    ADD CONSTRAINT link_pkey PRIMARY KEY (id);
+CREATE TABLE public.graph_change (
+    id bigint NOT NULL,
+    link_changes bigint DEFAULT 0 NOT NULL,
+    influence_input_changes bigint DEFAULT 0 NOT NULL
+);
 
 
 --
--- Name: record_history record_history_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: graph_change_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.record_history
-    ADD CONSTRAINT record_history_pkey PRIMARY KEY (id);
+ALTER TABLE public.graph_change ALTER COLUMN id ADD GENERATED ALWAYS AS IDENTITY (
+    SEQUENCE NAME public.graph_change_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
 
 
 --
--- Name: record record_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: graph_revision; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.record
-    ADD CONSTRAINT record_pkey PRIMARY KEY (id);
+CREATE TABLE public.graph_revision (
+    id boolean DEFAULT true NOT NULL,
+    revision bigint DEFAULT 0 NOT NULL,
+    influence_revision bigint,
+    influence_decay double precision,
+    influence_inputs_revision bigint DEFAULT 0 NOT NULL,
+    influence_computed_inputs_revision bigint,
+    CONSTRAINT graph_revision_id_check CHECK (id)
+);
 
 
 --
--- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: impact; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.schema_migrations
-    ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+CREATE TABLE public.impact (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid NOT NULL,
+    description character varying(255) NOT NULL,
+    value smallint NOT NULL,
+    category smallint NOT NULL,
+    confidence smallint
+);
 
 
 --
--- Name: source source_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: impact_history; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.source
-    ADD CONSTRAINT source_pkey PRIMARY KEY (id);
+CREATE TABLE public.impact_history (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    impact_id uuid,
+    record_id uuid,
+    description character varying(255) NOT NULL,
+    value smallint NOT NULL,
+    category smallint NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
 
 
 --
--- Name: idx_impact_history_impact_id; Type: INDEX; Schema: public; Owner: -
+-- Name: involvement; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_impact_history_impact_id ON public.impact_history USING btree (impact_id);
+CREATE TABLE public.involvement (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    person_id uuid NOT NULL,
+    record_id uuid NOT NULL,
+    role smallint NOT NULL,
+    start_date timestamp without time zone,
+    end_date timestamp without time zone,
+    CONSTRAINT involvement_check CHECK ((person_id <> record_id))
+);
 
 
 --
--- Name: idx_record_history_record_id; Type: INDEX; Schema: public; Owner: -
+-- Name: link; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_record_history_record_id ON public.record_history USING btree (record_id);
+CREATE TABLE public.link (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid NOT NULL,
+    record_id2 uuid NOT NULL,
+    strength smallint NOT NULL,
+    confidence smallint
+);
 
 
 --
--- Name: idx_record_impacts; Type: INDEX; Schema: public; Owner: -
+-- Name: notification; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_record_impacts ON public.impact USING btree (record_id);
+CREATE TABLE public.notification (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_name character varying(255) NOT NULL,
+    record_id uuid NOT NULL,
+    kind smallint NOT NULL,
+    related_record_id uuid,
+    created_at timestamp without time zone DEFAULT clock_timestamp() NOT NULL,
+    read_at timestamp without time zone,
+    comment_id uuid,
+    txid bigint DEFAULT txid_current()
+);
 
 
 --
--- Name: impact tr_impact_history; Type: TRIGGER; Schema: public; Owner: -
+-- Name: outbox; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TRIGGER tr_impact_history AFTER INSERT OR UPDATE ON public.impact FOR EACH ROW EXECUTE FUNCTION public.update_impact_history();
+CREATE TABLE public.outbox (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    event_type character varying(50) NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT clock_timestamp() NOT NULL,
+    dispatched_at timestamp without time zone
+);
 
 
 --
--- Name: record tr_record_history; Type: TRIGGER; Schema: public; Owner: -
+-- Name: person_name; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TRIGGER tr_record_history AFTER INSERT OR UPDATE ON public.record FOR EACH ROW EXECUTE FUNCTION public.update_record_history();
+CREATE TABLE public.person_name (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid NOT NULL,
+    name character varying(255) NOT NULL
+);
 
 
 --
--- Name: impact_history impact_history_impact_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: person_occupation; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.person_occupation (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid NOT NULL,
+    occupation character varying(255) NOT NULL
+);
+
+
+--
+-- Name: person_profile; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.person_profile (
+    record_id uuid NOT NULL,
+    birth_date timestamp without time zone,
+    birth_place character varying(255),
+    death_date timestamp without time zone,
+    death_place character varying(255)
+);
+
+
+--
+-- Name: record; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.record (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    title character varying(255) NOT NULL,
+    description character varying(255) NOT NULL,
+    location character varying(255) DEFAULT ''::character varying,
+    significance character varying(255) DEFAULT ''::character varying,
+    url character varying(255) NOT NULL,
+    start_date timestamp without time zone,
+    end_date timestamp without time zone,
+    type smallint NOT NULL,
+    status smallint NOT NULL,
+    attributes jsonb DEFAULT '{}'::jsonb NOT NULL,
+    confidence smallint
+);
+
+
+--
+-- Name: record_alias; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.record_alias (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid NOT NULL,
+    name character varying(255) NOT NULL,
+    language character varying(35)
+);
+
+
+--
+-- Name: record_history; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.record_history (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid,
+    title character varying(255) NOT NULL,
+    description character varying(255) NOT NULL,
+    location character varying(255) DEFAULT ''::character varying,
+    significance character varying(255) DEFAULT ''::character varying,
+    url character varying(255) NOT NULL,
+    start_date timestamp without time zone,
+    end_date timestamp without time zone,
+    type smallint NOT NULL,
+    status smallint NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    seq bigint NOT NULL
+);
+
+
+--
+-- Name: record_history_seq_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+ALTER TABLE public.record_history ALTER COLUMN seq ADD GENERATED ALWAYS AS IDENTITY (
+    SEQUENCE NAME public.record_history_seq_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+
+--
+-- Name: record_influence; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.record_influence (
+    record_id uuid NOT NULL,
+    influence double precision NOT NULL
+);
+
+
+--
+-- Name: record_label; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.record_label (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid NOT NULL,
+    language character varying(35) NOT NULL,
+    title character varying(255) NOT NULL,
+    description character varying(255)
+);
+
+
+--
+-- Name: record_redirect; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.record_redirect (
+    old_record_id uuid NOT NULL,
+    record_id uuid NOT NULL,
+    merged_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: record_term; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.record_term (
+    record_id uuid NOT NULL,
+    term_id uuid NOT NULL
+);
+
+
+--
+-- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.schema_migrations (
+    version character varying(128) NOT NULL
+);
+
+
+--
+-- Name: source; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.source (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    record_id uuid NOT NULL,
+    title character varying(255) NOT NULL,
+    type smallint NOT NULL,
+    url character varying(255) NOT NULL,
+    description character varying(255),
+    authors jsonb DEFAULT '[]'::jsonb NOT NULL,
+    year smallint,
+    publisher character varying(255),
+    container_title character varying(255)
+);
+
+
+--
+-- Name: status_definition; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.status_definition (
+    id smallint NOT NULL,
+    name character varying(50) NOT NULL,
+    builtin boolean DEFAULT false NOT NULL
+);
+
+
+--
+-- Name: status_definition_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+ALTER TABLE public.status_definition ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.status_definition_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 32767
+    CACHE 1
+);
+
+
+--
+-- Name: term; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.term (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    kind smallint NOT NULL,
+    name character varying(255) NOT NULL,
+    description character varying(255),
+    parent_id uuid,
+    CONSTRAINT term_check CHECK ((parent_id <> id))
+);
+
+
+--
+-- Name: type_definition; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.type_definition (
+    id smallint NOT NULL,
+    name character varying(50) NOT NULL,
+    builtin boolean DEFAULT false NOT NULL,
+    attribute_schema jsonb
+);
+
+
+--
+-- Name: type_definition_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+ALTER TABLE public.type_definition ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.type_definition_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 32767
+    CACHE 1
+);
+
+
+--
+-- Name: watch; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.watch (
+    user_name character varying(255) NOT NULL,
+    record_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: webhook; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.webhook (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    url character varying(2048) NOT NULL,
+    event_types jsonb DEFAULT '[]'::jsonb NOT NULL,
+    secret character varying(255) NOT NULL,
+    active boolean DEFAULT true NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: webhook_delivery; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.webhook_delivery (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    webhook_id uuid NOT NULL,
+    outbox_id uuid NOT NULL,
+    status smallint DEFAULT 0 NOT NULL,
+    attempts smallint DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp without time zone DEFAULT now() NOT NULL,
+    response_code smallint,
+    error character varying(1000),
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: alternative_value alternative_value_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.alternative_value
+    ADD CONSTRAINT alternative_value_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: alternative_value alternative_value_record_id_field_value_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.alternative_value
+    ADD CONSTRAINT alternative_value_record_id_field_value_key UNIQUE (record_id, field, value);
+
+
+--
+-- Name: arc_member arc_member_arc_id_position_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.arc_member
+    ADD CONSTRAINT arc_member_arc_id_position_key UNIQUE (arc_id, "position");
+
+
+--
+-- Name: arc_member arc_member_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.arc_member
+    ADD CONSTRAINT arc_member_pkey PRIMARY KEY (arc_id, record_id);
+
+
+--
+-- Name: category_definition category_definition_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.category_definition
+    ADD CONSTRAINT category_definition_name_key UNIQUE (name);
+
+
+--
+-- Name: category_definition category_definition_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.category_definition
+    ADD CONSTRAINT category_definition_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: claim claim_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.claim
+    ADD CONSTRAINT claim_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: comment_mention comment_mention_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mention
+    ADD CONSTRAINT comment_mention_pkey PRIMARY KEY (comment_id, user_name);
+
+
+--
+-- Name: comment comment_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: comment_revision comment_revision_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_revision
+    ADD CONSTRAINT comment_revision_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: external_identifier external_identifier_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.external_identifier
+    ADD CONSTRAINT external_identifier_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: external_identifier external_identifier_scheme_value_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.external_identifier
+    ADD CONSTRAINT external_identifier_scheme_value_key UNIQUE (scheme, value);
+
+
+--
+-- Name: graph_change graph_change_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.graph_change
+    ADD CONSTRAINT graph_change_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: graph_revision graph_revision_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.graph_revision
+    ADD CONSTRAINT graph_revision_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: impact_history impact_history_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.impact_history
+    ADD CONSTRAINT impact_history_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: impact impact_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.impact
+    ADD CONSTRAINT impact_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: involvement involvement_person_id_record_id_role_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.involvement
+    ADD CONSTRAINT involvement_person_id_record_id_role_key UNIQUE (person_id, record_id, role);
+
+
+--
+-- Name: involvement involvement_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.involvement
+    ADD CONSTRAINT involvement_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: link link_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.link
+    ADD CONSTRAINT link_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: notification notification_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification
+    ADD CONSTRAINT notification_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: outbox outbox_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.outbox
+    ADD CONSTRAINT outbox_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: person_name person_name_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.person_name
+    ADD CONSTRAINT person_name_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: person_name person_name_record_id_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.person_name
+    ADD CONSTRAINT person_name_record_id_name_key UNIQUE (record_id, name);
+
+
+--
+-- Name: person_occupation person_occupation_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.person_occupation
+    ADD CONSTRAINT person_occupation_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: person_occupation person_occupation_record_id_occupation_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.person_occupation
+    ADD CONSTRAINT person_occupation_record_id_occupation_key UNIQUE (record_id, occupation);
+
+
+--
+-- Name: person_profile person_profile_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.person_profile
+    ADD CONSTRAINT person_profile_pkey PRIMARY KEY (record_id);
+
+
+--
+-- Name: record_alias record_alias_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_alias
+    ADD CONSTRAINT record_alias_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: record_alias record_alias_record_id_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_alias
+    ADD CONSTRAINT record_alias_record_id_name_key UNIQUE (record_id, name);
+
+
+--
+-- Name: record_history record_history_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_history
+    ADD CONSTRAINT record_history_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: record_influence record_influence_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_influence
+    ADD CONSTRAINT record_influence_pkey PRIMARY KEY (record_id);
+
+
+--
+-- Name: record_label record_label_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_label
+    ADD CONSTRAINT record_label_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: record_label record_label_record_id_language_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_label
+    ADD CONSTRAINT record_label_record_id_language_key UNIQUE (record_id, language);
+
+
+--
+-- Name: record record_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record
+    ADD CONSTRAINT record_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: record_redirect record_redirect_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_redirect
+    ADD CONSTRAINT record_redirect_pkey PRIMARY KEY (old_record_id);
+
+
+--
+-- Name: record_term record_term_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_term
+    ADD CONSTRAINT record_term_pkey PRIMARY KEY (record_id, term_id);
+
+
+--
+-- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.schema_migrations
+    ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+
+
+--
+-- Name: source source_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.source
+    ADD CONSTRAINT source_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: status_definition status_definition_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.status_definition
+    ADD CONSTRAINT status_definition_name_key UNIQUE (name);
+
+
+--
+-- Name: status_definition status_definition_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.status_definition
+    ADD CONSTRAINT status_definition_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: term term_kind_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.term
+    ADD CONSTRAINT term_kind_name_key UNIQUE (kind, name);
+
+
+--
+-- Name: term term_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.term
+    ADD CONSTRAINT term_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: type_definition type_definition_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.type_definition
+    ADD CONSTRAINT type_definition_name_key UNIQUE (name);
+
+
+--
+-- Name: type_definition type_definition_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.type_definition
+    ADD CONSTRAINT type_definition_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: watch watch_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.watch
+    ADD CONSTRAINT watch_pkey PRIMARY KEY (user_name, record_id);
+
+
+--
+-- Name: webhook_delivery webhook_delivery_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.webhook_delivery
+    ADD CONSTRAINT webhook_delivery_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: webhook_delivery webhook_delivery_webhook_id_outbox_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.webhook_delivery
+    ADD CONSTRAINT webhook_delivery_webhook_id_outbox_id_key UNIQUE (webhook_id, outbox_id);
+
+
+--
+-- Name: webhook webhook_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.webhook
+    ADD CONSTRAINT webhook_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: idx_arc_member_record_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_arc_member_record_id ON public.arc_member USING btree (record_id);
+
+
+--
+-- Name: idx_claim_alternative_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_claim_alternative_id ON public.claim USING btree (alternative_id);
+
+
+--
+-- Name: idx_claim_impact_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_claim_impact_id ON public.claim USING btree (impact_id);
+
+
+--
+-- Name: idx_claim_source_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_claim_source_id ON public.claim USING btree (source_id);
+
+
+--
+-- Name: idx_comment_link_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_comment_link_id ON public.comment USING btree (link_id, created_at);
+
+
+--
+-- Name: idx_comment_parent_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_comment_parent_id ON public.comment USING btree (parent_id);
+
+
+--
+-- Name: idx_comment_record_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_comment_record_id ON public.comment USING btree (record_id, created_at);
+
+
+--
+-- Name: idx_comment_revision_comment_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_comment_revision_comment_id ON public.comment_revision USING btree (comment_id, created_at);
+
+
+--
+-- Name: idx_external_identifier_record_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_external_identifier_record_id ON public.external_identifier USING btree (record_id);
+
+
+--
+-- Name: idx_impact_history_impact_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_impact_history_impact_id ON public.impact_history USING btree (impact_id);
+
+
+--
+-- Name: idx_involvement_record_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_involvement_record_id ON public.involvement USING btree (record_id);
+
+
+--
+-- Name: idx_notification_unread; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notification_unread ON public.notification USING btree (user_name) WHERE (read_at IS NULL);
+
+
+--
+-- Name: idx_notification_user_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notification_user_name ON public.notification USING btree (user_name, created_at);
+
+
+--
+-- Name: idx_notification_user_name_record_id_txid; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_notification_user_name_record_id_txid ON public.notification USING btree (user_name, record_id, txid) WHERE (comment_id IS NULL);
+
+
+--
+-- Name: idx_outbox_pending; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_outbox_pending ON public.outbox USING btree (created_at) WHERE (dispatched_at IS NULL);
+
+
+--
+-- Name: idx_record_alias_name_trgm; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_alias_name_trgm ON public.record_alias USING gin (name public.gin_trgm_ops);
+
+
+--
+-- Name: idx_record_attributes; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_attributes ON public.record USING gin (attributes jsonb_path_ops);
+
+
+--
+-- Name: idx_record_history_record_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_history_record_id ON public.record_history USING btree (record_id);
+
+
+--
+-- Name: idx_record_history_record_id_seq; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_record_history_record_id_seq ON public.record_history USING btree (record_id, seq);
+
+
+--
+-- Name: idx_record_impacts; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_impacts ON public.impact USING btree (record_id);
+
+
+--
+-- Name: idx_record_influence_influence; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_influence_influence ON public.record_influence USING btree (influence);
+
+
+--
+-- Name: idx_record_label_title_trgm; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_label_title_trgm ON public.record_label USING gin (title public.gin_trgm_ops);
+
+
+--
+-- Name: idx_record_redirect_record_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_redirect_record_id ON public.record_redirect USING btree (record_id);
+
+
+--
+-- Name: idx_record_term_term_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_term_term_id ON public.record_term USING btree (term_id);
+
+
+--
+-- Name: idx_record_title_trgm; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_record_title_trgm ON public.record USING gin (title public.gin_trgm_ops);
+
+
+--
+-- Name: idx_term_parent_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_term_parent_id ON public.term USING btree (parent_id);
+
+
+--
+-- Name: idx_watch_record_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_watch_record_id ON public.watch USING btree (record_id);
+
+
+--
+-- Name: idx_webhook_delivery_due; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_webhook_delivery_due ON public.webhook_delivery USING btree (next_attempt_at) WHERE (status = 0);
+
+
+--
+-- Name: idx_webhook_delivery_webhook_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_webhook_delivery_webhook_id ON public.webhook_delivery USING btree (webhook_id, created_at);
+
+
+--
+-- Name: arc_member tr_arc_member_watchers; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_arc_member_watchers AFTER INSERT OR DELETE ON public.arc_member FOR EACH ROW EXECUTE FUNCTION public.notify_arc_watchers();
+
+
+--
+-- Name: category_definition tr_category_definition_notify; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_category_definition_notify AFTER INSERT OR DELETE OR UPDATE OR TRUNCATE ON public.category_definition FOR EACH STATEMENT EXECUTE FUNCTION public.notify_vocabulary_change();
+
+
+--
+-- Name: impact tr_impact_history; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_impact_history AFTER INSERT OR UPDATE ON public.impact FOR EACH ROW EXECUTE FUNCTION public.update_impact_history();
+
+
+--
+-- Name: impact_history tr_impact_history_watchers; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_impact_history_watchers AFTER INSERT ON public.impact_history FOR EACH ROW EXECUTE FUNCTION public.notify_impact_watchers();
+
+
+--
+-- Name: impact tr_impact_influence_inputs_revision; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_impact_influence_inputs_revision AFTER INSERT OR DELETE OR UPDATE OR TRUNCATE ON public.impact FOR EACH STATEMENT EXECUTE FUNCTION public.bump_influence_inputs_revision();
+
+
+--
+-- Name: impact tr_impact_notify; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_impact_notify AFTER INSERT OR DELETE OR UPDATE ON public.impact FOR EACH ROW EXECUTE FUNCTION public.notify_change();
+
+
+--
+-- Name: impact tr_impact_outbox; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_impact_outbox AFTER INSERT OR DELETE OR UPDATE ON public.impact FOR EACH ROW EXECUTE FUNCTION public.write_outbox();
+
+
+--
+-- Name: link tr_link_graph_revision; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_link_graph_revision AFTER INSERT OR DELETE OR UPDATE OR TRUNCATE ON public.link FOR EACH STATEMENT EXECUTE FUNCTION public.bump_graph_revision();
+
+
+--
+-- Name: link tr_link_notify; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_link_notify AFTER INSERT OR DELETE OR UPDATE ON public.link FOR EACH ROW EXECUTE FUNCTION public.notify_change();
+
+
+--
+-- Name: link tr_link_outbox; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_link_outbox AFTER INSERT OR DELETE OR UPDATE ON public.link FOR EACH ROW EXECUTE FUNCTION public.write_outbox();
+
+
+--
+-- Name: link tr_link_watchers; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_link_watchers AFTER INSERT OR DELETE OR UPDATE ON public.link FOR EACH ROW EXECUTE FUNCTION public.notify_link_watchers();
+
+
+--
+-- Name: record tr_record_history; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_record_history AFTER INSERT OR UPDATE ON public.record FOR EACH ROW EXECUTE FUNCTION public.update_record_history();
+
+
+--
+-- Name: record_history tr_record_history_watchers; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_record_history_watchers AFTER INSERT ON public.record_history FOR EACH ROW EXECUTE FUNCTION public.notify_record_watchers();
+
+
+--
+-- Name: record tr_record_notify; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_record_notify AFTER INSERT OR DELETE OR UPDATE ON public.record FOR EACH ROW EXECUTE FUNCTION public.notify_change();
+
+
+--
+-- Name: record tr_record_outbox; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_record_outbox AFTER INSERT OR DELETE OR UPDATE ON public.record FOR EACH ROW EXECUTE FUNCTION public.write_outbox();
+
+
+--
+-- Name: record tr_record_status_influence_inputs_revision; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_record_status_influence_inputs_revision AFTER UPDATE OF status ON public.record FOR EACH ROW WHEN ((old.status IS DISTINCT FROM new.status)) EXECUTE FUNCTION public.bump_influence_inputs_revision();
+
+
+--
+-- Name: status_definition tr_status_definition_notify; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_status_definition_notify AFTER INSERT OR DELETE OR UPDATE OR TRUNCATE ON public.status_definition FOR EACH STATEMENT EXECUTE FUNCTION public.notify_vocabulary_change();
+
+
+--
+-- Name: type_definition tr_type_definition_notify; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER tr_type_definition_notify AFTER INSERT OR DELETE OR UPDATE OR TRUNCATE ON public.type_definition FOR EACH STATEMENT EXECUTE FUNCTION public.notify_vocabulary_change();
+
+
+--
+-- Name: alternative_value alternative_value_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.alternative_value
+    ADD CONSTRAINT alternative_value_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: arc_member arc_member_arc_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.arc_member
+    ADD CONSTRAINT arc_member_arc_id_fkey FOREIGN KEY (arc_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: arc_member arc_member_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.arc_member
+    ADD CONSTRAINT arc_member_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: claim claim_alternative_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.claim
+    ADD CONSTRAINT claim_alternative_id_fkey FOREIGN KEY (alternative_id) REFERENCES public.alternative_value(id) ON DELETE CASCADE;
+
+
+--
+-- Name: claim claim_impact_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.claim
+    ADD CONSTRAINT claim_impact_id_fkey FOREIGN KEY (impact_id) REFERENCES public.impact(id) ON DELETE CASCADE;
+
+
+--
+-- Name: claim claim_source_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.claim
+    ADD CONSTRAINT claim_source_id_fkey FOREIGN KEY (source_id) REFERENCES public.source(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment comment_link_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_link_id_fkey FOREIGN KEY (link_id) REFERENCES public.link(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment_mention comment_mention_comment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_mention
+    ADD CONSTRAINT comment_mention_comment_id_fkey FOREIGN KEY (comment_id) REFERENCES public.comment(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment comment_parent_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES public.comment(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment comment_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment
+    ADD CONSTRAINT comment_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: comment_revision comment_revision_comment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.comment_revision
+    ADD CONSTRAINT comment_revision_comment_id_fkey FOREIGN KEY (comment_id) REFERENCES public.comment(id) ON DELETE CASCADE;
+
+
+--
+-- Name: external_identifier external_identifier_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.external_identifier
+    ADD CONSTRAINT external_identifier_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: impact impact_category_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.impact
+    ADD CONSTRAINT impact_category_fkey FOREIGN KEY (category) REFERENCES public.category_definition(id);
+
+
+--
+-- Name: impact_history impact_history_impact_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
 ALTER TABLE ONLY public.impact_history
@@ -325,6 +1702,22 @@ ALTER TABLE ONLY public.impact
     ADD CONSTRAINT impact_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
 
 
+--
+-- Name: involvement involvement_person_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.involvement
+    ADD CONSTRAINT involvement_person_id_fkey FOREIGN KEY (person_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: involvement involvement_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.involvement
+    ADD CONSTRAINT involvement_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
 --
 -- Name: link link_record_id2_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -341,6 +1734,54 @@ ALTER TABLE ONLY public.link
     ADD CONSTRAINT link_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
 
 
+--
+-- Name: notification notification_comment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification
+    ADD CONSTRAINT notification_comment_id_fkey FOREIGN KEY (comment_id) REFERENCES public.comment(id) ON DELETE CASCADE;
+
+
+--
+-- Name: notification notification_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification
+    ADD CONSTRAINT notification_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: person_name person_name_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.person_name
+    ADD CONSTRAINT person_name_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.person_profile(record_id) ON DELETE CASCADE;
+
+
+--
+-- Name: person_occupation person_occupation_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.person_occupation
+    ADD CONSTRAINT person_occupation_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.person_profile(record_id) ON DELETE CASCADE;
+
+
+--
+-- Name: person_profile person_profile_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.person_profile
+    ADD CONSTRAINT person_profile_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: record_alias record_alias_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_alias
+    ADD CONSTRAINT record_alias_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
 --
 -- Name: record_history record_history_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -349,6 +1790,70 @@ ALTER TABLE ONLY public.record_history
     ADD CONSTRAINT record_history_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
 
 
+--
+-- Name: record_influence record_influence_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_influence
+    ADD CONSTRAINT record_influence_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: record_label record_label_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_label
+    ADD CONSTRAINT record_label_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: record_redirect record_redirect_old_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_redirect
+    ADD CONSTRAINT record_redirect_old_record_id_fkey FOREIGN KEY (old_record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: record_redirect record_redirect_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_redirect
+    ADD CONSTRAINT record_redirect_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: record record_status_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record
+    ADD CONSTRAINT record_status_fkey FOREIGN KEY (status) REFERENCES public.status_definition(id);
+
+
+--
+-- Name: record_term record_term_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_term
+    ADD CONSTRAINT record_term_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: record_term record_term_term_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record_term
+    ADD CONSTRAINT record_term_term_id_fkey FOREIGN KEY (term_id) REFERENCES public.term(id) ON DELETE CASCADE;
+
+
+--
+-- Name: record record_type_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.record
+    ADD CONSTRAINT record_type_fkey FOREIGN KEY (type) REFERENCES public.type_definition(id);
+
+
 --
 -- Name: source source_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -357,6 +1862,38 @@ ALTER TABLE ONLY public.source
     ADD CONSTRAINT source_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
 
 
+--
+-- Name: term term_parent_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.term
+    ADD CONSTRAINT term_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES public.term(id);
+
+
+--
+-- Name: watch watch_record_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.watch
+    ADD CONSTRAINT watch_record_id_fkey FOREIGN KEY (record_id) REFERENCES public.record(id) ON DELETE CASCADE;
+
+
+--
+-- Name: webhook_delivery webhook_delivery_outbox_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.webhook_delivery
+    ADD CONSTRAINT webhook_delivery_outbox_id_fkey FOREIGN KEY (outbox_id) REFERENCES public.outbox(id) ON DELETE CASCADE;
+
+
+--
+-- Name: webhook_delivery webhook_delivery_webhook_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.webhook_delivery
+    ADD CONSTRAINT webhook_delivery_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES public.webhook(id) ON DELETE CASCADE;
+
+
 --
 -- PostgreSQL database dump complete
 --
@@ -370,4 +1907,28 @@ INSERT INTO public.schema_migrations (version) VALUES
     ('20250223144317'),
     ('20250302122704'),
     ('20250302131546'),
-    ('20250303074713');
+    ('20250303074713'),
+    ('20250310190512'),
+    ('20250316101245'),
+    ('20250322143010'),
+    ('20250329094521'),
+    ('20250405112033'),
+    ('20250412150318'),
+    ('20250419093427'),
+    ('20250426141552'),
+    ('20250503101744'),
+    ('20250510093215'),
+    ('20250517102406'),
+    ('20250524094113'),
+    ('20250531100527'),
+    ('20250607091842'),
+    ('20250614083027'),
+    ('20250621094512'),
+    ('20250628100315'),
+    ('20250705091204'),
+    ('20250712083015'),
+    ('20250719084512'),
+    ('20250726091530'),
+    ('20250802093011'),
+    ('20250809090512'),
+    ('20250816091045');
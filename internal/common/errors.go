@@ -8,4 +8,60 @@ var (
 	ErrImpactNotFound    = errors.New("impact not found")
 	ErrLinkAlreadyExists = errors.New("link already exists")
 	ErrLinkToItself      = errors.New("cannot link record to itself")
+	ErrImportInvalid     = errors.New("import contains invalid rows")
+
+	ErrInvalidExternalId       = errors.New("invalid external identifier")
+	ErrExternalIdAlreadyExists = errors.New("external identifier already exists")
+	ErrInvalidLabel            = errors.New("invalid label")
+
+	ErrInvalidAttributes      = errors.New("invalid attributes")
+	ErrInvalidAttributeSchema = errors.New("invalid attribute schema")
+	ErrInvalidAttributeFilter = errors.New("invalid attribute filter")
+
+	ErrMergeWithItself     = errors.New("cannot merge record with itself")
+	ErrRecordAlreadyMerged = errors.New("record was already merged into another record")
+	ErrRecordRemoved       = errors.New("removed records cannot be merged")
+
+	ErrNotAnArc           = errors.New("record is not an arc")
+	ErrArcCycle           = errors.New("arc cannot contain itself")
+	ErrDuplicateArcMember = errors.New("record is listed more than once in the arc")
+
+	ErrNotAPerson               = errors.New("record is not a person")
+	ErrInvolvementNotFound      = errors.New("involvement not found")
+	ErrInvolvementAlreadyExists = errors.New("person already has this role in the record")
+	ErrInvolvementInPerson      = errors.New("persons can only be involved in events, objects and arcs")
+	ErrInvalidDateRange         = errors.New("end date is before start date")
+
+	ErrTermNotFound      = errors.New("term not found")
+	ErrTermAlreadyExists = errors.New("term with this name already exists")
+	ErrTermHasChildren   = errors.New("term has narrower terms")
+	ErrInvalidTermParent = errors.New("broader term must be of the same kind and not below the term itself")
+
+	ErrVocabularyValueNotFound      = errors.New("vocabulary value not found")
+	ErrVocabularyValueAlreadyExists = errors.New("vocabulary value already exists")
+	ErrVocabularyValueBuiltin       = errors.New("built-in vocabulary values cannot be changed")
+	ErrVocabularyValueInUse         = errors.New("vocabulary value is still in use")
+
+	ErrSourceNotFound    = errors.New("source not found")
+	ErrClaimNotFound     = errors.New("claim not found")
+	ErrInvalidClaim      = errors.New("claim must back either a field, an alternative value or an impact of the record of its source")
+	ErrMissingProvenance = errors.New("record cannot be reviewed before its facts are backed by sources")
+
+	ErrAlternativeNotFound      = errors.New("alternative value not found")
+	ErrAlternativeAlreadyExists = errors.New("alternative value already exists")
+	ErrInvalidAlternative       = errors.New("invalid alternative value")
+
+	ErrRevisionNotFound = errors.New("revision not found")
+
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrInvalidWebhook   = errors.New("webhook url must be an absolute http or https url")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrDeliveryNotDead  = errors.New("only dead deliveries can be retried")
+
+	ErrWatchNotFound        = errors.New("record is not watched")
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrInvalidComment   = errors.New("replies must answer a comment on the same record or link")
+	ErrCommentForbidden = errors.New("only the author can edit a comment")
 )
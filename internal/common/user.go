@@ -0,0 +1,42 @@
+package common
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// UserInput is embedded in the input of operations made on behalf of a user,
+// who is named by the authenticating proxy in front of the API.
+type UserInput struct {
+	User string `header:"X-User" required:"true" minLength:"1" maxLength:"255" doc:"User making the request, as passed on by the authenticating proxy"`
+}
+
+// ActorInput is embedded in the input of changes the watchers of a record
+// are notified of. Requests that name a user do not notify that user.
+type ActorInput struct {
+	Actor string `header:"X-User" maxLength:"255" doc:"User making the change, as passed on by the authenticating proxy. Watchers are not notified of their own changes"`
+}
+
+type actorKey struct{}
+
+// WithActor names the user making the changes of a request.
+func (i ActorInput) WithActor(c context.Context) context.Context {
+	if i.Actor == "" {
+		return c
+	}
+	return context.WithValue(c, actorKey{}, i.Actor)
+}
+
+// SetActor tells the triggers notifying watchers which user makes the
+// changes of a transaction, if the request named one.
+func SetActor(c context.Context, tx *sql.Tx) error {
+	actor, ok := c.Value(actorKey{}).(string)
+	if !ok {
+		return nil
+	}
+	if _, err := tx.ExecContext(c, "SELECT set_config('historylink.actor', $1, true)", actor); err != nil {
+		return fmt.Errorf("failed to set actor: %w", err)
+	}
+	return nil
+}
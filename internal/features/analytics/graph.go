@@ -0,0 +1,382 @@
+package analytics
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"historylink/.gen/historylink/public/model"
+
+	"github.com/google/uuid"
+)
+
+const (
+	pageRankDamping    = 0.85
+	pageRankIterations = 100
+	pageRankTolerance  = 1e-9
+	louvainPasses      = 100
+	epsilon            = 1e-12
+)
+
+// graph is the undirected link graph with records as nodes, numbered in the
+// order of their ids so results do not depend on the order links are read in.
+// Parallel links between the same records are folded into one edge carrying
+// their combined strength.
+type graph struct {
+	ids []uuid.UUID
+	adj []map[int]float64
+}
+
+func newGraph(links []model.Link) graph {
+	seen := map[uuid.UUID]bool{}
+	var ids []uuid.UUID
+	for _, link := range links {
+		for _, id := range []uuid.UUID{link.RecordID, link.RecordId2} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	index := make(map[uuid.UUID]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	g := graph{ids: ids, adj: make([]map[int]float64, len(ids))}
+	for i := range g.adj {
+		g.adj[i] = map[int]float64{}
+	}
+	for _, link := range links {
+		a, b := index[link.RecordID], index[link.RecordId2]
+		if a == b {
+			continue
+		}
+		// Links without a positive strength still connect their records
+		weight := math.Max(float64(link.Strength), 1)
+		g.adj[a][b] += weight
+		g.adj[b][a] += weight
+	}
+	return g
+}
+
+// neighbors returns the neighbors of a node in ascending order, as ranging
+// over the map directly would make results differ between runs.
+func (g graph) neighbors(node int) []int {
+	neighbors := make([]int, 0, len(g.adj[node]))
+	for neighbor := range g.adj[node] {
+		neighbors = append(neighbors, neighbor)
+	}
+	sort.Ints(neighbors)
+	return neighbors
+}
+
+func (g graph) degrees() ([]int, []float64) {
+	degree := make([]int, len(g.ids))
+	weighted := make([]float64, len(g.ids))
+	for node, edges := range g.adj {
+		degree[node] = len(edges)
+		for _, weight := range edges {
+			weighted[node] += weight
+		}
+	}
+	return degree, weighted
+}
+
+// pageRank treats every link as going both ways, with the chance of following
+// a link proportional to its strength.
+func (g graph) pageRank() []float64 {
+	n := len(g.ids)
+	if n == 0 {
+		return nil
+	}
+	_, strength := g.degrees()
+
+	rank := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+
+	for iteration := 0; iteration < pageRankIterations; iteration++ {
+		next := make([]float64, n)
+		for i := range next {
+			next[i] = (1 - pageRankDamping) / float64(n)
+		}
+		for node, edges := range g.adj {
+			for neighbor, weight := range edges {
+				next[neighbor] += pageRankDamping * rank[node] * weight / strength[node]
+			}
+		}
+
+		delta := 0.0
+		for i := range rank {
+			delta += math.Abs(next[i] - rank[i])
+		}
+		rank = next
+		if delta < pageRankTolerance {
+			break
+		}
+	}
+	return rank
+}
+
+// betweenness is Brandes' algorithm on shortest paths where a link is shorter
+// the stronger it is. Scores are normalized to the share of all pairs of
+// other records whose shortest paths run through a record.
+func (g graph) betweenness() []float64 {
+	n := len(g.ids)
+	centrality := make([]float64, n)
+
+	for source := 0; source < n; source++ {
+		var stack []int
+		predecessors := make([][]int, n)
+		paths := make([]float64, n)
+		distance := make([]float64, n)
+		for i := range distance {
+			distance[i] = math.Inf(1)
+		}
+		paths[source] = 1
+		distance[source] = 0
+
+		queue := &priorityQueue{{node: source}}
+		for queue.Len() > 0 {
+			item := heap.Pop(queue).(queueItem)
+			if item.distance > distance[item.node] {
+				continue
+			}
+			stack = append(stack, item.node)
+
+			for _, neighbor := range g.neighbors(item.node) {
+				length := item.distance + 1/g.adj[item.node][neighbor]
+				switch {
+				case length < distance[neighbor]-epsilon:
+					distance[neighbor] = length
+					paths[neighbor] = paths[item.node]
+					predecessors[neighbor] = []int{item.node}
+					heap.Push(queue, queueItem{node: neighbor, distance: length})
+				case math.Abs(length-distance[neighbor]) <= epsilon:
+					paths[neighbor] += paths[item.node]
+					predecessors[neighbor] = append(predecessors[neighbor], item.node)
+				}
+			}
+		}
+
+		dependency := make([]float64, n)
+		for i := len(stack) - 1; i >= 0; i-- {
+			node := stack[i]
+			for _, predecessor := range predecessors[node] {
+				dependency[predecessor] += paths[predecessor] / paths[node] * (1 + dependency[node])
+			}
+			if node != source {
+				centrality[node] += dependency[node]
+			}
+		}
+	}
+
+	// Every pair was counted from both ends
+	if n > 2 {
+		pairs := float64(n-1) * float64(n-2)
+		for i := range centrality {
+			centrality[i] /= pairs
+		}
+	}
+	return centrality
+}
+
+type queueItem struct {
+	node     int
+	distance float64
+}
+
+type priorityQueue []queueItem
+
+func (q priorityQueue) Len() int { return len(q) }
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].distance == q[j].distance {
+		return q[i].node < q[j].node
+	}
+	return q[i].distance < q[j].distance
+}
+func (q priorityQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue) Push(x any)   { *q = append(*q, x.(queueItem)) }
+func (q *priorityQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// communities detects communities with the Louvain method, moving records
+// between communities while that raises modularity and then repeating on the
+// graph of communities. Communities are numbered from largest to smallest.
+func (g graph) communities() ([]int, float64) {
+	n := len(g.ids)
+	membership := make([]int, n)
+	for i := range membership {
+		membership[i] = i
+	}
+
+	level := g.adj
+	for {
+		assignment, moved := louvainLevel(level)
+		for i := range membership {
+			membership[i] = assignment[membership[i]]
+		}
+		if !moved {
+			break
+		}
+		level = aggregate(level, assignment)
+	}
+
+	membership = renumberBySize(membership)
+	return membership, modularity(g.adj, membership)
+}
+
+// louvainLevel moves each node to the neighboring community that gains the
+// most modularity until no move helps, returning the community of every node
+// numbered from zero.
+func louvainLevel(adj []map[int]float64) ([]int, bool) {
+	n := len(adj)
+	community := make([]int, n)
+	degree := make([]float64, n)
+	total := make([]float64, n)
+	twiceWeight := 0.0
+	for node, edges := range adj {
+		community[node] = node
+		for _, weight := range edges {
+			degree[node] += weight
+		}
+		total[node] = degree[node]
+		twiceWeight += degree[node]
+	}
+	if twiceWeight == 0 {
+		return community, false
+	}
+
+	moved := false
+	for pass := 0; pass < louvainPasses; pass++ {
+		changed := false
+		for node := 0; node < n; node++ {
+			current := community[node]
+
+			links := map[int]float64{}
+			neighbors := make([]int, 0, len(adj[node]))
+			for neighbor, weight := range adj[node] {
+				if neighbor == node {
+					continue
+				}
+				if _, ok := links[community[neighbor]]; !ok {
+					neighbors = append(neighbors, community[neighbor])
+				}
+				links[community[neighbor]] += weight
+			}
+			sort.Ints(neighbors)
+
+			total[current] -= degree[node]
+			best := current
+			bestGain := links[current] - total[current]*degree[node]/twiceWeight
+			for _, candidate := range neighbors {
+				gain := links[candidate] - total[candidate]*degree[node]/twiceWeight
+				if gain > bestGain+epsilon {
+					best, bestGain = candidate, gain
+				}
+			}
+			total[best] += degree[node]
+
+			if best != current {
+				community[node] = best
+				changed, moved = true, true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	return renumber(community), moved
+}
+
+// aggregate builds the graph of communities, in which links inside a
+// community become a link of the community to itself.
+func aggregate(adj []map[int]float64, community []int) []map[int]float64 {
+	size := 0
+	for _, c := range community {
+		size = max(size, c+1)
+	}
+	result := make([]map[int]float64, size)
+	for i := range result {
+		result[i] = map[int]float64{}
+	}
+	for node, edges := range adj {
+		for neighbor, weight := range edges {
+			result[community[node]][community[neighbor]] += weight
+		}
+	}
+	return result
+}
+
+func modularity(adj []map[int]float64, community []int) float64 {
+	inside := map[int]float64{}
+	total := map[int]float64{}
+	twiceWeight := 0.0
+	for node, edges := range adj {
+		for neighbor, weight := range edges {
+			twiceWeight += weight
+			total[community[node]] += weight
+			if community[node] == community[neighbor] {
+				inside[community[node]] += weight
+			}
+		}
+	}
+	if twiceWeight == 0 {
+		return 0
+	}
+
+	q := 0.0
+	for c, t := range total {
+		q += inside[c]/twiceWeight - (t/twiceWeight)*(t/twiceWeight)
+	}
+	return q
+}
+
+// renumber numbers communities from zero in order of first appearance.
+func renumber(community []int) []int {
+	numbers := map[int]int{}
+	result := make([]int, len(community))
+	for i, c := range community {
+		if _, ok := numbers[c]; !ok {
+			numbers[c] = len(numbers)
+		}
+		result[i] = numbers[c]
+	}
+	return result
+}
+
+func renumberBySize(community []int) []int {
+	sizes := map[int]int{}
+	for _, c := range community {
+		sizes[c]++
+	}
+	order := make([]int, 0, len(sizes))
+	for c := range sizes {
+		order = append(order, c)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if sizes[order[i]] == sizes[order[j]] {
+			return order[i] < order[j]
+		}
+		return sizes[order[i]] > sizes[order[j]]
+	})
+
+	numbers := make(map[int]int, len(order))
+	for i, c := range order {
+		numbers[c] = i
+	}
+	result := make([]int, len(community))
+	for i, c := range community {
+		result[i] = numbers[c]
+	}
+	return result
+}
@@ -0,0 +1,215 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"historylink/.gen/historylink/public/model"
+
+	"github.com/google/uuid"
+)
+
+// node gives the id of the record numbered i in the graph, as records are
+// numbered in the order of their ids.
+func node(i int) uuid.UUID {
+	return uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+}
+
+// testGraph builds a graph from links given as the numbers of both records
+// and the strength of the link.
+func testGraph(links ...[3]int) graph {
+	models := make([]model.Link, len(links))
+	for i, link := range links {
+		models[i] = model.Link{
+			ID:        uuid.New(),
+			RecordID:  node(link[0]),
+			RecordId2: node(link[1]),
+			Strength:  int16(link[2]),
+		}
+	}
+	return newGraph(models)
+}
+
+func equalScores(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-6 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewGraph(t *testing.T) {
+	g := testGraph([3]int{1, 0, 2}, [3]int{0, 1, 3}, [3]int{1, 2, 0}, [3]int{2, 2, 4})
+
+	if expected := []uuid.UUID{node(0), node(1), node(2)}; !reflect.DeepEqual(g.ids, expected) {
+		t.Fatalf("ids = %v, expected %v", g.ids, expected)
+	}
+	expected := []map[int]float64{
+		// Parallel links add up
+		{1: 5},
+		// Links without strength count as strength 1
+		{0: 5, 2: 1},
+		// Links of a record to itself are left out
+		{1: 1},
+	}
+	if !reflect.DeepEqual(g.adj, expected) {
+		t.Errorf("adj = %v, expected %v", g.adj, expected)
+	}
+}
+
+func TestBetweenness(t *testing.T) {
+	tests := []struct {
+		name     string
+		graph    graph
+		expected []float64
+	}{
+		{name: "empty", graph: testGraph(), expected: []float64{}},
+		{name: "single link", graph: testGraph([3]int{0, 1, 1}), expected: []float64{0, 0}},
+		{
+			name:     "path",
+			graph:    testGraph([3]int{0, 1, 1}, [3]int{1, 2, 1}),
+			expected: []float64{0, 1, 0},
+		},
+		{
+			name:     "star",
+			graph:    testGraph([3]int{0, 1, 1}, [3]int{0, 2, 1}, [3]int{0, 3, 1}),
+			expected: []float64{1, 0, 0, 0},
+		},
+		{
+			name:     "square splits paths between both ways round",
+			graph:    testGraph([3]int{0, 1, 1}, [3]int{1, 2, 1}, [3]int{2, 3, 1}, [3]int{3, 0, 1}),
+			expected: []float64{1.0 / 6, 1.0 / 6, 1.0 / 6, 1.0 / 6},
+		},
+		{
+			name:     "strong links are shorter than a weak direct one",
+			graph:    testGraph([3]int{0, 1, 5}, [3]int{1, 2, 5}, [3]int{0, 2, 1}),
+			expected: []float64{0, 1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.graph.betweenness(); !equalScores(got, tt.expected) {
+				t.Errorf("betweenness() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	// In a star of n records the center and each leaf hold c and l, with
+	// c = (1-d)/n + d*(n-1)*l and l = (1-d)/n + d*c/(n-1)
+	center := (1 - pageRankDamping) / 4 * (1 + 3*pageRankDamping) / (1 - pageRankDamping*pageRankDamping)
+	leaf := (1 - center) / 3
+	// Leaves hand all of their rank back to the center either way, which
+	// shares its rank out by strength
+	weightedLeaf := func(strength float64) float64 {
+		return (1-pageRankDamping)/4 + pageRankDamping*center*strength/11
+	}
+
+	tests := []struct {
+		name     string
+		graph    graph
+		expected []float64
+	}{
+		{name: "empty", graph: testGraph(), expected: nil},
+		{
+			name:     "triangle",
+			graph:    testGraph([3]int{0, 1, 1}, [3]int{1, 2, 1}, [3]int{2, 0, 1}),
+			expected: []float64{1.0 / 3, 1.0 / 3, 1.0 / 3},
+		},
+		{
+			name:     "star",
+			graph:    testGraph([3]int{0, 1, 1}, [3]int{0, 2, 1}, [3]int{0, 3, 1}),
+			expected: []float64{center, leaf, leaf, leaf},
+		},
+		{
+			name:     "stronger links carry more rank",
+			graph:    testGraph([3]int{0, 1, 9}, [3]int{0, 2, 1}, [3]int{0, 3, 1}),
+			expected: []float64{center, weightedLeaf(9), weightedLeaf(1), weightedLeaf(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.graph.pageRank()
+			if !equalScores(got, tt.expected) {
+				t.Errorf("pageRank() = %v, expected %v", got, tt.expected)
+			}
+			sum := 0.0
+			for _, rank := range got {
+				sum += rank
+			}
+			if len(got) > 0 && math.Abs(sum-1) > 1e-6 {
+				t.Errorf("pageRank() sums to %f, expected 1", sum)
+			}
+		})
+	}
+}
+
+func TestCommunities(t *testing.T) {
+	tests := []struct {
+		name               string
+		graph              graph
+		expected           []int
+		expectedModularity float64
+	}{
+		{name: "empty", graph: testGraph(), expected: []int{}},
+		{name: "single link", graph: testGraph([3]int{0, 1, 1}), expected: []int{0, 0}},
+		{
+			name: "two triangles joined by a link",
+			graph: testGraph(
+				[3]int{0, 1, 1}, [3]int{1, 2, 1}, [3]int{2, 0, 1},
+				[3]int{3, 4, 1}, [3]int{4, 5, 1}, [3]int{5, 3, 1},
+				[3]int{2, 3, 1},
+			),
+			expected:           []int{0, 0, 0, 1, 1, 1},
+			expectedModularity: 5.0 / 14,
+		},
+		{
+			name: "larger communities come first",
+			graph: testGraph(
+				[3]int{0, 1, 1},
+				[3]int{2, 3, 1}, [3]int{3, 4, 1}, [3]int{4, 2, 1},
+			),
+			expected:           []int{1, 1, 0, 0, 0},
+			expectedModularity: 1 - (2.0/8)*(2.0/8) - (6.0/8)*(6.0/8),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, q := tt.graph.communities()
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("communities() = %v, expected %v", got, tt.expected)
+			}
+			if math.Abs(q-tt.expectedModularity) > 1e-9 {
+				t.Errorf("communities() modularity = %f, expected %f", q, tt.expectedModularity)
+			}
+		})
+	}
+}
+
+func TestRenumberBySize(t *testing.T) {
+	tests := []struct {
+		community []int
+		expected  []int
+	}{
+		{community: []int{}, expected: []int{}},
+		{community: []int{5, 5, 2, 7, 2, 2}, expected: []int{1, 1, 0, 2, 0, 0}},
+		// Communities of the same size are ordered by their number
+		{community: []int{3, 1, 3, 1}, expected: []int{1, 0, 1, 0}},
+	}
+
+	for _, tt := range tests {
+		if got := renumberBySize(tt.community); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("renumberBySize(%v) = %v, expected %v", tt.community, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func NewAnalyticsResources(conn *sql.DB, logger *slog.Logger) AnalyticsResources {
+	return AnalyticsResources{
+		logger:           logger,
+		AnalyticsService: NewAnalyticsService(NewRepository(conn, logger), logger),
+	}
+}
+
+type AnalyticsResources struct {
+	AnalyticsService IAnalyticsService
+	logger           *slog.Logger
+}
+
+func (rs AnalyticsResources) getCentrality(c context.Context, input *struct {
+	Metric Metric `query:"metric" enum:"degree,weighted-degree,betweenness,pagerank" default:"pagerank"`
+	Limit  int    `query:"limit" minimum:"1" maximum:"1000" default:"20"`
+}) (*struct {
+	Body centralityResponseBody
+}, error) {
+	response, err := rs.AnalyticsService.GetCentrality(c, input.Metric, input.Limit)
+	if err != nil {
+		rs.logger.Error(err.Error())
+		return nil, err
+	}
+
+	return &struct {
+		Body centralityResponseBody
+	}{
+		Body: response,
+	}, nil
+}
+
+func (rs AnalyticsResources) getCommunities(c context.Context, input *struct {
+	Limit   int `query:"limit" minimum:"1" maximum:"1000" default:"20"`
+	Members int `query:"members" minimum:"1" maximum:"1000" default:"10" doc:"Number of records listed per community"`
+}) (*struct {
+	Body communitiesResponseBody
+}, error) {
+	response, err := rs.AnalyticsService.GetCommunities(c, input.Limit, input.Members)
+	if err != nil {
+		rs.logger.Error(err.Error())
+		return nil, err
+	}
+
+	return &struct {
+		Body communitiesResponseBody
+	}{
+		Body: response,
+	}, nil
+}
+
+func (rs AnalyticsResources) getImpacts(c context.Context, input *struct {
+	From   int    `query:"from" default:"-9999" doc:"First year to include, by the start date of records"`
+	To     int    `query:"to" default:"9999" doc:"Last year to include, by the start date of records"`
+	Bucket Bucket `query:"bucket" enum:"year,decade,century" default:"century"`
+}) (*struct {
+	Body impactAnalyticsResponseBody
+}, error) {
+	if input.From > input.To {
+		return nil, huma.Error400BadRequest("from must not be after to")
+	}
+
+	response, err := rs.AnalyticsService.GetImpacts(c, input.Bucket, input.From, input.To)
+	if err != nil {
+		rs.logger.Error(err.Error())
+		return nil, err
+	}
+
+	return &struct {
+		Body impactAnalyticsResponseBody
+	}{
+		Body: response,
+	}, nil
+}
+
+func (rs AnalyticsResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "get-centrality",
+		Method:      http.MethodGet,
+		Path:        "/analytics/centrality",
+	}, rs.getCentrality)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-communities",
+		Method:      http.MethodGet,
+		Path:        "/analytics/communities",
+	}, rs.getCommunities)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-impact-analytics",
+		Method:      http.MethodGet,
+		Path:        "/analytics/impacts",
+	}, rs.getImpacts)
+}
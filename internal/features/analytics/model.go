@@ -0,0 +1,124 @@
+package analytics
+
+import (
+	"time"
+
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+)
+
+type Metric string
+
+const (
+	Degree         Metric = "degree"
+	WeightedDegree Metric = "weighted-degree"
+	Betweenness    Metric = "betweenness"
+	PageRank       Metric = "pagerank"
+)
+
+// graphAnalysis holds the metrics of every linked record for one revision of
+// the link graph. Records without links are not part of the graph.
+type graphAnalysis struct {
+	Revision   int64
+	ComputedAt time.Time
+	Modularity float64
+	Nodes      []nodeMetrics
+}
+
+type nodeMetrics struct {
+	RecordID       uuid.UUID
+	Degree         int
+	WeightedDegree float64
+	Betweenness    float64
+	PageRank       float64
+	Community      int
+}
+
+func (m nodeMetrics) value(metric Metric) float64 {
+	switch metric {
+	case Degree:
+		return float64(m.Degree)
+	case WeightedDegree:
+		return m.WeightedDegree
+	case Betweenness:
+		return m.Betweenness
+	default:
+		return m.PageRank
+	}
+}
+
+type centralityResponseBody struct {
+	Revision   int64              `json:"revision"`
+	ComputedAt string             `json:"computedAt"`
+	Metric     Metric             `json:"metric"`
+	Records    []recordCentrality `json:"records"`
+}
+
+type recordCentrality struct {
+	RecordID       uuid.UUID   `json:"recordId"`
+	Title          string      `json:"title"`
+	Type           record.Type `json:"type"`
+	Degree         int         `json:"degree"`
+	WeightedDegree float64     `json:"weightedDegree"`
+	Betweenness    float64     `json:"betweenness"`
+	PageRank       float64     `json:"pageRank"`
+	Community      int         `json:"community"`
+}
+
+type communitiesResponseBody struct {
+	Revision    int64               `json:"revision"`
+	ComputedAt  string              `json:"computedAt"`
+	Modularity  float64             `json:"modularity"`
+	Communities []communityResponse `json:"communities"`
+}
+
+type communityResponse struct {
+	ID   int `json:"id"`
+	Size int `json:"size"`
+	// Records are the most central members of the community by PageRank
+	Records []communityMember `json:"records"`
+}
+
+type communityMember struct {
+	RecordID uuid.UUID   `json:"recordId"`
+	Title    string      `json:"title"`
+	Type     record.Type `json:"type"`
+	PageRank float64     `json:"pageRank"`
+}
+
+type Bucket string
+
+const (
+	Year    Bucket = "year"
+	Decade  Bucket = "decade"
+	Century Bucket = "century"
+)
+
+var bucketYears = map[Bucket]int{
+	Year:    1,
+	Decade:  10,
+	Century: 100,
+}
+
+// impactBucketRow is the sum of the impacts of one category in the bucket
+// starting at Year.
+type impactBucketRow struct {
+	Year     float64
+	Category int16
+	Total    int64
+	Impacts  int64
+}
+
+type impactAnalyticsResponseBody struct {
+	Bucket  Bucket                 `json:"bucket"`
+	Buckets []impactBucketResponse `json:"buckets"`
+}
+
+type impactBucketResponse struct {
+	From       int                     `json:"from"`
+	To         int                     `json:"to"`
+	Total      int                     `json:"total"`
+	Impacts    int                     `json:"impacts"`
+	ByCategory map[record.Category]int `json:"byCategory"`
+}
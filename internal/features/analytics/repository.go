@@ -0,0 +1,140 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const lookupChunkSize = 1000
+
+type IAnalyticsRepository interface {
+	GetRevision(c context.Context) (int64, error)
+	GetGraph(c context.Context) (int64, []model.Link, error)
+	GetRecords(c context.Context, ids []uuid.UUID) (map[uuid.UUID]model.Record, error)
+	GetImpactBuckets(c context.Context, size int, from int, to int) ([]impactBucketRow, error)
+}
+
+type AnalyticsRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) IAnalyticsRepository {
+	return AnalyticsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetRevision returns the revision of the link graph, which triggers raise
+// with every change to links.
+func (r AnalyticsRepository) GetRevision(c context.Context) (int64, error) {
+	return r.getRevision(c, r.db)
+}
+
+func (r AnalyticsRepository) getRevision(c context.Context, db qrm.Queryable) (int64, error) {
+	// Changes to links are appended to graph_change until they are folded
+	// into the revision
+	pending := IntExp(SELECT(CAST(COALESCE(SUM(GraphChange.LinkChanges), Int64(0))).AS_BIGINT()).FROM(GraphChange))
+	stmt := SELECT(GraphRevision.Revision.ADD(pending).AS("graph_revision.revision")).
+		FROM(GraphRevision)
+
+	var dest model.GraphRevision
+	if err := stmt.QueryContext(c, db, &dest); err != nil {
+		return 0, fmt.Errorf("error getting graph revision: %w", err)
+	}
+	return dest.Revision, nil
+}
+
+// GetGraph returns all links together with the revision they belong to, read
+// from the same snapshot.
+func (r AnalyticsRepository) GetGraph(c context.Context) (int64, []model.Link, error) {
+	tx, err := r.db.BeginTx(c, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return 0, nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	revision, err := r.getRevision(c, tx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	stmt := SELECT(Link.AllColumns).
+		FROM(Link)
+
+	var links []model.Link
+	if err = stmt.QueryContext(c, tx, &links); err != nil {
+		return 0, nil, fmt.Errorf("error getting links: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return revision, links, nil
+}
+
+func (r AnalyticsRepository) GetRecords(c context.Context, ids []uuid.UUID) (map[uuid.UUID]model.Record, error) {
+	records := make(map[uuid.UUID]model.Record, len(ids))
+	for _, chunk := range lo.Chunk(ids, lookupChunkSize) {
+		stmt := SELECT(Record.AllColumns).
+			FROM(Record).
+			WHERE(Record.ID.IN(lo.Map(chunk, func(id uuid.UUID, index int) Expression { return UUID(id) })...))
+
+		var dest []model.Record
+		if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+			return nil, fmt.Errorf("error getting records: %w", err)
+		}
+		for _, record := range dest {
+			records[record.ID] = record
+		}
+	}
+	return records, nil
+}
+
+// GetImpactBuckets sums impact values per category over buckets of size
+// years, placing each impact in the year its record starts. Records without
+// a start date and removed records are left out.
+func (r AnalyticsRepository) GetImpactBuckets(c context.Context, size int, from int, to int) ([]impactBucketRow, error) {
+	year := EXTRACT(YEAR, Record.StartDate)
+	bucket := FLOOR(year.DIV(Double(float64(size)))).MUL(Double(float64(size)))
+
+	stmt := SELECT(
+		bucket.AS("impact_bucket_row.year"),
+		Impact.Category.AS("impact_bucket_row.category"),
+		SUM(Impact.Value).AS("impact_bucket_row.total"),
+		COUNT(Impact.ID).AS("impact_bucket_row.impacts"),
+	).FROM(
+		Impact.INNER_JOIN(Record, Record.ID.EQ(Impact.RecordID)),
+	).WHERE(
+		Record.StartDate.IS_NOT_NULL().
+			AND(Record.Status.NOT_EQ(Int16(record.Removed.ToInt16()))).
+			AND(year.BETWEEN(Double(float64(from)), Double(float64(to)))),
+	).GROUP_BY(
+		// The bucket is grouped on by its alias, as its parameters would not
+		// match those of the selected expression
+		Raw(`"impact_bucket_row.year"`),
+		Impact.Category,
+	).ORDER_BY(
+		Raw(`"impact_bucket_row.year"`),
+		Impact.Category,
+	)
+
+	var dest []impactBucketRow
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting impact buckets: %w", err)
+	}
+	return dest, nil
+}
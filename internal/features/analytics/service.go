@@ -0,0 +1,206 @@
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type IAnalyticsService interface {
+	GetCentrality(c context.Context, metric Metric, limit int) (centralityResponseBody, error)
+	GetCommunities(c context.Context, limit int, members int) (communitiesResponseBody, error)
+	GetImpacts(c context.Context, bucket Bucket, from int, to int) (impactAnalyticsResponseBody, error)
+}
+
+type AnalyticsService struct {
+	analyticsRepository IAnalyticsRepository
+	logger              *slog.Logger
+	cache               *analysisCache
+}
+
+// analysisCache keeps the analysis of the latest graph revision, as computing
+// betweenness takes a shortest path search from every record.
+type analysisCache struct {
+	mu       sync.Mutex
+	analysis *graphAnalysis
+}
+
+func NewAnalyticsService(analyticsRepository IAnalyticsRepository, logger *slog.Logger) IAnalyticsService {
+	return AnalyticsService{
+		analyticsRepository: analyticsRepository,
+		logger:              logger,
+		cache:               &analysisCache{},
+	}
+}
+
+func (s AnalyticsService) GetCentrality(c context.Context, metric Metric, limit int) (centralityResponseBody, error) {
+	analysis, err := s.analysis(c)
+	if err != nil {
+		return centralityResponseBody{}, err
+	}
+
+	nodes := append([]nodeMetrics(nil), analysis.Nodes...)
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].value(metric) > nodes[j].value(metric) })
+	nodes = nodes[:min(limit, len(nodes))]
+
+	records, err := s.analyticsRepository.GetRecords(c, lo.Map(nodes, func(node nodeMetrics, index int) uuid.UUID { return node.RecordID }))
+	if err != nil {
+		return centralityResponseBody{}, err
+	}
+
+	return centralityResponseBody{
+		Revision:   analysis.Revision,
+		ComputedAt: common.ToDateTimeString(&analysis.ComputedAt),
+		Metric:     metric,
+		Records: lo.Map(nodes, func(node nodeMetrics, index int) recordCentrality {
+			return recordCentrality{
+				RecordID:       node.RecordID,
+				Title:          records[node.RecordID].Title,
+				Type:           record.TypeFromInt16(records[node.RecordID].Type),
+				Degree:         node.Degree,
+				WeightedDegree: node.WeightedDegree,
+				Betweenness:    node.Betweenness,
+				PageRank:       node.PageRank,
+				Community:      node.Community,
+			}
+		}),
+	}, nil
+}
+
+func (s AnalyticsService) GetCommunities(c context.Context, limit int, members int) (communitiesResponseBody, error) {
+	analysis, err := s.analysis(c)
+	if err != nil {
+		return communitiesResponseBody{}, err
+	}
+
+	// Communities are numbered from largest to smallest
+	grouped := lo.GroupBy(analysis.Nodes, func(node nodeMetrics) int { return node.Community })
+	communities := make([]communityResponse, 0, min(limit, len(grouped)))
+	var ids []uuid.UUID
+	for id := 0; id < len(grouped) && id < limit; id++ {
+		nodes := grouped[id]
+		sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].PageRank > nodes[j].PageRank })
+		top := nodes[:min(members, len(nodes))]
+
+		communities = append(communities, communityResponse{
+			ID:   id,
+			Size: len(nodes),
+			Records: lo.Map(top, func(node nodeMetrics, index int) communityMember {
+				return communityMember{RecordID: node.RecordID, PageRank: node.PageRank}
+			}),
+		})
+		ids = append(ids, lo.Map(top, func(node nodeMetrics, index int) uuid.UUID { return node.RecordID })...)
+	}
+
+	records, err := s.analyticsRepository.GetRecords(c, ids)
+	if err != nil {
+		return communitiesResponseBody{}, err
+	}
+	for i := range communities {
+		for j, member := range communities[i].Records {
+			communities[i].Records[j].Title = records[member.RecordID].Title
+			communities[i].Records[j].Type = record.TypeFromInt16(records[member.RecordID].Type)
+		}
+	}
+
+	return communitiesResponseBody{
+		Revision:    analysis.Revision,
+		ComputedAt:  common.ToDateTimeString(&analysis.ComputedAt),
+		Modularity:  analysis.Modularity,
+		Communities: communities,
+	}, nil
+}
+
+// analysis returns the cached analysis while the link graph is unchanged and
+// recomputes it otherwise. Requests arriving during a recomputation wait for
+// it instead of starting their own.
+func (s AnalyticsService) analysis(c context.Context) (*graphAnalysis, error) {
+	revision, err := s.analyticsRepository.GetRevision(c)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	if s.cache.analysis != nil && s.cache.analysis.Revision == revision {
+		return s.cache.analysis, nil
+	}
+
+	revision, links, err := s.analyticsRepository.GetGraph(c)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	s.cache.analysis = analyze(revision, links)
+	s.logger.Info("analyzed link graph", "revision", revision, "records", len(s.cache.analysis.Nodes), "links", len(links), "duration", time.Since(start))
+
+	return s.cache.analysis, nil
+}
+
+func analyze(revision int64, links []model.Link) *graphAnalysis {
+	g := newGraph(links)
+	degree, weightedDegree := g.degrees()
+	betweenness := g.betweenness()
+	pageRank := g.pageRank()
+	communities, modularity := g.communities()
+
+	analysis := &graphAnalysis{
+		Revision:   revision,
+		ComputedAt: time.Now().UTC(),
+		Modularity: modularity,
+		Nodes:      make([]nodeMetrics, len(g.ids)),
+	}
+	for i, id := range g.ids {
+		analysis.Nodes[i] = nodeMetrics{
+			RecordID:       id,
+			Degree:         degree[i],
+			WeightedDegree: weightedDegree[i],
+			Betweenness:    betweenness[i],
+			PageRank:       pageRank[i],
+			Community:      communities[i],
+		}
+	}
+	return analysis
+}
+
+func (s AnalyticsService) GetImpacts(c context.Context, bucket Bucket, from int, to int) (impactAnalyticsResponseBody, error) {
+	size := bucketYears[bucket]
+	rows, err := s.analyticsRepository.GetImpactBuckets(c, size, from, to)
+	if err != nil {
+		return impactAnalyticsResponseBody{}, err
+	}
+
+	buckets := []impactBucketResponse{}
+	for _, row := range rows {
+		start := int(row.Year)
+		if len(buckets) == 0 || buckets[len(buckets)-1].From != start {
+			_, byCategory := record.ImpactTotals(nil)
+			buckets = append(buckets, impactBucketResponse{
+				From:       start,
+				To:         start + size - 1,
+				ByCategory: byCategory,
+			})
+		}
+
+		current := &buckets[len(buckets)-1]
+		current.Total += int(row.Total)
+		current.Impacts += int(row.Impacts)
+		current.ByCategory[record.CategoryFromInt16(row.Category)] += int(row.Total)
+	}
+
+	return impactAnalyticsResponseBody{
+		Bucket:  bucket,
+		Buckets: buckets,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package arc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+func NewArcResources(conn *sql.DB, logger *slog.Logger) ArcResources {
+	return ArcResources{
+		logger:     logger,
+		ArcService: NewArcService(NewRepository(conn, logger), logger),
+	}
+}
+
+type ArcResources struct {
+	ArcService IArcService
+	logger     *slog.Logger
+}
+
+func (rs ArcResources) getMembers(c context.Context, input *struct {
+	ArcID uuid.UUID `path:"arcId"`
+}) (*struct {
+	Body []memberResponseBody
+}, error) {
+	members, err := rs.ArcService.GetMembers(c, input.ArcID)
+	if err != nil {
+		return nil, arcError(err)
+	}
+
+	if members == nil {
+		members = []memberResponseBody{}
+	}
+
+	return &struct {
+		Body []memberResponseBody
+	}{
+		Body: members,
+	}, nil
+}
+
+func (rs ArcResources) replaceMembers(c context.Context, input *struct {
+	common.ActorInput
+	ArcID uuid.UUID `path:"arcId"`
+	Body  replaceMembersCommandBody
+}) (*struct {
+	Body []memberResponseBody
+}, error) {
+	members, err := rs.ArcService.ReplaceMembers(input.WithActor(c), input.ArcID, input.Body)
+	if err != nil {
+		return nil, arcError(err)
+	}
+
+	if members == nil {
+		members = []memberResponseBody{}
+	}
+
+	return &struct {
+		Body []memberResponseBody
+	}{
+		Body: members,
+	}, nil
+}
+
+func (rs ArcResources) getArcs(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body []arcResponseBody
+}, error) {
+	arcs, err := rs.ArcService.GetArcs(c, input.ID)
+	if err != nil {
+		return nil, arcError(err)
+	}
+
+	if arcs == nil {
+		arcs = []arcResponseBody{}
+	}
+
+	return &struct {
+		Body []arcResponseBody
+	}{
+		Body: arcs,
+	}, nil
+}
+
+func arcError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, common.ErrNotAnArc), errors.Is(err, common.ErrDuplicateArcMember):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, common.ErrArcCycle):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return err
+	}
+}
+
+func (rs ArcResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "get-arc-members",
+		Method:      http.MethodGet,
+		Path:        "/records/{arcId}/members",
+	}, rs.getMembers)
+	huma.Register(s, huma.Operation{
+		OperationID: "replace-arc-members",
+		Method:      http.MethodPut,
+		Path:        "/records/{arcId}/members",
+		Description: "Sets the members of an arc in the order given. Arcs can contain other arcs, but never themselves.",
+	}, rs.replaceMembers)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-record-arcs",
+		Method:      http.MethodGet,
+		Path:        "/records/{id}/arcs",
+	}, rs.getArcs)
+}
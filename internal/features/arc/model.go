@@ -0,0 +1,69 @@
+package arc
+
+import (
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+)
+
+type memberCommandBody struct {
+	RecordID uuid.UUID `json:"recordId"`
+	Chapter  *string   `json:"chapter,omitempty" maxLength:"255"`
+	Role     *string   `json:"role,omitempty" maxLength:"255"`
+}
+
+type replaceMembersCommandBody struct {
+	Members []memberCommandBody `json:"members" doc:"Records in the arc, in the order the arc tells them"`
+}
+
+type memberResponseBody struct {
+	Position  int         `json:"position"`
+	RecordID  uuid.UUID   `json:"recordId"`
+	Title     string      `json:"title"`
+	Type      record.Type `json:"type"`
+	StartDate string      `json:"startDate"`
+	EndDate   string      `json:"endDate"`
+	Chapter   *string     `json:"chapter"`
+	Role      *string     `json:"role"`
+}
+
+type arcResponseBody struct {
+	ArcID    uuid.UUID `json:"arcId"`
+	Title    string    `json:"title"`
+	Position int       `json:"position"`
+	Chapter  *string   `json:"chapter"`
+	Role     *string   `json:"role"`
+}
+
+// ArcMembership is the membership of a record in an arc, joined with either
+// the member or the arc depending on which side is being listed.
+type ArcMembership struct {
+	model.ArcMember
+
+	Record model.Record
+}
+
+func mapMemberResponseBody(m ArcMembership, index int) memberResponseBody {
+	return memberResponseBody{
+		Position:  int(m.Position),
+		RecordID:  m.RecordID,
+		Title:     m.Record.Title,
+		Type:      record.TypeFromInt16(m.Record.Type),
+		StartDate: common.ToDateString(m.Record.StartDate),
+		EndDate:   common.ToDateString(m.Record.EndDate),
+		Chapter:   m.Chapter,
+		Role:      m.Role,
+	}
+}
+
+func mapArcResponseBody(m ArcMembership, index int) arcResponseBody {
+	return arcResponseBody{
+		ArcID:    m.ArcID,
+		Title:    m.Record.Title,
+		Position: int(m.Position),
+		Chapter:  m.Chapter,
+		Role:     m.Role,
+	}
+}
@@ -0,0 +1,171 @@
+package arc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type IArcRepository interface {
+	GetRecord(c context.Context, id uuid.UUID) (model.Record, error)
+	GetMembers(c context.Context, arcId uuid.UUID) ([]ArcMembership, error)
+	GetArcs(c context.Context, recordId uuid.UUID) ([]ArcMembership, error)
+	ReplaceMembers(c context.Context, arcId uuid.UUID, members []model.ArcMember) error
+}
+
+type ArcRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) IArcRepository {
+	return ArcRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r ArcRepository) GetRecord(c context.Context, id uuid.UUID) (model.Record, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.EQ(UUID(id)))
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return model.Record{}, fmt.Errorf("failed to get record: %w", err)
+	}
+	if len(records) == 0 {
+		return model.Record{}, common.ErrRecordNotFound
+	}
+	return records[0], nil
+}
+
+// GetMembers returns the records in an arc in order, leaving out removed ones.
+func (r ArcRepository) GetMembers(c context.Context, arcId uuid.UUID) ([]ArcMembership, error) {
+	stmt := SELECT(
+		ArcMember.AllColumns,
+		Record.AllColumns,
+	).FROM(
+		ArcMember.INNER_JOIN(Record, Record.ID.EQ(ArcMember.RecordID)),
+	).WHERE(
+		ArcMember.ArcID.EQ(UUID(arcId)).
+			AND(Record.Status.NOT_EQ(Int16(record.Removed.ToInt16()))),
+	).ORDER_BY(ArcMember.Position)
+
+	var dest []ArcMembership
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("failed to get arc members: %w", err)
+	}
+	return dest, nil
+}
+
+// GetArcs returns the arcs a record is a direct member of, leaving out
+// removed ones.
+func (r ArcRepository) GetArcs(c context.Context, recordId uuid.UUID) ([]ArcMembership, error) {
+	stmt := SELECT(
+		ArcMember.AllColumns,
+		Record.AllColumns,
+	).FROM(
+		ArcMember.INNER_JOIN(Record, Record.ID.EQ(ArcMember.ArcID)),
+	).WHERE(
+		ArcMember.RecordID.EQ(UUID(recordId)).
+			AND(Record.Status.NOT_EQ(Int16(record.Removed.ToInt16()))),
+	).ORDER_BY(Record.Title, Record.ID)
+
+	var dest []ArcMembership
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("failed to get arcs: %w", err)
+	}
+	return dest, nil
+}
+
+// ReplaceMembers sets the members of an arc, refusing members that would
+// make the arc contain itself through the arcs among them.
+func (r ArcRepository) ReplaceMembers(c context.Context, arcId uuid.UUID, members []model.ArcMember) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = common.SetActor(c, tx); err != nil {
+		return err
+	}
+
+	// Two arcs gaining each other as members at the same time would each pass
+	// the cycle check on their own, so membership changes run one at a time
+	if _, err = ArcMember.LOCK().IN(LOCK_SHARE_ROW_EXCLUSIVE).ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to lock arc members: %w", err)
+	}
+
+	ids := lo.Map(members, func(member model.ArcMember, index int) uuid.UUID { return member.RecordID })
+	if len(ids) > 0 {
+		recordStmt := SELECT(Record.ID).
+			FROM(Record).
+			WHERE(Record.ID.IN(uuids(ids)...).
+				AND(Record.Status.NOT_EQ(Int16(record.Removed.ToInt16()))))
+
+		var records []model.Record
+		if err = recordStmt.QueryContext(c, tx, &records); err != nil {
+			return fmt.Errorf("failed to get member records: %w", err)
+		}
+		if len(records) != len(ids) {
+			return common.ErrRecordNotFound
+		}
+	}
+
+	// Walk down from the new members; reaching the arc means it would end up
+	// inside itself
+	seen := map[uuid.UUID]bool{}
+	for level := ids; len(level) > 0; {
+		for _, id := range level {
+			if id == arcId {
+				return common.ErrArcCycle
+			}
+			seen[id] = true
+		}
+
+		childStmt := SELECT(ArcMember.RecordID).
+			FROM(ArcMember).
+			WHERE(ArcMember.ArcID.IN(uuids(level)...))
+
+		var children []model.ArcMember
+		if err = childStmt.QueryContext(c, tx, &children); err != nil {
+			return fmt.Errorf("failed to get arc members: %w", err)
+		}
+		level = lo.Uniq(lo.FilterMap(children, func(child model.ArcMember, index int) (uuid.UUID, bool) {
+			return child.RecordID, !seen[child.RecordID]
+		}))
+	}
+
+	if _, err = ArcMember.DELETE().WHERE(ArcMember.ArcID.EQ(UUID(arcId))).ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to delete arc members: %w", err)
+	}
+
+	if len(members) > 0 {
+		insertStmt := ArcMember.INSERT(ArcMember.AllColumns).
+			MODELS(members)
+		if _, err = insertStmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("failed to insert arc members: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func uuids(ids []uuid.UUID) []Expression {
+	return lo.Map(ids, func(id uuid.UUID, index int) Expression { return UUID(id) })
+}
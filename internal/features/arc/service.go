@@ -0,0 +1,101 @@
+package arc
+
+import (
+	"context"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type IArcService interface {
+	GetMembers(c context.Context, arcId uuid.UUID) ([]memberResponseBody, error)
+	ReplaceMembers(c context.Context, arcId uuid.UUID, command replaceMembersCommandBody) ([]memberResponseBody, error)
+	GetArcs(c context.Context, recordId uuid.UUID) ([]arcResponseBody, error)
+}
+
+type ArcService struct {
+	arcRepository IArcRepository
+	logger        *slog.Logger
+}
+
+func NewArcService(arcRepository IArcRepository, logger *slog.Logger) IArcService {
+	return ArcService{
+		arcRepository: arcRepository,
+		logger:        logger,
+	}
+}
+
+func (s ArcService) GetMembers(c context.Context, arcId uuid.UUID) ([]memberResponseBody, error) {
+	if err := s.ensureArc(c, arcId); err != nil {
+		return nil, err
+	}
+
+	members, err := s.arcRepository.GetMembers(c, arcId)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(members, mapMemberResponseBody), nil
+}
+
+// ReplaceMembers sets the members of an arc in the order they are given,
+// numbering their positions from one.
+func (s ArcService) ReplaceMembers(c context.Context, arcId uuid.UUID, command replaceMembersCommandBody) ([]memberResponseBody, error) {
+	if err := s.ensureArc(c, arcId); err != nil {
+		return nil, err
+	}
+
+	seen := map[uuid.UUID]bool{}
+	members := make([]model.ArcMember, len(command.Members))
+	for i, member := range command.Members {
+		if member.RecordID == arcId {
+			return nil, common.ErrArcCycle
+		}
+		if seen[member.RecordID] {
+			return nil, common.ErrDuplicateArcMember
+		}
+		seen[member.RecordID] = true
+
+		members[i] = model.ArcMember{
+			ArcID:    arcId,
+			RecordID: member.RecordID,
+			Position: int32(i + 1),
+			Chapter:  member.Chapter,
+			Role:     member.Role,
+		}
+	}
+
+	if err := s.arcRepository.ReplaceMembers(c, arcId, members); err != nil {
+		return nil, err
+	}
+	s.logger.Info("replaced arc members", "arc", arcId, "members", len(members))
+
+	return s.GetMembers(c, arcId)
+}
+
+func (s ArcService) GetArcs(c context.Context, recordId uuid.UUID) ([]arcResponseBody, error) {
+	if _, err := s.arcRepository.GetRecord(c, recordId); err != nil {
+		return nil, err
+	}
+
+	arcs, err := s.arcRepository.GetArcs(c, recordId)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(arcs, mapArcResponseBody), nil
+}
+
+func (s ArcService) ensureArc(c context.Context, id uuid.UUID) error {
+	arc, err := s.arcRepository.GetRecord(c, id)
+	if err != nil {
+		return err
+	}
+	if record.TypeFromInt16(arc.Type) != record.Arc {
+		return common.ErrNotAnArc
+	}
+	return nil
+}
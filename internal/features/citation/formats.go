@@ -0,0 +1,180 @@
+package citation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/features/record"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func cite(source model.Source) citedSource {
+	authors := record.SourceAuthors(source)
+	return citedSource{
+		Source:  source,
+		Key:     citationKey(source, authors),
+		Type:    record.SourceTypeFromInt16(source.Type),
+		Authors: authors,
+	}
+}
+
+// citationKey is the family name of the first author and the year, followed
+// by the start of the id of the source so keys stay unique and stable, such
+// as keegan1998-3f2a9c1b.
+func citationKey(source model.Source, authors []string) string {
+	var key strings.Builder
+	if len(authors) > 0 {
+		family, _, _ := strings.Cut(authors[0], ",")
+		for _, r := range norm.NFD.String(family) {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				key.WriteRune(unicode.ToLower(r))
+			}
+		}
+	}
+	if key.Len() == 0 {
+		key.WriteString("source")
+	}
+	if source.Year != nil {
+		key.WriteString(strconv.Itoa(int(*source.Year)))
+	}
+	key.WriteString("-")
+	key.WriteString(source.ID.String()[:8])
+	return key.String()
+}
+
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// bibtexURLEscaper percent-encodes the characters that would end or break a
+// url field. The url package reads the rest verbatim.
+var bibtexURLEscaper = strings.NewReplacer(
+	`\`, `%5C`,
+	`{`, `%7B`,
+	`}`, `%7D`,
+)
+
+func formatBibTeX(sources []citedSource) []byte {
+	var b bytes.Buffer
+	for i, source := range sources {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "@%s{%s,\n", citationTypes[source.Type].bibtex, source.Key)
+		field := func(name string, value string) {
+			if value != "" {
+				fmt.Fprintf(&b, "  %s = {%s},\n", name, bibtexEscaper.Replace(value))
+			}
+		}
+
+		field("title", source.Title)
+		field("author", strings.Join(source.Authors, " and "))
+		if source.Year != nil {
+			field("year", strconv.Itoa(int(*source.Year)))
+		}
+		field("publisher", deref(source.Publisher))
+		switch source.Type {
+		case record.Article:
+			field("journal", deref(source.ContainerTitle))
+		case record.Chapter:
+			field("booktitle", deref(source.ContainerTitle))
+		}
+		if source.URL != "" {
+			fmt.Fprintf(&b, "  url = {%s},\n", bibtexURLEscaper.Replace(source.URL))
+		}
+		field("note", deref(source.Description))
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
+
+// formatRIS writes the tagged format of reference managers, which ends lines
+// with CRLF.
+func formatRIS(sources []citedSource) []byte {
+	var b bytes.Buffer
+	tag := func(name string, value string) {
+		if value != "" {
+			// Values cannot span lines
+			value = strings.Join(strings.Fields(value), " ")
+			fmt.Fprintf(&b, "%s  - %s\r\n", name, value)
+		}
+	}
+
+	for _, source := range sources {
+		tag("TY", citationTypes[source.Type].ris)
+		tag("ID", source.Key)
+		tag("TI", source.Title)
+		for _, author := range source.Authors {
+			tag("AU", author)
+		}
+		if source.Year != nil {
+			tag("PY", strconv.Itoa(int(*source.Year)))
+		}
+		tag("PB", deref(source.Publisher))
+		tag("T2", deref(source.ContainerTitle))
+		tag("UR", source.URL)
+		tag("N1", deref(source.Description))
+		b.WriteString("ER  - \r\n")
+	}
+	return b.Bytes()
+}
+
+func formatCSLJSON(sources []citedSource) ([]byte, error) {
+	items := make([]cslItem, len(sources))
+	for i, source := range sources {
+		item := cslItem{
+			ID:             source.Key,
+			Type:           citationTypes[source.Type].csl,
+			Title:          source.Title,
+			Publisher:      source.Publisher,
+			ContainerTitle: source.ContainerTitle,
+			URL:            source.URL,
+			Note:           source.Description,
+		}
+		for _, author := range source.Authors {
+			item.Author = append(item.Author, cslAuthor(author))
+		}
+		if source.Year != nil {
+			item.Issued = &cslDate{DateParts: [][]int{{int(*source.Year)}}}
+		}
+		items[i] = item
+	}
+
+	document, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CSL-JSON: %w", err)
+	}
+	return document, nil
+}
+
+// cslAuthor splits a name written as "Family, Given". Names without a comma,
+// such as organisations, are kept whole.
+func cslAuthor(name string) cslName {
+	family, given, found := strings.Cut(name, ",")
+	if !found {
+		return cslName{Literal: strings.TrimSpace(name)}
+	}
+	return cslName{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,142 @@
+package citation
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+func TestCitationKey(t *testing.T) {
+	id := uuid.MustParse("3f2a9c1b-5d4e-4f60-8a7b-9c0d1e2f3a4b")
+
+	tests := []struct {
+		name     string
+		authors  []string
+		year     *int16
+		expected string
+	}{
+		{name: "family name and year", authors: []string{"Keegan, John", "Holmes, Richard"}, year: lo.ToPtr[int16](1998), expected: "keegan1998-3f2a9c1b"},
+		{name: "without year", authors: []string{"Keegan, John"}, expected: "keegan-3f2a9c1b"},
+		{name: "accents and spaces", authors: []string{"Ó Súilleabháin, Seán"}, year: lo.ToPtr[int16](1942), expected: "osuilleabhain1942-3f2a9c1b"},
+		{name: "organisation", authors: []string{"Royal Historical Society"}, expected: "royalhistoricalsociety-3f2a9c1b"},
+		{name: "without latin letters", authors: []string{"Толстой, Лев"}, year: lo.ToPtr[int16](1869), expected: "source1869-3f2a9c1b"},
+		{name: "without authors", expected: "source-3f2a9c1b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := citationKey(model.Source{ID: id, Year: tt.year}, tt.authors)
+			if got != tt.expected {
+				t.Errorf("citationKey() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func testSources() []citedSource {
+	book := model.Source{
+		ID:          uuid.MustParse("3f2a9c1b-5d4e-4f60-8a7b-9c0d1e2f3a4b"),
+		Title:       "War & Peace: 100% of it",
+		Type:        record.Book.ToInt16(),
+		URL:         `https://example.org/{id}\scans?q=a_b~c`,
+		Description: lo.ToPtr("Costs ~5^2\n$ #1"),
+		Authors:     `["Keegan, John","Royal Historical Society"]`,
+		Year:        lo.ToPtr[int16](1998),
+		Publisher:   lo.ToPtr("Hutchinson"),
+	}
+	article := model.Source{
+		ID:             uuid.MustParse("0c7e5d2a-1b3c-4d5e-8f70-a1b2c3d4e5f6"),
+		Title:          "Les rois thaumaturges",
+		Type:           record.Article.ToInt16(),
+		Authors:        `["Bloch, Marc"]`,
+		ContainerTitle: lo.ToPtr("Annales"),
+	}
+	return []citedSource{cite(book), cite(article)}
+}
+
+func TestFormatBibTeX(t *testing.T) {
+	expected := `@book{keegan1998-3f2a9c1b,
+  title = {War \& Peace: 100\% of it},
+  author = {Keegan, John and Royal Historical Society},
+  year = {1998},
+  publisher = {Hutchinson},
+  url = {https://example.org/%7Bid%7D%5Cscans?q=a_b~c},
+  note = {Costs \textasciitilde{}5\textasciicircum{}2
+\$ \#1},
+}
+
+@article{bloch-0c7e5d2a,
+  title = {Les rois thaumaturges},
+  author = {Bloch, Marc},
+  journal = {Annales},
+}
+`
+	if got := string(formatBibTeX(testSources())); got != expected {
+		t.Errorf("formatBibTeX() =\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestFormatRIS(t *testing.T) {
+	expected := "TY  - BOOK\r\n" +
+		"ID  - keegan1998-3f2a9c1b\r\n" +
+		"TI  - War & Peace: 100% of it\r\n" +
+		"AU  - Keegan, John\r\n" +
+		"AU  - Royal Historical Society\r\n" +
+		"PY  - 1998\r\n" +
+		"PB  - Hutchinson\r\n" +
+		`UR  - https://example.org/{id}\scans?q=a_b~c` + "\r\n" +
+		"N1  - Costs ~5^2 $ #1\r\n" +
+		"ER  - \r\n" +
+		"TY  - JOUR\r\n" +
+		"ID  - bloch-0c7e5d2a\r\n" +
+		"TI  - Les rois thaumaturges\r\n" +
+		"AU  - Bloch, Marc\r\n" +
+		"T2  - Annales\r\n" +
+		"ER  - \r\n"
+	if got := string(formatRIS(testSources())); got != expected {
+		t.Errorf("formatRIS() =\n%q\nexpected\n%q", got, expected)
+	}
+}
+
+func TestFormatCSLJSON(t *testing.T) {
+	document, err := formatCSLJSON(testSources())
+	if err != nil {
+		t.Fatalf("formatCSLJSON() error = %v", err)
+	}
+
+	var got []cslItem
+	if err := json.Unmarshal(document, &got); err != nil {
+		t.Fatalf("formatCSLJSON() is not a list of items: %v", err)
+	}
+	expected := []cslItem{
+		{
+			ID:    "keegan1998-3f2a9c1b",
+			Type:  "book",
+			Title: "War & Peace: 100% of it",
+			Author: []cslName{
+				{Family: "Keegan", Given: "John"},
+				{Literal: "Royal Historical Society"},
+			},
+			Issued:    &cslDate{DateParts: [][]int{{1998}}},
+			Publisher: lo.ToPtr("Hutchinson"),
+			URL:       `https://example.org/{id}\scans?q=a_b~c`,
+			Note:      lo.ToPtr("Costs ~5^2\n$ #1"),
+		},
+		{
+			ID:             "bloch-0c7e5d2a",
+			Type:           "article-journal",
+			Title:          "Les rois thaumaturges",
+			Author:         []cslName{{Family: "Bloch", Given: "Marc"}},
+			ContainerTitle: lo.ToPtr("Annales"),
+		},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("formatCSLJSON() = %s", document)
+	}
+}
@@ -0,0 +1,188 @@
+package citation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+func NewCitationResources(conn *sql.DB, logger *slog.Logger, baseURL string) CitationResources {
+	return CitationResources{
+		logger:          logger,
+		baseURL:         baseURL,
+		CitationService: NewCitationService(NewRepository(conn, logger), logger),
+	}
+}
+
+type CitationResources struct {
+	CitationService ICitationService
+	logger          *slog.Logger
+	baseURL         string
+}
+
+type citationOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        []byte
+}
+
+func (rs CitationResources) getRecordCitations(c context.Context, input *struct {
+	ID     uuid.UUID `path:"id"`
+	Format Format    `query:"format" enum:"bibtex,ris,csl-json" default:"bibtex"`
+}) (*citationOutput, error) {
+	document, err := rs.CitationService.GetRecordCitations(c, input.ID, input.Format)
+	if err != nil {
+		return nil, citationError(err)
+	}
+
+	return &citationOutput{
+		ContentType: contentTypes[input.Format],
+		Body:        document,
+	}, nil
+}
+
+func (rs CitationResources) getCitations(c context.Context, input *struct {
+	Format  Format    `query:"format" enum:"bibtex,ris,csl-json" default:"bibtex"`
+	Records []string  `query:"record" maxItems:"100" doc:"Records to cite the sources of"`
+	Around  uuid.UUID `query:"around" doc:"Record to cite the sources of along with the sources of the records linked to it"`
+	Depth   int       `query:"depth" minimum:"1" maximum:"3" default:"1" doc:"Number of links to follow from the around record"`
+}) (*citationOutput, error) {
+	var ids []uuid.UUID
+	for _, record := range input.Records {
+		id, err := uuid.Parse(record)
+		if err != nil {
+			return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("invalid record id %q", record))
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 && input.Around == uuid.Nil {
+		return nil, huma.Error422UnprocessableEntity("give the records to cite, the record to cite the neighborhood of, or both")
+	}
+
+	document, err := rs.CitationService.GetCitations(c, ids, input.Around, input.Depth, input.Format)
+	if err != nil {
+		return nil, citationError(err)
+	}
+
+	return &citationOutput{
+		ContentType: contentTypes[input.Format],
+		Body:        document,
+	}, nil
+}
+
+func (rs CitationResources) getRevisions(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body []revisionSummary
+}, error) {
+	revisions, err := rs.CitationService.GetRevisions(c, input.ID, rs.baseURL)
+	if err != nil {
+		return nil, citationError(err)
+	}
+
+	return &struct {
+		Body []revisionSummary
+	}{
+		Body: revisions,
+	}, nil
+}
+
+func (rs CitationResources) getRevision(c context.Context, input *struct {
+	ID         uuid.UUID `path:"id"`
+	RevisionID uuid.UUID `path:"revisionId"`
+}) (*struct {
+	Body revisionResponse
+}, error) {
+	revision, err := rs.CitationService.GetRevision(c, input.ID, input.RevisionID, rs.baseURL)
+	if err != nil {
+		return nil, citationError(err)
+	}
+
+	return &struct {
+		Body revisionResponse
+	}{
+		Body: revision,
+	}, nil
+}
+
+func (rs CitationResources) citeRecord(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body revisionResponse
+}, error) {
+	revision, err := rs.CitationService.GetCurrentRevision(c, input.ID, rs.baseURL)
+	if err != nil {
+		return nil, citationError(err)
+	}
+
+	return &struct {
+		Body revisionResponse
+	}{
+		Body: revision,
+	}, nil
+}
+
+func citationError(err error) error {
+	if errors.Is(err, common.ErrRecordNotFound) || errors.Is(err, common.ErrRevisionNotFound) {
+		return huma.Error404NotFound(err.Error())
+	}
+	return err
+}
+
+func citationResponses(description string) map[string]*huma.Response {
+	return map[string]*huma.Response{
+		"200": {
+			Description: description,
+			Content: map[string]*huma.MediaType{
+				contentTypes[BibTeX]:  {Schema: &huma.Schema{Type: huma.TypeString}},
+				contentTypes[RIS]:     {Schema: &huma.Schema{Type: huma.TypeString}},
+				contentTypes[CSLJSON]: {Schema: &huma.Schema{Type: huma.TypeArray, Items: &huma.Schema{Type: huma.TypeObject}}},
+			},
+		},
+	}
+}
+
+func (rs CitationResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID:   "get-record-citations",
+		Method:        http.MethodGet,
+		Path:          "/records/{id}/citations",
+		DefaultStatus: http.StatusOK,
+		Responses:     citationResponses("Sources of the record, ready to import into a reference manager"),
+	}, rs.getRecordCitations)
+	huma.Register(s, huma.Operation{
+		OperationID:   "get-citations",
+		Method:        http.MethodGet,
+		Path:          "/citations",
+		DefaultStatus: http.StatusOK,
+		Description:   "Cites the sources of a set of records, of the records around a record in the graph, or of both. Removed records are not followed.",
+		Responses:     citationResponses("Sources of the records, ready to import into a reference manager"),
+	}, rs.getCitations)
+	huma.Register(s, huma.Operation{
+		OperationID:   "get-record-revisions",
+		Method:        http.MethodGet,
+		Path:          "/records/{id}/revisions",
+		DefaultStatus: http.StatusOK,
+		Description:   "Lists the revisions of a record oldest first, each with a permalink that keeps pointing at the record as it was.",
+	}, rs.getRevisions)
+	huma.Register(s, huma.Operation{
+		OperationID:   "get-record-revision",
+		Method:        http.MethodGet,
+		Path:          "/records/{id}/revisions/{revisionId}",
+		DefaultStatus: http.StatusOK,
+		Description:   "Gives the record as it was at a revision, with a citation and DOI-style metadata for that revision. Revisions hold the title, description, location, significance, url, dates, type and status of the record; its impacts, attributes, labels, aliases and confidence are not versioned.",
+	}, rs.getRevision)
+	huma.Register(s, huma.Operation{
+		OperationID:   "cite-record",
+		Method:        http.MethodGet,
+		Path:          "/records/{id}/cite",
+		DefaultStatus: http.StatusOK,
+		Description:   "Cites the current revision of a record.",
+	}, rs.citeRecord)
+}
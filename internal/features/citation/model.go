@@ -0,0 +1,143 @@
+package citation
+
+import (
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	BibTeX  Format = "bibtex"
+	RIS     Format = "ris"
+	CSLJSON Format = "csl-json"
+)
+
+var contentTypes = map[Format]string{
+	BibTeX:  "application/x-bibtex",
+	RIS:     "application/x-research-info-systems",
+	CSLJSON: "application/vnd.citationstyles.csl+json",
+}
+
+// citationTypes are the entry types each format cites a type of source as.
+var citationTypes = map[record.SourceType]struct {
+	bibtex string
+	ris    string
+	csl    string
+}{
+	record.Book:    {"book", "BOOK", "book"},
+	record.Article: {"article", "JOUR", "article-journal"},
+	record.Chapter: {"incollection", "CHAP", "chapter"},
+	record.Website: {"misc", "ELEC", "webpage"},
+	record.Archive: {"unpublished", "MANSCPT", "manuscript"},
+	record.Other:   {"misc", "GEN", "document"},
+}
+
+// cslItem is a source as a CSL-JSON item, the format Zotero and citeproc
+// read.
+type cslItem struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Title          string    `json:"title"`
+	Author         []cslName `json:"author,omitempty"`
+	Issued         *cslDate  `json:"issued,omitempty"`
+	Publisher      *string   `json:"publisher,omitempty"`
+	ContainerTitle *string   `json:"container-title,omitempty"`
+	URL            string    `json:"URL,omitempty"`
+	Note           *string   `json:"note,omitempty"`
+}
+
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// citedSource is a source with what citing it needs decoded.
+type citedSource struct {
+	model.Source
+	Key     string
+	Type    record.SourceType
+	Authors []string
+}
+
+// publisher is the name records are cited as published under.
+const publisher = "History Link"
+
+type revisionSummary struct {
+	ID uuid.UUID `json:"id"`
+	// Number counts the revisions of the record from 1
+	Number    int    `json:"number"`
+	Permalink string `json:"permalink"`
+	RevisedAt string `json:"revisedAt"`
+}
+
+// revisionResponse is a record as it was at one revision, with what is
+// needed to cite exactly that revision. A revision only holds the fields
+// below: impacts, attributes, labels, aliases and confidence are not
+// versioned.
+type revisionResponse struct {
+	ID           uuid.UUID           `json:"id"`
+	RecordID     uuid.UUID           `json:"recordId"`
+	Number       int                 `json:"number"`
+	Current      bool                `json:"current"`
+	Permalink    string              `json:"permalink"`
+	RevisedAt    string              `json:"revisedAt"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	Location     *string             `json:"location"`
+	Significance *string             `json:"significance"`
+	Url          string              `json:"url"`
+	StartDate    string              `json:"startDate"`
+	EndDate      string              `json:"endDate"`
+	RecordStatus record.RecordStatus `json:"recordStatus"`
+	Type         record.Type         `json:"type"`
+	// Citation is the revision cited as plain text
+	Citation string           `json:"citation"`
+	Metadata revisionMetadata `json:"metadata"`
+}
+
+// revisionMetadata describes a revision the way DataCite describes a
+// resource with a DOI, with the permalink standing in for the DOI.
+type revisionMetadata struct {
+	Identifier         metadataIdentifier          `json:"identifier"`
+	Titles             []metadataTitle             `json:"titles"`
+	Publisher          string                      `json:"publisher"`
+	PublicationYear    int                         `json:"publicationYear"`
+	ResourceType       metadataResourceType        `json:"resourceType"`
+	Version            string                      `json:"version"`
+	Dates              []metadataDate              `json:"dates"`
+	RelatedIdentifiers []metadataRelatedIdentifier `json:"relatedIdentifiers"`
+	URL                string                      `json:"url"`
+}
+
+type metadataIdentifier struct {
+	Identifier     string `json:"identifier"`
+	IdentifierType string `json:"identifierType"`
+}
+
+type metadataTitle struct {
+	Title string `json:"title"`
+}
+
+type metadataResourceType struct {
+	ResourceTypeGeneral string `json:"resourceTypeGeneral"`
+	ResourceType        string `json:"resourceType"`
+}
+
+type metadataDate struct {
+	Date     string `json:"date"`
+	DateType string `json:"dateType"`
+}
+
+type metadataRelatedIdentifier struct {
+	RelatedIdentifier     string `json:"relatedIdentifier"`
+	RelatedIdentifierType string `json:"relatedIdentifierType"`
+	RelationType          string `json:"relationType"`
+}
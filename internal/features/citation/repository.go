@@ -0,0 +1,117 @@
+package citation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ICitationRepository interface {
+	GetRecords(c context.Context, ids []uuid.UUID) ([]model.Record, error)
+	GetSources(c context.Context, recordIds []uuid.UUID) ([]model.Source, error)
+	GetNeighbors(c context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	GetRevisions(c context.Context, id uuid.UUID) ([]model.RecordHistory, error)
+}
+
+type CitationRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) ICitationRepository {
+	return CitationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetRecords returns the records with these ids, failing when one of them
+// does not exist.
+func (r CitationRepository) GetRecords(c context.Context, ids []uuid.UUID) ([]model.Record, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.IN(uuids(ids)...))
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return nil, fmt.Errorf("failed to get records: %w", err)
+	}
+	if len(records) != len(ids) {
+		return nil, common.ErrRecordNotFound
+	}
+	return records, nil
+}
+
+// GetRevisions returns the revisions of a record oldest first, in the order
+// that makes the last one its current revision.
+func (r CitationRepository) GetRevisions(c context.Context, id uuid.UUID) ([]model.RecordHistory, error) {
+	stmt := SELECT(RecordHistory.AllColumns).
+		FROM(RecordHistory).
+		WHERE(RecordHistory.RecordID.EQ(UUID(id))).
+		ORDER_BY(RecordHistory.Seq)
+
+	var revisions []model.RecordHistory
+	if err := stmt.QueryContext(c, r.db, &revisions); err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	if len(revisions) == 0 {
+		return nil, common.ErrRecordNotFound
+	}
+	return revisions, nil
+}
+
+func (r CitationRepository) GetSources(c context.Context, recordIds []uuid.UUID) ([]model.Source, error) {
+	stmt := SELECT(Source.AllColumns).
+		FROM(Source).
+		WHERE(Source.RecordID.IN(uuids(recordIds)...)).
+		ORDER_BY(Source.Title, Source.ID)
+
+	var sources []model.Source
+	if err := stmt.QueryContext(c, r.db, &sources); err != nil {
+		return nil, fmt.Errorf("failed to get sources: %w", err)
+	}
+	return sources, nil
+}
+
+// GetNeighbors returns the records linked to any of these records in either
+// direction, leaving out removed records.
+func (r CitationRepository) GetNeighbors(c context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	frontier := uuids(ids)
+	stmt := SELECT(Record.ID).
+		FROM(Record).
+		WHERE(
+			Record.Status.NOT_EQ(Int16(record.Removed.ToInt16())).
+				AND(EXISTS(
+					SELECT(Link.ID).
+						FROM(Link).
+						WHERE(
+							Link.RecordID.EQ(Record.ID).AND(Link.RecordId2.IN(frontier...)).
+								OR(Link.RecordId2.EQ(Record.ID).AND(Link.RecordID.IN(frontier...))),
+						),
+				)),
+		)
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return nil, fmt.Errorf("failed to get linked records: %w", err)
+	}
+	return lo.Map(records, func(record model.Record, index int) uuid.UUID {
+		return record.ID
+	}), nil
+}
+
+func uuids(ids []uuid.UUID) []Expression {
+	return lo.Map(ids, func(id uuid.UUID, index int) Expression {
+		return UUID(id)
+	})
+}
@@ -0,0 +1,98 @@
+package citation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+func recordURL(baseURL string, id uuid.UUID) string {
+	return fmt.Sprintf("%s/records/%s", strings.TrimSuffix(baseURL, "/"), id)
+}
+
+// permalink is the address a revision of a record keeps when the record
+// changes later on.
+func permalink(baseURL string, id uuid.UUID, revision model.RecordHistory) string {
+	return fmt.Sprintf("%s/revisions/%s", recordURL(baseURL, id), revision.ID)
+}
+
+// revisionSummaries lists the revisions of a record, which come oldest
+// first.
+func revisionSummaries(baseURL string, id uuid.UUID, revisions []model.RecordHistory) []revisionSummary {
+	return lo.Map(revisions, func(revision model.RecordHistory, index int) revisionSummary {
+		return revisionSummary{
+			ID:        revision.ID,
+			Number:    index + 1,
+			Permalink: permalink(baseURL, id, revision),
+			RevisedAt: common.ToDateTimeString(&revision.UpdatedAt),
+		}
+	})
+}
+
+// citeRevision describes the revision at index among the revisions of a
+// record, which come oldest first.
+func citeRevision(baseURL string, id uuid.UUID, revisions []model.RecordHistory, index int) revisionResponse {
+	revision := revisions[index]
+	number := index + 1
+	link := permalink(baseURL, id, revision)
+	recordType := record.TypeFromInt16(revision.Type)
+
+	related := []metadataRelatedIdentifier{
+		{RelatedIdentifier: recordURL(baseURL, id), RelatedIdentifierType: "URL", RelationType: "IsVersionOf"},
+	}
+	if index > 0 {
+		related = append(related, metadataRelatedIdentifier{
+			RelatedIdentifier:     permalink(baseURL, id, revisions[index-1]),
+			RelatedIdentifierType: "URL",
+			RelationType:          "IsNewVersionOf",
+		})
+	}
+	if index < len(revisions)-1 {
+		related = append(related, metadataRelatedIdentifier{
+			RelatedIdentifier:     permalink(baseURL, id, revisions[index+1]),
+			RelatedIdentifierType: "URL",
+			RelationType:          "IsPreviousVersionOf",
+		})
+	}
+
+	return revisionResponse{
+		ID:           revision.ID,
+		RecordID:     id,
+		Number:       number,
+		Current:      index == len(revisions)-1,
+		Permalink:    link,
+		RevisedAt:    common.ToDateTimeString(&revision.UpdatedAt),
+		Title:        revision.Title,
+		Description:  revision.Description,
+		Location:     revision.Location,
+		Significance: revision.Significance,
+		Url:          revision.URL,
+		StartDate:    common.ToDateString(revision.StartDate),
+		EndDate:      common.ToDateString(revision.EndDate),
+		RecordStatus: record.RecordStatusFromInt16(revision.Status),
+		Type:         recordType,
+		Citation: fmt.Sprintf("%s. %s, revision %d, %s. %s",
+			strings.TrimRight(revision.Title, "."), publisher, number, revision.UpdatedAt.Format("2 January 2006"), link),
+		Metadata: revisionMetadata{
+			Identifier:      metadataIdentifier{Identifier: link, IdentifierType: "URL"},
+			Titles:          []metadataTitle{{Title: revision.Title}},
+			Publisher:       publisher,
+			PublicationYear: revision.UpdatedAt.Year(),
+			ResourceType:    metadataResourceType{ResourceTypeGeneral: "Dataset", ResourceType: string(recordType)},
+			Version:         strconv.Itoa(number),
+			Dates: []metadataDate{
+				{Date: common.ToDateString(&revision.CreatedAt), DateType: "Created"},
+				{Date: common.ToDateString(&revision.UpdatedAt), DateType: "Updated"},
+			},
+			RelatedIdentifiers: related,
+			URL:                link,
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package citation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ICitationService interface {
+	GetRecordCitations(c context.Context, id uuid.UUID, format Format) ([]byte, error)
+	GetCitations(c context.Context, ids []uuid.UUID, around uuid.UUID, depth int, format Format) ([]byte, error)
+	GetRevisions(c context.Context, id uuid.UUID, baseURL string) ([]revisionSummary, error)
+	GetRevision(c context.Context, id uuid.UUID, revisionId uuid.UUID, baseURL string) (revisionResponse, error)
+	GetCurrentRevision(c context.Context, id uuid.UUID, baseURL string) (revisionResponse, error)
+}
+
+type CitationService struct {
+	citationRepository ICitationRepository
+	logger             *slog.Logger
+}
+
+func NewCitationService(citationRepository ICitationRepository, logger *slog.Logger) ICitationService {
+	return CitationService{
+		citationRepository: citationRepository,
+		logger:             logger,
+	}
+}
+
+// GetRecordCitations cites the sources of a record.
+func (s CitationService) GetRecordCitations(c context.Context, id uuid.UUID, format Format) ([]byte, error) {
+	return s.GetCitations(c, []uuid.UUID{id}, uuid.Nil, 0, format)
+}
+
+// GetCitations cites the sources of a set of records together with the
+// sources of the records within depth links of around, when given.
+func (s CitationService) GetCitations(c context.Context, ids []uuid.UUID, around uuid.UUID, depth int, format Format) ([]byte, error) {
+	ids = lo.Uniq(ids)
+	if around != uuid.Nil {
+		neighborhood, err := s.neighborhood(c, around, depth)
+		if err != nil {
+			return nil, err
+		}
+		ids = lo.Uniq(append(ids, neighborhood...))
+	}
+	if _, err := s.citationRepository.GetRecords(c, ids); err != nil {
+		return nil, err
+	}
+
+	sources, err := s.citationRepository.GetSources(c, ids)
+	if err != nil {
+		return nil, err
+	}
+	cited := lo.Map(sources, func(source model.Source, index int) citedSource {
+		return cite(source)
+	})
+
+	switch format {
+	case BibTeX:
+		return formatBibTeX(cited), nil
+	case RIS:
+		return formatRIS(cited), nil
+	case CSLJSON:
+		return formatCSLJSON(cited)
+	}
+	return nil, fmt.Errorf("unsupported citation format %q", format)
+}
+
+// neighborhood returns a record with the records reached from it by
+// following at most depth links.
+func (s CitationService) neighborhood(c context.Context, id uuid.UUID, depth int) ([]uuid.UUID, error) {
+	seen := map[uuid.UUID]bool{id: true}
+	reached := []uuid.UUID{id}
+	frontier := []uuid.UUID{id}
+	for range depth {
+		neighbors, err := s.citationRepository.GetNeighbors(c, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = nil
+		for _, neighbor := range neighbors {
+			if !seen[neighbor] {
+				seen[neighbor] = true
+				reached = append(reached, neighbor)
+				frontier = append(frontier, neighbor)
+			}
+		}
+		if len(frontier) == 0 {
+			break
+		}
+	}
+	return reached, nil
+}
+
+// GetRevisions lists the revisions of a record, oldest first, using baseURL
+// to build their permalinks.
+func (s CitationService) GetRevisions(c context.Context, id uuid.UUID, baseURL string) ([]revisionSummary, error) {
+	revisions, err := s.citationRepository.GetRevisions(c, id)
+	if err != nil {
+		return nil, err
+	}
+	return revisionSummaries(baseURL, id, revisions), nil
+}
+
+// GetRevision cites a revision of a record.
+func (s CitationService) GetRevision(c context.Context, id uuid.UUID, revisionId uuid.UUID, baseURL string) (revisionResponse, error) {
+	revisions, err := s.citationRepository.GetRevisions(c, id)
+	if err != nil {
+		return revisionResponse{}, err
+	}
+	_, index, found := lo.FindIndexOf(revisions, func(revision model.RecordHistory) bool {
+		return revision.ID == revisionId
+	})
+	if !found {
+		return revisionResponse{}, common.ErrRevisionNotFound
+	}
+	return citeRevision(baseURL, id, revisions, index), nil
+}
+
+// GetCurrentRevision cites a record as it is now.
+func (s CitationService) GetCurrentRevision(c context.Context, id uuid.UUID, baseURL string) (revisionResponse, error) {
+	revisions, err := s.citationRepository.GetRevisions(c, id)
+	if err != nil {
+		return revisionResponse{}, err
+	}
+	return citeRevision(baseURL, id, revisions, len(revisions)-1), nil
+}
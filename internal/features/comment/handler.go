@@ -0,0 +1,237 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+func NewCommentResources(conn *sql.DB, logger *slog.Logger) CommentResources {
+	return CommentResources{
+		logger:         logger,
+		CommentService: NewCommentService(NewRepository(conn, logger), logger),
+	}
+}
+
+type CommentResources struct {
+	CommentService ICommentService
+	logger         *slog.Logger
+}
+
+func (rs CommentResources) getRecordComments(c context.Context, input *struct {
+	ID     uuid.UUID    `path:"id"`
+	Status ThreadStatus `query:"status" enum:"open,resolved" doc:"Only list threads in this status"`
+}) (*struct {
+	Body []threadResponseBody
+}, error) {
+	threads, err := rs.CommentService.GetRecordComments(c, input.ID, input.Status)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return &struct {
+		Body []threadResponseBody
+	}{
+		Body: threads,
+	}, nil
+}
+
+func (rs CommentResources) getLinkComments(c context.Context, input *struct {
+	ID     uuid.UUID    `path:"id"`
+	Status ThreadStatus `query:"status" enum:"open,resolved" doc:"Only list threads in this status"`
+}) (*struct {
+	Body []threadResponseBody
+}, error) {
+	threads, err := rs.CommentService.GetLinkComments(c, input.ID, input.Status)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return &struct {
+		Body []threadResponseBody
+	}{
+		Body: threads,
+	}, nil
+}
+
+func (rs CommentResources) createRecordComment(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+	Body createCommentCommandBody
+}) (*struct {
+	Body commentResponseBody
+}, error) {
+	comment, err := rs.CommentService.CreateRecordComment(c, input.User, input.ID, input.Body)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return &struct {
+		Body commentResponseBody
+	}{
+		Body: comment,
+	}, nil
+}
+
+func (rs CommentResources) createLinkComment(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+	Body createCommentCommandBody
+}) (*struct {
+	Body commentResponseBody
+}, error) {
+	comment, err := rs.CommentService.CreateLinkComment(c, input.User, input.ID, input.Body)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return &struct {
+		Body commentResponseBody
+	}{
+		Body: comment,
+	}, nil
+}
+
+func (rs CommentResources) update(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+	Body updateCommentCommandBody
+}) (*struct {
+	Body commentResponseBody
+}, error) {
+	comment, err := rs.CommentService.UpdateComment(c, input.User, input.ID, input.Body)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return &struct {
+		Body commentResponseBody
+	}{
+		Body: comment,
+	}, nil
+}
+
+func (rs CommentResources) getHistory(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body []commentRevisionResponseBody
+}, error) {
+	revisions, err := rs.CommentService.GetCommentHistory(c, input.ID)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return &struct {
+		Body []commentRevisionResponseBody
+	}{
+		Body: revisions,
+	}, nil
+}
+
+func (rs CommentResources) resolve(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+}) (*struct {
+	Body threadResponseBody
+}, error) {
+	thread, err := rs.CommentService.SetResolved(c, input.User, input.ID, true)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return &struct {
+		Body threadResponseBody
+	}{
+		Body: thread,
+	}, nil
+}
+
+func (rs CommentResources) unresolve(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+}) (*struct {
+	Body threadResponseBody
+}, error) {
+	thread, err := rs.CommentService.SetResolved(c, input.User, input.ID, false)
+	if err != nil {
+		return nil, commentError(err)
+	}
+
+	return &struct {
+		Body threadResponseBody
+	}{
+		Body: thread,
+	}, nil
+}
+
+func commentError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound),
+		errors.Is(err, common.ErrLinkNotFound),
+		errors.Is(err, common.ErrCommentNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, common.ErrInvalidComment):
+		return huma.Error422UnprocessableEntity(err.Error())
+	case errors.Is(err, common.ErrCommentForbidden):
+		return huma.Error403Forbidden(err.Error())
+	default:
+		return err
+	}
+}
+
+func (rs CommentResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "get-record-comments",
+		Method:      http.MethodGet,
+		Path:        "/records/{id}/comments",
+		Description: "Lists the discussion threads on a record in the order they were started, each with its replies.",
+	}, rs.getRecordComments)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-record-comment",
+		Method:      http.MethodPost,
+		Path:        "/records/{id}/comments",
+		Description: "Starts a thread on a record or replies to one. Users mentioned as @name and those watching the record are notified.",
+	}, rs.createRecordComment)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-link-comments",
+		Method:      http.MethodGet,
+		Path:        "/links/{id}/comments",
+		Description: "Lists the discussion threads on a link in the order they were started, each with its replies.",
+	}, rs.getLinkComments)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-link-comment",
+		Method:      http.MethodPost,
+		Path:        "/links/{id}/comments",
+		Description: "Starts a thread on a link or replies to one. Users mentioned as @name and those watching either record of the link are notified.",
+	}, rs.createLinkComment)
+	huma.Register(s, huma.Operation{
+		OperationID: "update-comment",
+		Method:      http.MethodPut,
+		Path:        "/comments/{id}",
+		Description: "Changes the body of a comment, keeping the earlier body in its history. Only its author can.",
+	}, rs.update)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-comment-history",
+		Method:      http.MethodGet,
+		Path:        "/comments/{id}/history",
+		Description: "Lists every body a comment had, oldest first, ending with the body it has now.",
+	}, rs.getHistory)
+	huma.Register(s, huma.Operation{
+		OperationID: "resolve-comment",
+		Method:      http.MethodPost,
+		Path:        "/comments/{id}/resolve",
+		Description: "Resolves the thread of a comment.",
+	}, rs.resolve)
+	huma.Register(s, huma.Operation{
+		OperationID: "unresolve-comment",
+		Method:      http.MethodPost,
+		Path:        "/comments/{id}/unresolve",
+		Description: "Opens the thread of a comment again.",
+	}, rs.unresolve)
+}
@@ -0,0 +1,153 @@
+package comment
+
+import (
+	"regexp"
+	"strings"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// ThreadStatus tells whether the discussion in a thread is settled.
+type ThreadStatus string
+
+const (
+	Open     ThreadStatus = "open"
+	Resolved ThreadStatus = "resolved"
+)
+
+// Subject is what comments are written on, either a record or a link.
+type Subject struct {
+	RecordID *uuid.UUID
+	LinkID   *uuid.UUID
+}
+
+// CommentEntity is a comment together with the users it mentions.
+type CommentEntity struct {
+	model.Comment
+	Mentions []model.CommentMention
+}
+
+// threadID is the comment starting the thread the comment is part of.
+func (e CommentEntity) threadID() uuid.UUID {
+	if e.ParentID != nil {
+		return *e.ParentID
+	}
+	return e.ID
+}
+
+type commentResponseBody struct {
+	ID       uuid.UUID  `json:"id"`
+	RecordID *uuid.UUID `json:"recordId"`
+	LinkID   *uuid.UUID `json:"linkId"`
+	// ParentID is the comment starting the thread replied to, nil for
+	// comments starting a thread
+	ParentID *uuid.UUID `json:"parentId"`
+	Author   string     `json:"author"`
+	Body     string     `json:"body"`
+	Mentions []string   `json:"mentions"`
+	// Edited comments have their earlier bodies in their history
+	Edited    bool   `json:"edited"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type threadResponseBody struct {
+	Comment    commentResponseBody   `json:"comment"`
+	Status     ThreadStatus          `json:"status"`
+	ResolvedBy *string               `json:"resolvedBy"`
+	ResolvedAt *string               `json:"resolvedAt"`
+	Replies    []commentResponseBody `json:"replies"`
+}
+
+type commentRevisionResponseBody struct {
+	Body      string `json:"body"`
+	WrittenAt string `json:"writtenAt"`
+}
+
+type createCommentCommandBody struct {
+	Body     string     `json:"body" minLength:"1" maxLength:"4000" doc:"Text of the comment, mentioning users as @name"`
+	ParentID *uuid.UUID `json:"parentId,omitempty" doc:"Comment replied to, starting a new thread when left out"`
+}
+
+type updateCommentCommandBody struct {
+	Body string `json:"body" minLength:"1" maxLength:"4000" doc:"Text of the comment, mentioning users as @name"`
+}
+
+// mentionPattern finds users mentioned as @name. Names may hold an @ of their
+// own so users known by their email address can be mentioned too.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@(\w[\w.@-]*)`)
+
+// parseMentions lists the users a comment body mentions, once each, in the
+// order they are first mentioned.
+func parseMentions(body string) []string {
+	var mentions []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.TrimRight(match[1], ".@-")
+		if name != "" && len(name) <= 255 {
+			mentions = append(mentions, name)
+		}
+	}
+	return lo.Uniq(mentions)
+}
+
+func mapCommentResponseBody(comment CommentEntity, index int) commentResponseBody {
+	return commentResponseBody{
+		ID:       comment.ID,
+		RecordID: comment.RecordID,
+		LinkID:   comment.LinkID,
+		ParentID: comment.ParentID,
+		Author:   comment.Author,
+		Body:     comment.Body,
+		Mentions: lo.Map(comment.Mentions, func(mention model.CommentMention, index int) string {
+			return mention.UserName
+		}),
+		Edited:    !comment.UpdatedAt.Equal(comment.CreatedAt),
+		CreatedAt: common.ToDateTimeString(&comment.CreatedAt),
+		UpdatedAt: common.ToDateTimeString(&comment.UpdatedAt),
+	}
+}
+
+// mapThreads groups comments into the threads they are part of, in the order
+// the comments starting them were written. The comments are expected in the
+// order they were written.
+func mapThreads(comments []CommentEntity) []threadResponseBody {
+	threads := []threadResponseBody{}
+	indexes := map[uuid.UUID]int{}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			indexes[comment.ID] = len(threads)
+			threads = append(threads, mapThreadResponseBody(comment))
+		}
+	}
+	for _, comment := range comments {
+		if index, ok := indexes[comment.threadID()]; ok && comment.ParentID != nil {
+			threads[index].Replies = append(threads[index].Replies, mapCommentResponseBody(comment, 0))
+		}
+	}
+	return threads
+}
+
+func mapThreadResponseBody(comment CommentEntity) threadResponseBody {
+	thread := threadResponseBody{
+		Comment:    mapCommentResponseBody(comment, 0),
+		Status:     Open,
+		ResolvedBy: comment.ResolvedBy,
+		Replies:    []commentResponseBody{},
+	}
+	if comment.ResolvedAt != nil {
+		thread.Status = Resolved
+		thread.ResolvedAt = lo.ToPtr(common.ToDateTimeString(comment.ResolvedAt))
+	}
+	return thread
+}
+
+func mapCommentRevisionResponseBody(revision model.CommentRevision, index int) commentRevisionResponseBody {
+	return commentRevisionResponseBody{
+		Body:      revision.Body,
+		WrittenAt: common.ToDateTimeString(&revision.CreatedAt),
+	}
+}
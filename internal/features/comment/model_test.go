@@ -0,0 +1,35 @@
+package comment
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{name: "none", body: "The date looks off by a year."},
+		{name: "start of body", body: "@ada can you check the source?", expected: []string{"ada"}},
+		{name: "several", body: "Agreed with @ada and @grace_hopper.", expected: []string{"ada", "grace_hopper"}},
+		{name: "once each in order", body: "@grace, @ada, then @grace again", expected: []string{"grace", "ada"}},
+		{name: "email address", body: "Ask @ada.lovelace@example.org about it", expected: []string{"ada.lovelace@example.org"}},
+		{name: "trailing punctuation", body: "Thanks @ada. And @grace-", expected: []string{"ada", "grace"}},
+		{name: "inside an email address", body: "Mail ada@example.org instead"},
+		{name: "inside a word", body: "see x@ada or .@ada"},
+		{name: "in brackets", body: "(cc @ada)", expected: []string{"ada"}},
+		{name: "alone", body: "@ and @-"},
+		{name: "too long", body: "@" + strings.Repeat("a", 256)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMentions(tt.body); !reflect.DeepEqual(got, tt.expected) && (len(got) > 0 || len(tt.expected) > 0) {
+				t.Errorf("parseMentions(%q) = %q, expected %q", tt.body, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ICommentRepository interface {
+	GetRecord(c context.Context, id uuid.UUID) (model.Record, error)
+	GetLink(c context.Context, id uuid.UUID) (model.Link, error)
+	GetWatches(c context.Context, recordIds []uuid.UUID) ([]model.Watch, error)
+	GetComments(c context.Context, subject Subject) ([]CommentEntity, error)
+	GetComment(c context.Context, id uuid.UUID) (CommentEntity, error)
+	GetThread(c context.Context, id uuid.UUID) ([]CommentEntity, error)
+	GetRevisions(c context.Context, id uuid.UUID) ([]model.CommentRevision, error)
+	Create(c context.Context, comment model.Comment, mentions []string, notifications []model.Notification) (uuid.UUID, error)
+	Update(c context.Context, id uuid.UUID, body string, mentions []string, notifications []model.Notification) error
+	SetResolved(c context.Context, id uuid.UUID, resolvedBy *string) error
+}
+
+type CommentRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) ICommentRepository {
+	return CommentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r CommentRepository) GetRecord(c context.Context, id uuid.UUID) (model.Record, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.EQ(UUID(id)))
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return model.Record{}, fmt.Errorf("failed to get record: %w", err)
+	}
+	if len(records) == 0 {
+		return model.Record{}, common.ErrRecordNotFound
+	}
+	return records[0], nil
+}
+
+func (r CommentRepository) GetLink(c context.Context, id uuid.UUID) (model.Link, error) {
+	stmt := SELECT(Link.AllColumns).
+		FROM(Link).
+		WHERE(Link.ID.EQ(UUID(id)))
+
+	var links []model.Link
+	if err := stmt.QueryContext(c, r.db, &links); err != nil {
+		return model.Link{}, fmt.Errorf("failed to get link: %w", err)
+	}
+	if len(links) == 0 {
+		return model.Link{}, common.ErrLinkNotFound
+	}
+	return links[0], nil
+}
+
+// GetWatches lists who watches any of the records.
+func (r CommentRepository) GetWatches(c context.Context, recordIds []uuid.UUID) ([]model.Watch, error) {
+	ids := lo.Map(recordIds, func(id uuid.UUID, index int) Expression {
+		return UUID(id)
+	})
+	stmt := SELECT(Watch.AllColumns).
+		FROM(Watch).
+		WHERE(Watch.RecordID.IN(ids...)).
+		ORDER_BY(Watch.UserName, Watch.RecordID)
+
+	var watches []model.Watch
+	if err := stmt.QueryContext(c, r.db, &watches); err != nil {
+		return nil, fmt.Errorf("failed to get watches: %w", err)
+	}
+	return watches, nil
+}
+
+func (r CommentRepository) getComments(c context.Context, condition BoolExpression) ([]CommentEntity, error) {
+	stmt := SELECT(Comment.AllColumns, CommentMention.AllColumns).
+		FROM(Comment.LEFT_JOIN(CommentMention, CommentMention.CommentID.EQ(Comment.ID))).
+		WHERE(condition).
+		ORDER_BY(Comment.CreatedAt, Comment.ID, CommentMention.UserName)
+
+	var comments []CommentEntity
+	if err := stmt.QueryContext(c, r.db, &comments); err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	return comments, nil
+}
+
+// GetComments lists the comments on a record or link in the order they were
+// written.
+func (r CommentRepository) GetComments(c context.Context, subject Subject) ([]CommentEntity, error) {
+	if subject.LinkID != nil {
+		return r.getComments(c, Comment.LinkID.EQ(UUID(*subject.LinkID)))
+	}
+	return r.getComments(c, Comment.RecordID.EQ(UUID(*subject.RecordID)))
+}
+
+func (r CommentRepository) GetComment(c context.Context, id uuid.UUID) (CommentEntity, error) {
+	comments, err := r.getComments(c, Comment.ID.EQ(UUID(id)))
+	if err != nil {
+		return CommentEntity{}, err
+	}
+	if len(comments) == 0 {
+		return CommentEntity{}, common.ErrCommentNotFound
+	}
+	return comments[0], nil
+}
+
+// GetThread lists the comment starting a thread and its replies in the order
+// they were written.
+func (r CommentRepository) GetThread(c context.Context, id uuid.UUID) ([]CommentEntity, error) {
+	comments, err := r.getComments(c, Comment.ID.EQ(UUID(id)).OR(Comment.ParentID.EQ(UUID(id))))
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, common.ErrCommentNotFound
+	}
+	return comments, nil
+}
+
+// GetRevisions lists the earlier bodies of a comment, oldest first.
+func (r CommentRepository) GetRevisions(c context.Context, id uuid.UUID) ([]model.CommentRevision, error) {
+	stmt := SELECT(CommentRevision.AllColumns).
+		FROM(CommentRevision).
+		WHERE(CommentRevision.CommentID.EQ(UUID(id))).
+		ORDER_BY(CommentRevision.CreatedAt, CommentRevision.ID)
+
+	var revisions []model.CommentRevision
+	if err := stmt.QueryContext(c, r.db, &revisions); err != nil {
+		return nil, fmt.Errorf("failed to get comment history: %w", err)
+	}
+	return revisions, nil
+}
+
+// Create writes a comment with the users it mentions, and the notifications
+// it sends, which are tied to the comment written.
+func (r CommentRepository) Create(c context.Context, comment model.Comment, mentions []string, notifications []model.Notification) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt := Comment.INSERT(Comment.RecordID, Comment.LinkID, Comment.ParentID, Comment.Author, Comment.Body).
+		MODEL(comment).
+		RETURNING(Comment.ID)
+
+	var created model.Comment
+	if err = stmt.QueryContext(c, tx, &created); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	if err = r.insertMentions(c, tx, created.ID, mentions); err != nil {
+		return uuid.Nil, err
+	}
+	if err = r.insertNotifications(c, tx, created.ID, notifications); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created.ID, nil
+}
+
+// Update changes the body of a comment, keeping the body it replaces in its
+// history, and replaces the users it mentions.
+func (r CommentRepository) Update(c context.Context, id uuid.UUID, body string, mentions []string, notifications []model.Notification) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	lockStmt := SELECT(Comment.AllColumns).
+		FROM(Comment).
+		WHERE(Comment.ID.EQ(UUID(id))).
+		FOR(UPDATE())
+
+	var comments []model.Comment
+	if err = lockStmt.QueryContext(c, tx, &comments); err != nil {
+		return fmt.Errorf("failed to lock comment: %w", err)
+	}
+	if len(comments) == 0 {
+		return common.ErrCommentNotFound
+	}
+	if comments[0].Body == body {
+		return nil
+	}
+
+	revisionStmt := CommentRevision.INSERT(CommentRevision.CommentID, CommentRevision.Body, CommentRevision.CreatedAt).
+		VALUES(id, comments[0].Body, comments[0].UpdatedAt)
+	if _, err = revisionStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to keep comment history: %w", err)
+	}
+
+	updateStmt := Comment.UPDATE().
+		SET(
+			Comment.Body.SET(String(body)),
+			Comment.UpdatedAt.SET(LOCALTIMESTAMP()),
+		).
+		WHERE(Comment.ID.EQ(UUID(id)))
+	if _, err = updateStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	deleteStmt := CommentMention.DELETE().
+		WHERE(CommentMention.CommentID.EQ(UUID(id)))
+	if _, err = deleteStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to delete mentions: %w", err)
+	}
+	if err = r.insertMentions(c, tx, id, mentions); err != nil {
+		return err
+	}
+	if err = r.insertNotifications(c, tx, id, notifications); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r CommentRepository) insertMentions(c context.Context, tx *sql.Tx, id uuid.UUID, mentions []string) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	rows := lo.Map(mentions, func(user string, index int) model.CommentMention {
+		return model.CommentMention{CommentID: id, UserName: user}
+	})
+	stmt := CommentMention.INSERT(CommentMention.AllColumns).
+		MODELS(rows)
+	if _, err := stmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to create mentions: %w", err)
+	}
+	return nil
+}
+
+func (r CommentRepository) insertNotifications(c context.Context, tx *sql.Tx, id uuid.UUID, notifications []model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	for i := range notifications {
+		notifications[i].CommentID = &id
+	}
+	stmt := Notification.INSERT(Notification.UserName, Notification.RecordID, Notification.Kind, Notification.RelatedRecordID, Notification.CommentID).
+		MODELS(notifications)
+	if _, err := stmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to create notifications: %w", err)
+	}
+	return nil
+}
+
+// SetResolved resolves the thread a comment starts when given who resolves
+// it, and opens it again when not. Threads already resolved keep who resolved
+// them first.
+func (r CommentRepository) SetResolved(c context.Context, id uuid.UUID, resolvedBy *string) error {
+	stmt := Comment.UPDATE().
+		SET(
+			Comment.ResolvedAt.SET(TimestampExp(NULL)),
+			Comment.ResolvedBy.SET(StringExp(NULL)),
+		).
+		WHERE(Comment.ID.EQ(UUID(id)))
+	if resolvedBy != nil {
+		stmt = Comment.UPDATE().
+			SET(
+				Comment.ResolvedAt.SET(TimestampExp(COALESCE(Comment.ResolvedAt, LOCALTIMESTAMP()))),
+				Comment.ResolvedBy.SET(StringExp(COALESCE(Comment.ResolvedBy, String(*resolvedBy)))),
+			).
+			WHERE(Comment.ID.EQ(UUID(id)))
+	}
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to resolve comment: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to resolve comment: %w", err)
+	} else if rows == 0 {
+		return common.ErrCommentNotFound
+	}
+	return nil
+}
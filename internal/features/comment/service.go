@@ -0,0 +1,263 @@
+package comment
+
+import (
+	"context"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/notification"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ICommentService interface {
+	GetRecordComments(c context.Context, recordId uuid.UUID, status ThreadStatus) ([]threadResponseBody, error)
+	GetLinkComments(c context.Context, linkId uuid.UUID, status ThreadStatus) ([]threadResponseBody, error)
+	CreateRecordComment(c context.Context, user string, recordId uuid.UUID, body createCommentCommandBody) (commentResponseBody, error)
+	CreateLinkComment(c context.Context, user string, linkId uuid.UUID, body createCommentCommandBody) (commentResponseBody, error)
+	UpdateComment(c context.Context, user string, id uuid.UUID, body updateCommentCommandBody) (commentResponseBody, error)
+	GetCommentHistory(c context.Context, id uuid.UUID) ([]commentRevisionResponseBody, error)
+	SetResolved(c context.Context, user string, id uuid.UUID, resolved bool) (threadResponseBody, error)
+}
+
+type CommentService struct {
+	commentRepository ICommentRepository
+	logger            *slog.Logger
+}
+
+func NewCommentService(commentRepository ICommentRepository, logger *slog.Logger) ICommentService {
+	return CommentService{
+		commentRepository: commentRepository,
+		logger:            logger,
+	}
+}
+
+// subjectRecord is a record a comment is about, with the record at the
+// other end for comments on links.
+type subjectRecord struct {
+	recordID        uuid.UUID
+	relatedRecordID *uuid.UUID
+}
+
+// subjectRecords lists the records a comment on the subject is about, failing
+// when the record or link does not exist.
+func (s CommentService) subjectRecords(c context.Context, subject Subject) ([]subjectRecord, error) {
+	if subject.LinkID != nil {
+		link, err := s.commentRepository.GetLink(c, *subject.LinkID)
+		if err != nil {
+			return nil, err
+		}
+		return []subjectRecord{
+			{recordID: link.RecordID, relatedRecordID: &link.RecordId2},
+			{recordID: link.RecordId2, relatedRecordID: &link.RecordID},
+		}, nil
+	}
+
+	if _, err := s.commentRepository.GetRecord(c, *subject.RecordID); err != nil {
+		return nil, err
+	}
+	return []subjectRecord{{recordID: *subject.RecordID}}, nil
+}
+
+func (s CommentService) getComments(c context.Context, subject Subject, status ThreadStatus) ([]threadResponseBody, error) {
+	if _, err := s.subjectRecords(c, subject); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepository.GetComments(c, subject)
+	if err != nil {
+		return nil, err
+	}
+	threads := mapThreads(comments)
+	if status != "" {
+		threads = lo.Filter(threads, func(thread threadResponseBody, index int) bool {
+			return thread.Status == status
+		})
+	}
+	return threads, nil
+}
+
+func (s CommentService) GetRecordComments(c context.Context, recordId uuid.UUID, status ThreadStatus) ([]threadResponseBody, error) {
+	return s.getComments(c, Subject{RecordID: &recordId}, status)
+}
+
+func (s CommentService) GetLinkComments(c context.Context, linkId uuid.UUID, status ThreadStatus) ([]threadResponseBody, error) {
+	return s.getComments(c, Subject{LinkID: &linkId}, status)
+}
+
+func (s CommentService) createComment(c context.Context, user string, subject Subject, body createCommentCommandBody) (commentResponseBody, error) {
+	records, err := s.subjectRecords(c, subject)
+	if err != nil {
+		return commentResponseBody{}, err
+	}
+
+	comment := model.Comment{
+		RecordID: subject.RecordID,
+		LinkID:   subject.LinkID,
+		Author:   user,
+		Body:     body.Body,
+	}
+	if body.ParentID != nil {
+		parent, err := s.commentRepository.GetComment(c, *body.ParentID)
+		if err != nil {
+			return commentResponseBody{}, err
+		}
+		if lo.FromPtr(parent.RecordID) != lo.FromPtr(subject.RecordID) ||
+			lo.FromPtr(parent.LinkID) != lo.FromPtr(subject.LinkID) {
+			return commentResponseBody{}, common.ErrInvalidComment
+		}
+		// Replies to replies join the thread of the comment replied to
+		comment.ParentID = lo.ToPtr(parent.threadID())
+	}
+
+	mentions := parseMentions(body.Body)
+	watches, err := s.commentRepository.GetWatches(c, lo.Map(records, func(record subjectRecord, index int) uuid.UUID {
+		return record.recordID
+	}))
+	if err != nil {
+		return commentResponseBody{}, err
+	}
+	notifications := append(
+		mentionNotifications(user, records, mentions, nil),
+		watcherNotifications(user, records, mentions, watches)...,
+	)
+
+	id, err := s.commentRepository.Create(c, comment, mentions, notifications)
+	if err != nil {
+		return commentResponseBody{}, err
+	}
+	created, err := s.commentRepository.GetComment(c, id)
+	if err != nil {
+		return commentResponseBody{}, err
+	}
+	return mapCommentResponseBody(created, 0), nil
+}
+
+func (s CommentService) CreateRecordComment(c context.Context, user string, recordId uuid.UUID, body createCommentCommandBody) (commentResponseBody, error) {
+	return s.createComment(c, user, Subject{RecordID: &recordId}, body)
+}
+
+func (s CommentService) CreateLinkComment(c context.Context, user string, linkId uuid.UUID, body createCommentCommandBody) (commentResponseBody, error) {
+	return s.createComment(c, user, Subject{LinkID: &linkId}, body)
+}
+
+// UpdateComment changes the body of a comment written by the user. Only users
+// the comment did not mention before are told about being mentioned.
+func (s CommentService) UpdateComment(c context.Context, user string, id uuid.UUID, body updateCommentCommandBody) (commentResponseBody, error) {
+	comment, err := s.commentRepository.GetComment(c, id)
+	if err != nil {
+		return commentResponseBody{}, err
+	}
+	if comment.Author != user {
+		return commentResponseBody{}, common.ErrCommentForbidden
+	}
+
+	records, err := s.subjectRecords(c, Subject{RecordID: comment.RecordID, LinkID: comment.LinkID})
+	if err != nil {
+		return commentResponseBody{}, err
+	}
+	mentioned := lo.Map(comment.Mentions, func(mention model.CommentMention, index int) string {
+		return mention.UserName
+	})
+	mentions := parseMentions(body.Body)
+	notifications := mentionNotifications(user, records, mentions, mentioned)
+
+	if err = s.commentRepository.Update(c, id, body.Body, mentions, notifications); err != nil {
+		return commentResponseBody{}, err
+	}
+	updated, err := s.commentRepository.GetComment(c, id)
+	if err != nil {
+		return commentResponseBody{}, err
+	}
+	return mapCommentResponseBody(updated, 0), nil
+}
+
+// GetCommentHistory lists every body a comment had, oldest first, ending
+// with the body it has now.
+func (s CommentService) GetCommentHistory(c context.Context, id uuid.UUID) ([]commentRevisionResponseBody, error) {
+	comment, err := s.commentRepository.GetComment(c, id)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := s.commentRepository.GetRevisions(c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions = append(revisions, model.CommentRevision{
+		CommentID: comment.ID,
+		Body:      comment.Body,
+		CreatedAt: comment.UpdatedAt,
+	})
+	return lo.Map(revisions, mapCommentRevisionResponseBody), nil
+}
+
+// SetResolved resolves or opens again the thread of a comment, which may be
+// any comment of the thread.
+func (s CommentService) SetResolved(c context.Context, user string, id uuid.UUID, resolved bool) (threadResponseBody, error) {
+	comment, err := s.commentRepository.GetComment(c, id)
+	if err != nil {
+		return threadResponseBody{}, err
+	}
+
+	var resolvedBy *string
+	if resolved {
+		resolvedBy = &user
+	}
+	if err = s.commentRepository.SetResolved(c, comment.threadID(), resolvedBy); err != nil {
+		return threadResponseBody{}, err
+	}
+
+	comments, err := s.commentRepository.GetThread(c, comment.threadID())
+	if err != nil {
+		return threadResponseBody{}, err
+	}
+	threads := mapThreads(comments)
+	if len(threads) == 0 {
+		return threadResponseBody{}, common.ErrCommentNotFound
+	}
+	return threads[0], nil
+}
+
+// mentionNotifications tells the users a comment mentions, other than its
+// author and those it mentioned already, that they were mentioned.
+func mentionNotifications(author string, records []subjectRecord, mentions []string, mentioned []string) []model.Notification {
+	users := lo.Without(lo.Without(mentions, mentioned...), author)
+	return lo.Map(users, func(user string, index int) model.Notification {
+		return model.Notification{
+			UserName:        user,
+			RecordID:        records[0].recordID,
+			Kind:            notification.Mentioned.ToInt16(),
+			RelatedRecordID: records[0].relatedRecordID,
+		}
+	})
+}
+
+// watcherNotifications tells those watching the records a comment is about,
+// other than its author and the users it mentions, that it was written.
+// Users watching both records of a link are told once.
+func watcherNotifications(author string, records []subjectRecord, mentions []string, watches []model.Watch) []model.Notification {
+	related := lo.SliceToMap(records, func(record subjectRecord) (uuid.UUID, *uuid.UUID) {
+		return record.recordID, record.relatedRecordID
+	})
+	told := lo.SliceToMap(append(mentions, author), func(user string) (string, bool) {
+		return user, true
+	})
+
+	var notifications []model.Notification
+	for _, watch := range watches {
+		if told[watch.UserName] {
+			continue
+		}
+		told[watch.UserName] = true
+		notifications = append(notifications, model.Notification{
+			UserName:        watch.UserName,
+			RecordID:        watch.RecordID,
+			Kind:            notification.Commented.ToInt16(),
+			RelatedRecordID: related[watch.RecordID],
+		})
+	}
+	return notifications
+}
@@ -0,0 +1,115 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+// heartbeatInterval is how often an idle stream gets a comment, so proxies
+// do not close it.
+const heartbeatInterval = 30 * time.Second
+
+func NewEventResources(connStr string, logger *slog.Logger) EventResources {
+	return EventResources{
+		logger:       logger,
+		EventService: NewEventService(NewRepository(connStr, logger), logger),
+	}
+}
+
+type EventResources struct {
+	EventService IEventService
+	logger       *slog.Logger
+}
+
+func (rs EventResources) getEvents(c context.Context, input *struct {
+	Entities []Entity  `query:"entity" enum:"record,impact,link" doc:"Only stream changes to these kinds of things"`
+	Record   uuid.UUID `query:"record" doc:"Only stream changes to this record, its impacts and the links from or to it"`
+}) (*huma.StreamResponse, error) {
+	filter := EventFilter{
+		Entities: input.Entities,
+		Record:   input.Record,
+	}
+
+	return &huma.StreamResponse{
+		Body: func(ctx huma.Context) {
+			changes, unsubscribe := rs.EventService.Subscribe(filter)
+			defer unsubscribe()
+
+			ctx.SetHeader("Content-Type", "text/event-stream")
+			ctx.SetHeader("Cache-Control", "no-cache")
+			w := ctx.BodyWriter()
+			flusher, ok := w.(http.Flusher)
+			if !ok {
+				rs.logger.Error("event stream cannot be flushed")
+				return
+			}
+			flusher.Flush()
+
+			heartbeat := time.NewTicker(heartbeatInterval)
+			defer heartbeat.Stop()
+			for {
+				var err error
+				select {
+				case <-ctx.Context().Done():
+					return
+				case change, ok := <-changes:
+					if !ok {
+						return
+					}
+					err = writeEvent(w, change)
+				case <-heartbeat.C:
+					_, err = io.WriteString(w, ": heartbeat\n\n")
+				}
+				if err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		},
+	}, nil
+}
+
+// writeEvent writes a change as an event named after the entity changed, or
+// a resync event when changes may have been missed.
+func writeEvent(w io.Writer, change *changeEvent) error {
+	if change == nil {
+		_, err := io.WriteString(w, "event: resync\ndata: {}\n\n")
+		return err
+	}
+
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", change.Entity, data)
+	return err
+}
+
+func (rs EventResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID:   "get-events",
+		Method:        http.MethodGet,
+		Path:          "/events",
+		DefaultStatus: http.StatusOK,
+		Description: "Streams changes to records, impacts and links as Server-Sent Events while they happen. " +
+			"Each event is named after the kind of thing changed and carries its ids; a resync event tells that changes may have been missed, " +
+			"after which clients should fetch what they show again. Streams of clients that fall behind are closed.",
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Stream of change events",
+				Content: map[string]*huma.MediaType{
+					"text/event-stream": {Schema: s.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(changeEvent{}), true, "ChangeEvent")},
+				},
+			},
+		},
+	}, rs.getEvents)
+}
@@ -0,0 +1,58 @@
+package event
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Entity is the kind of thing a change happened to.
+type Entity string
+
+const (
+	RecordEntity Entity = "record"
+	ImpactEntity Entity = "impact"
+	LinkEntity   Entity = "link"
+)
+
+type Action string
+
+const (
+	Created Action = "created"
+	Updated Action = "updated"
+	Deleted Action = "deleted"
+)
+
+// changeEvent tells that a record, impact or link changed. It only carries
+// ids, clients fetch whatever they show of it again.
+type changeEvent struct {
+	Entity Entity    `json:"entity" enum:"record,impact,link"`
+	Action Action    `json:"action" enum:"created,updated,deleted"`
+	ID     uuid.UUID `json:"id"`
+	// RecordID is the record an impact belongs to or a link starts from
+	RecordID *uuid.UUID `json:"recordId,omitempty"`
+	// RecordID2 is the record a link points at
+	RecordID2 *uuid.UUID `json:"recordId2,omitempty"`
+}
+
+// EventFilter narrows down the changes a client is sent.
+type EventFilter struct {
+	// Entities only passes changes to these kinds of things, all of them
+	// when empty
+	Entities []Entity
+	// Record only passes changes to this record, its impacts and the links
+	// from or to it
+	Record uuid.UUID
+}
+
+func (f EventFilter) matches(event changeEvent) bool {
+	if len(f.Entities) > 0 && !slices.Contains(f.Entities, event.Entity) {
+		return false
+	}
+	if f.Record != uuid.Nil {
+		return event.ID == f.Record ||
+			(event.RecordID != nil && *event.RecordID == f.Record) ||
+			(event.RecordID2 != nil && *event.RecordID2 == f.Record)
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// channel is the channel the notify_change trigger notifies changes on.
+const channel = "change"
+
+const (
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+	// pingInterval is how long to go without notifications before checking
+	// the connection is still alive
+	pingInterval = 90 * time.Second
+)
+
+type IEventRepository interface {
+	// Listen sends the changes made to the database until the context ends.
+	// A nil change tells that changes may have been missed while the
+	// connection was lost.
+	Listen(c context.Context, changes chan<- *changeEvent) error
+}
+
+type EventRepository struct {
+	connStr string
+	logger  *slog.Logger
+}
+
+// NewRepository listens on its own connection rather than a pooled one, as
+// notifications are delivered to the connection that asked for them.
+func NewRepository(connStr string, logger *slog.Logger) IEventRepository {
+	return EventRepository{
+		connStr: connStr,
+		logger:  logger,
+	}
+}
+
+func (r EventRepository) Listen(c context.Context, changes chan<- *changeEvent) error {
+	listener := pq.NewListener(r.connStr, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("change listener: %v", err))
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(channel); err != nil {
+		return fmt.Errorf("failed to listen for changes: %w", err)
+	}
+
+	for {
+		var change *changeEvent
+		select {
+		case <-c.Done():
+			return nil
+		case <-time.After(pingInterval):
+			go listener.Ping()
+			continue
+		case notification := <-listener.Notify:
+			// The listener sends nil once it has reconnected
+			if notification != nil {
+				change = &changeEvent{}
+				if err := json.Unmarshal([]byte(notification.Extra), change); err != nil {
+					r.logger.Error(fmt.Sprintf("failed to decode change %q: %v", notification.Extra, err))
+					continue
+				}
+			}
+		}
+
+		select {
+		case <-c.Done():
+			return nil
+		case changes <- change:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// subscriberBuffer is how many changes a client may fall behind by before it
+// is dropped.
+const subscriberBuffer = 64
+
+type IEventService interface {
+	// Run passes the changes made to the database on to the subscribers
+	// until the context ends.
+	Run(c context.Context) error
+	// Subscribe returns the changes passing the filter, with nil telling
+	// that changes may have been missed. The channel is closed when the
+	// subscriber falls too far behind or unsubscribes.
+	Subscribe(filter EventFilter) (<-chan *changeEvent, func())
+}
+
+type EventService struct {
+	eventRepository IEventRepository
+	logger          *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[chan *changeEvent]EventFilter
+}
+
+func NewEventService(eventRepository IEventRepository, logger *slog.Logger) IEventService {
+	return &EventService{
+		eventRepository: eventRepository,
+		logger:          logger,
+		subscribers:     map[chan *changeEvent]EventFilter{},
+	}
+}
+
+func (s *EventService) Run(c context.Context) error {
+	changes := make(chan *changeEvent)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.eventRepository.Listen(c, changes)
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			return err
+		case change := <-changes:
+			s.publish(change)
+		}
+	}
+}
+
+func (s *EventService) Subscribe(filter EventFilter) (<-chan *changeEvent, func()) {
+	subscriber := make(chan *changeEvent, subscriberBuffer)
+
+	s.mu.Lock()
+	s.subscribers[subscriber] = filter
+	s.mu.Unlock()
+
+	return subscriber, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.drop(subscriber)
+	}
+}
+
+func (s *EventService) publish(change *changeEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for subscriber, filter := range s.subscribers {
+		if change != nil && !filter.matches(*change) {
+			continue
+		}
+		select {
+		case subscriber <- change:
+		default:
+			// Closing the stream of a client that cannot keep up makes it
+			// reconnect and fetch what it missed
+			s.drop(subscriber)
+		}
+	}
+}
+
+// drop must be called holding the lock.
+func (s *EventService) drop(subscriber chan *changeEvent) {
+	if _, ok := s.subscribers[subscriber]; ok {
+		delete(s.subscribers, subscriber)
+		close(subscriber)
+	}
+}
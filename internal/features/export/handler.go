@@ -0,0 +1,64 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+func NewExportResources(conn *sql.DB, logger *slog.Logger) ExportResources {
+	return ExportResources{
+		logger:        logger,
+		ExportService: NewExportService(NewRepository(conn, logger), logger),
+	}
+}
+
+type ExportResources struct {
+	ExportService IExportService
+	logger        *slog.Logger
+}
+
+var contentTypes = map[Format]string{
+	NDJSON: "application/x-ndjson",
+	CSV:    "text/csv",
+}
+
+func (rs ExportResources) export(c context.Context, input *struct {
+	Format Format `query:"format" enum:"ndjson,csv" default:"ndjson"`
+}) (*huma.StreamResponse, error) {
+	return &huma.StreamResponse{
+		Body: func(ctx huma.Context) {
+			filename := fmt.Sprintf("historylink-%s.%s", time.Now().Format("20060102"), input.Format)
+			ctx.SetHeader("Content-Type", contentTypes[input.Format])
+			ctx.SetHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+			// The status is sent with the first write, so failures halfway
+			// through can only be logged and show up as a truncated file.
+			if err := rs.ExportService.Export(ctx.Context(), ctx.BodyWriter(), input.Format); err != nil {
+				rs.logger.Error(err.Error())
+			}
+		},
+	}, nil
+}
+
+func (rs ExportResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "export",
+		Method:      http.MethodGet,
+		Path:        "/export",
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "All records with everything attached to them. CSV leaves out what does not fit a flat table, such as claims, labels and terms",
+				Content: map[string]*huma.MediaType{
+					"application/x-ndjson": {},
+					"text/csv":             {},
+				},
+			},
+		},
+	}, rs.export)
+}
@@ -0,0 +1,227 @@
+package export
+
+import (
+	"encoding/json"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+	"historylink/internal/features/term"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+// exportAggregate is a record with everything attached to it. Links contain
+// every link the record takes part in, so a link appears under both records.
+type exportAggregate struct {
+	model.Record
+
+	Impacts      []model.Impact
+	Sources      []model.Source
+	Claims       []model.Claim
+	Alternatives []model.AlternativeValue
+	Links        []model.Link
+	ExternalIds  []model.ExternalIdentifier
+	Labels       []model.RecordLabel
+	Aliases      []model.RecordAlias
+	Terms        []model.Term
+}
+
+type exportImpact struct {
+	ID          uuid.UUID          `json:"id"`
+	Description string             `json:"description"`
+	Value       int16              `json:"value"`
+	Category    record.Category    `json:"category"`
+	Confidence  *record.Confidence `json:"confidence"`
+}
+
+type exportSource struct {
+	ID             uuid.UUID     `json:"id"`
+	Title          string        `json:"title"`
+	Type           int16         `json:"type"`
+	Url            string        `json:"url"`
+	Description    *string       `json:"description"`
+	Authors        []string      `json:"authors"`
+	Year           *int16        `json:"year"`
+	Publisher      *string       `json:"publisher"`
+	ContainerTitle *string       `json:"containerTitle"`
+	Claims         []exportClaim `json:"claims"`
+}
+
+// exportClaim is a claim of the source it is nested in. Field or ImpactID is
+// the part of the record it backs, and AlternativeID is set when it backs a
+// competing value rather than the value the record holds.
+type exportClaim struct {
+	ID            uuid.UUID          `json:"id"`
+	Field         *record.ClaimField `json:"field"`
+	ImpactID      *uuid.UUID         `json:"impactId"`
+	AlternativeID *uuid.UUID         `json:"alternativeId"`
+	Confidence    record.Confidence  `json:"confidence"`
+	Page          *string            `json:"page"`
+	Quote         *string            `json:"quote"`
+}
+
+type exportAlternative struct {
+	ID    uuid.UUID         `json:"id"`
+	Field record.ClaimField `json:"field"`
+	Value string            `json:"value"`
+}
+
+type exportLink struct {
+	ID         uuid.UUID          `json:"id"`
+	RecordID   uuid.UUID          `json:"recordId"`
+	RecordId2  uuid.UUID          `json:"recordId2"`
+	Strength   int16              `json:"strength"`
+	Confidence *record.Confidence `json:"confidence"`
+}
+
+type exportExternalId struct {
+	Scheme record.ExternalIdScheme `json:"scheme"`
+	Value  string                  `json:"value"`
+}
+
+type exportLabel struct {
+	Language    string  `json:"language"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+}
+
+type exportAlias struct {
+	Name     string  `json:"name"`
+	Language *string `json:"language"`
+}
+
+type exportTerm struct {
+	ID   uuid.UUID     `json:"id"`
+	Kind term.TermKind `json:"kind"`
+	Name string        `json:"name"`
+}
+
+type exportRecord struct {
+	ID           uuid.UUID           `json:"id"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	Location     *string             `json:"location"`
+	Significance *string             `json:"significance"`
+	Url          string              `json:"url"`
+	StartDate    string              `json:"startDate"`
+	EndDate      string              `json:"endDate"`
+	RecordStatus record.RecordStatus `json:"recordStatus"`
+	Type         record.Type         `json:"type"`
+	Confidence   *record.Confidence  `json:"confidence"`
+	Attributes   json.RawMessage     `json:"attributes"`
+	ExternalIds  []exportExternalId  `json:"externalIds"`
+	Labels       []exportLabel       `json:"labels"`
+	Aliases      []exportAlias       `json:"aliases"`
+	Terms        []exportTerm        `json:"terms"`
+	Impacts      []exportImpact      `json:"impacts"`
+	Sources      []exportSource      `json:"sources"`
+	Alternatives []exportAlternative `json:"alternatives"`
+	Links        []exportLink        `json:"links"`
+}
+
+func (a exportAggregate) toExport() exportRecord {
+	var attributes json.RawMessage
+	if a.Attributes != "" {
+		attributes = json.RawMessage(a.Attributes)
+	}
+	claimsBySource := lo.GroupBy(a.Claims, func(c model.Claim) uuid.UUID { return c.SourceID })
+
+	return exportRecord{
+		ID:           a.ID,
+		Title:        a.Title,
+		Description:  a.Description,
+		Location:     a.Location,
+		Significance: a.Significance,
+		Url:          a.URL,
+		StartDate:    common.ToDateString(a.StartDate),
+		EndDate:      common.ToDateString(a.EndDate),
+		RecordStatus: record.RecordStatusFromInt16(a.Status),
+		Type:         record.TypeFromInt16(a.Type),
+		Confidence:   record.ConfidenceFromNullInt16(a.Record.Confidence),
+		Attributes:   attributes,
+		ExternalIds: lo.Map(a.ExternalIds, func(e model.ExternalIdentifier, index int) exportExternalId {
+			return exportExternalId{
+				Scheme: record.ExternalIdSchemeFromInt16(e.Scheme),
+				Value:  e.Value,
+			}
+		}),
+		Labels: lo.Map(a.Labels, func(l model.RecordLabel, index int) exportLabel {
+			return exportLabel{
+				Language:    l.Language,
+				Title:       l.Title,
+				Description: l.Description,
+			}
+		}),
+		Aliases: lo.Map(a.Aliases, func(l model.RecordAlias, index int) exportAlias {
+			return exportAlias{
+				Name:     l.Name,
+				Language: l.Language,
+			}
+		}),
+		Terms: lo.Map(a.Terms, func(t model.Term, index int) exportTerm {
+			return exportTerm{
+				ID:   t.ID,
+				Kind: term.TermKindFromInt16(t.Kind),
+				Name: t.Name,
+			}
+		}),
+		Impacts: lo.Map(a.Impacts, func(i model.Impact, index int) exportImpact {
+			return exportImpact{
+				ID:          i.ID,
+				Description: i.Description,
+				Value:       i.Value,
+				Category:    record.CategoryFromInt16(i.Category),
+				Confidence:  record.ConfidenceFromNullInt16(i.Confidence),
+			}
+		}),
+		Sources: lo.Map(a.Sources, func(s model.Source, index int) exportSource {
+			return exportSource{
+				ID:             s.ID,
+				Title:          s.Title,
+				Type:           s.Type,
+				Url:            s.URL,
+				Description:    s.Description,
+				Authors:        record.SourceAuthors(s),
+				Year:           s.Year,
+				Publisher:      s.Publisher,
+				ContainerTitle: s.ContainerTitle,
+				Claims: lo.Map(claimsBySource[s.ID], func(c model.Claim, index int) exportClaim {
+					return exportClaim{
+						ID:            c.ID,
+						Field:         (*record.ClaimField)(c.Field),
+						ImpactID:      c.ImpactID,
+						AlternativeID: c.AlternativeID,
+						Confidence:    record.ConfidenceFromInt16(c.Confidence),
+						Page:          c.Page,
+						Quote:         c.Quote,
+					}
+				}),
+			}
+		}),
+		Alternatives: lo.Map(a.Alternatives, func(v model.AlternativeValue, index int) exportAlternative {
+			return exportAlternative{
+				ID:    v.ID,
+				Field: record.ClaimField(v.Field),
+				Value: v.Value,
+			}
+		}),
+		Links: lo.Map(a.Links, func(l model.Link, index int) exportLink {
+			return exportLink{
+				ID:         l.ID,
+				RecordID:   l.RecordID,
+				RecordId2:  l.RecordId2,
+				Strength:   l.Strength,
+				Confidence: record.ConfidenceFromNullInt16(l.Confidence),
+			}
+		}),
+	}
+}
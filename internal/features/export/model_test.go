@@ -0,0 +1,78 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+func TestToExport(t *testing.T) {
+	id := uuid.MustParse("6a1f3c2e-9b4d-4e8a-b7c5-0d2e1f3a4b5c")
+	source := uuid.MustParse("1c2d3e4f-5a6b-4c7d-8e9f-a0b1c2d3e4f5")
+	alternative := uuid.MustParse("9e8d7c6b-5a49-4837-a625-14f3e2d1c0b9")
+
+	aggregate := exportAggregate{
+		Record: model.Record{
+			ID:         id,
+			Title:      "Battle of Waterloo",
+			Type:       record.Event.ToInt16(),
+			Status:     record.Draft.ToInt16(),
+			Attributes: `{"victor":"Seventh Coalition"}`,
+			Confidence: lo.ToPtr(record.HighConfidence.ToInt16()),
+		},
+		Sources: []model.Source{{ID: source, RecordID: id, Title: "Waterloo: The Campaign of 1815", Authors: `["Hofschröer, Peter"]`}},
+		Claims: []model.Claim{
+			{ID: uuid.New(), SourceID: source, Field: lo.ToPtr("startDate"), Confidence: record.MediumConfidence.ToInt16()},
+			{ID: uuid.New(), SourceID: source, Field: lo.ToPtr("location"), AlternativeID: &alternative, Confidence: record.LowConfidence.ToInt16()},
+		},
+		Alternatives: []model.AlternativeValue{{ID: alternative, RecordID: id, Field: "location", Value: "Mont-Saint-Jean"}},
+		Links:        []model.Link{{ID: uuid.New(), RecordID: id, RecordId2: uuid.New(), Strength: 3}},
+		ExternalIds:  []model.ExternalIdentifier{{RecordID: id, Scheme: 0, Value: "Q48314"}},
+		Labels:       []model.RecordLabel{{RecordID: id, Language: "fr", Title: "Bataille de Waterloo"}},
+		Aliases:      []model.RecordAlias{{RecordID: id, Name: "Battle of Mont-Saint-Jean"}},
+		Terms:        []model.Term{{ID: uuid.New(), Kind: 1, Name: "Napoleonic Wars"}},
+	}
+
+	got := aggregate.toExport()
+	if got.Confidence == nil || *got.Confidence != record.HighConfidence {
+		t.Errorf("confidence = %v, expected %s", got.Confidence, record.HighConfidence)
+	}
+	if string(got.Attributes) != `{"victor":"Seventh Coalition"}` {
+		t.Errorf("attributes = %s", got.Attributes)
+	}
+	if len(got.Sources) != 1 || len(got.Sources[0].Claims) != 2 {
+		t.Fatalf("sources = %+v, expected one source with both claims", got.Sources)
+	}
+	if claim := got.Sources[0].Claims[1]; claim.AlternativeID == nil || *claim.AlternativeID != alternative || claim.Confidence != record.LowConfidence {
+		t.Errorf("claim = %+v, expected a low confidence claim backing the alternative", claim)
+	}
+	if authors := got.Sources[0].Authors; len(authors) != 1 || authors[0] != "Hofschröer, Peter" {
+		t.Errorf("authors = %q", authors)
+	}
+	if got.Links[0].Confidence != nil {
+		t.Errorf("link confidence = %v, expected none", *got.Links[0].Confidence)
+	}
+	if got.ExternalIds[0].Scheme != record.Wikidata || got.Terms[0].Name != "Napoleonic Wars" || got.Labels[0].Language != "fr" || got.Aliases[0].Name != "Battle of Mont-Saint-Jean" {
+		t.Errorf("externalIds = %+v, terms = %+v, labels = %+v, aliases = %+v", got.ExternalIds, got.Terms, got.Labels, got.Aliases)
+	}
+
+	// Records missing everything optional still encode every field
+	encoded, err := json.Marshal(exportAggregate{Record: model.Record{ID: id}}.toExport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"confidence", "attributes", "externalIds", "labels", "aliases", "terms", "alternatives"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("%s is missing from %s", field, encoded)
+		}
+	}
+}
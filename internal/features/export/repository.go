@@ -0,0 +1,197 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type IExportRepository interface {
+	Stream(c context.Context, pageSize int, fn func(page []exportAggregate) error) error
+}
+
+type ExportRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) IExportRepository {
+	return ExportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Stream walks all records through a server-side cursor inside a read-only
+// snapshot, loading what is attached to the records of one page at a time, so
+// memory use depends on pageSize rather than on the size of the dataset.
+func (r ExportRepository) Stream(c context.Context, pageSize int, fn func(page []exportAggregate) error) error {
+	tx, err := r.db.BeginTx(c, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args := SELECT(Record.AllColumns).
+		FROM(Record).
+		ORDER_BY(Record.ID).
+		Sql()
+
+	if _, err = tx.ExecContext(c, "DECLARE export_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return fmt.Errorf("error declaring export cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM export_cursor", pageSize)
+	for {
+		var records []model.Record
+		if _, err = qrm.Query(c, tx, fetch, nil, &records); err != nil {
+			return fmt.Errorf("error fetching records: %w", err)
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		page, err := r.attach(c, tx, records)
+		if err != nil {
+			return err
+		}
+		if err = fn(page); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.ExecContext(c, "CLOSE export_cursor"); err != nil {
+		return fmt.Errorf("error closing export cursor: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r ExportRepository) attach(c context.Context, tx *sql.Tx, records []model.Record) ([]exportAggregate, error) {
+	ids := lo.Map(records, func(record model.Record, index int) Expression {
+		return UUID(record.ID)
+	})
+
+	var impacts []model.Impact
+	impactStmt := SELECT(Impact.AllColumns).
+		FROM(Impact).
+		WHERE(Impact.RecordID.IN(ids...))
+	if err := impactStmt.QueryContext(c, tx, &impacts); err != nil {
+		return nil, fmt.Errorf("error getting impacts: %w", err)
+	}
+
+	var sources []model.Source
+	sourceStmt := SELECT(Source.AllColumns).
+		FROM(Source).
+		WHERE(Source.RecordID.IN(ids...))
+	if err := sourceStmt.QueryContext(c, tx, &sources); err != nil {
+		return nil, fmt.Errorf("error getting sources: %w", err)
+	}
+
+	var claims []model.Claim
+	claimStmt := SELECT(Claim.AllColumns).
+		FROM(Claim.INNER_JOIN(Source, Source.ID.EQ(Claim.SourceID))).
+		WHERE(Source.RecordID.IN(ids...)).
+		ORDER_BY(Claim.CreatedAt, Claim.ID)
+	if err := claimStmt.QueryContext(c, tx, &claims); err != nil {
+		return nil, fmt.Errorf("error getting claims: %w", err)
+	}
+
+	var alternatives []model.AlternativeValue
+	alternativeStmt := SELECT(AlternativeValue.AllColumns).
+		FROM(AlternativeValue).
+		WHERE(AlternativeValue.RecordID.IN(ids...)).
+		ORDER_BY(AlternativeValue.Field, AlternativeValue.CreatedAt)
+	if err := alternativeStmt.QueryContext(c, tx, &alternatives); err != nil {
+		return nil, fmt.Errorf("error getting alternative values: %w", err)
+	}
+
+	var links []model.Link
+	linkStmt := SELECT(Link.AllColumns).
+		FROM(Link).
+		WHERE(Link.RecordID.IN(ids...).OR(Link.RecordId2.IN(ids...)))
+	if err := linkStmt.QueryContext(c, tx, &links); err != nil {
+		return nil, fmt.Errorf("error getting links: %w", err)
+	}
+
+	var externalIds []model.ExternalIdentifier
+	externalIdStmt := SELECT(ExternalIdentifier.AllColumns).
+		FROM(ExternalIdentifier).
+		WHERE(ExternalIdentifier.RecordID.IN(ids...)).
+		ORDER_BY(ExternalIdentifier.Scheme, ExternalIdentifier.Value)
+	if err := externalIdStmt.QueryContext(c, tx, &externalIds); err != nil {
+		return nil, fmt.Errorf("error getting external identifiers: %w", err)
+	}
+
+	var labels []model.RecordLabel
+	labelStmt := SELECT(RecordLabel.AllColumns).
+		FROM(RecordLabel).
+		WHERE(RecordLabel.RecordID.IN(ids...)).
+		ORDER_BY(RecordLabel.Language)
+	if err := labelStmt.QueryContext(c, tx, &labels); err != nil {
+		return nil, fmt.Errorf("error getting labels: %w", err)
+	}
+
+	var aliases []model.RecordAlias
+	aliasStmt := SELECT(RecordAlias.AllColumns).
+		FROM(RecordAlias).
+		WHERE(RecordAlias.RecordID.IN(ids...)).
+		ORDER_BY(RecordAlias.Name)
+	if err := aliasStmt.QueryContext(c, tx, &aliases); err != nil {
+		return nil, fmt.Errorf("error getting aliases: %w", err)
+	}
+
+	var terms []struct {
+		model.RecordTerm
+		Term model.Term
+	}
+	termStmt := SELECT(RecordTerm.AllColumns, Term.AllColumns).
+		FROM(RecordTerm.INNER_JOIN(Term, Term.ID.EQ(RecordTerm.TermID))).
+		WHERE(RecordTerm.RecordID.IN(ids...)).
+		ORDER_BY(Term.Kind, Term.Name)
+	if err := termStmt.QueryContext(c, tx, &terms); err != nil {
+		return nil, fmt.Errorf("error getting terms: %w", err)
+	}
+
+	impactsByRecord := lo.GroupBy(impacts, func(i model.Impact) uuid.UUID { return i.RecordID })
+	sourcesByRecord := lo.GroupBy(sources, func(s model.Source) uuid.UUID { return s.RecordID })
+	recordBySource := lo.SliceToMap(sources, func(s model.Source) (uuid.UUID, uuid.UUID) { return s.ID, s.RecordID })
+	claimsByRecord := lo.GroupBy(claims, func(c model.Claim) uuid.UUID { return recordBySource[c.SourceID] })
+	alternativesByRecord := lo.GroupBy(alternatives, func(a model.AlternativeValue) uuid.UUID { return a.RecordID })
+	linksByRecord := map[uuid.UUID][]model.Link{}
+	for _, l := range links {
+		linksByRecord[l.RecordID] = append(linksByRecord[l.RecordID], l)
+		linksByRecord[l.RecordId2] = append(linksByRecord[l.RecordId2], l)
+	}
+	externalIdsByRecord := lo.GroupBy(externalIds, func(e model.ExternalIdentifier) uuid.UUID { return e.RecordID })
+	labelsByRecord := lo.GroupBy(labels, func(l model.RecordLabel) uuid.UUID { return l.RecordID })
+	aliasesByRecord := lo.GroupBy(aliases, func(a model.RecordAlias) uuid.UUID { return a.RecordID })
+	termsByRecord := map[uuid.UUID][]model.Term{}
+	for _, t := range terms {
+		termsByRecord[t.RecordID] = append(termsByRecord[t.RecordID], t.Term)
+	}
+
+	return lo.Map(records, func(record model.Record, index int) exportAggregate {
+		return exportAggregate{
+			Record:       record,
+			Impacts:      impactsByRecord[record.ID],
+			Sources:      sourcesByRecord[record.ID],
+			Claims:       claimsByRecord[record.ID],
+			Alternatives: alternativesByRecord[record.ID],
+			Links:        linksByRecord[record.ID],
+			ExternalIds:  externalIdsByRecord[record.ID],
+			Labels:       labelsByRecord[record.ID],
+			Aliases:      aliasesByRecord[record.ID],
+			Terms:        termsByRecord[record.ID],
+		}
+	}), nil
+}
@@ -0,0 +1,125 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+)
+
+const pageSize = 500
+
+type IExportService interface {
+	Export(c context.Context, w io.Writer, format Format) error
+}
+
+type ExportService struct {
+	exportRepository IExportRepository
+	logger           *slog.Logger
+}
+
+func NewExportService(exportRepository IExportRepository, logger *slog.Logger) IExportService {
+	return ExportService{
+		exportRepository: exportRepository,
+		logger:           logger,
+	}
+}
+
+func (s ExportService) Export(c context.Context, w io.Writer, format Format) error {
+	switch format {
+	case NDJSON:
+		return s.exportNDJSON(c, w)
+	case CSV:
+		return s.exportCSV(c, w)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+// exportNDJSON writes one record per line with everything attached to it
+// nested: impacts, sources with their claims, competing values, links,
+// external identifiers, labels, aliases and terms.
+func (s ExportService) exportNDJSON(c context.Context, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return s.exportRepository.Stream(c, pageSize, func(page []exportAggregate) error {
+		for _, a := range page {
+			if err := encoder.Encode(a.toExport()); err != nil {
+				return fmt.Errorf("error writing record: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+var csvHeader = []string{
+	"kind", "id", "recordId", "recordId2", "title", "description", "location", "significance", "url",
+	"startDate", "endDate", "type", "recordStatus", "category", "value", "strength",
+}
+
+// exportCSV writes a single flat table in which the kind column tells records,
+// impacts, sources and links apart. Impacts and sources point at their record
+// through recordId, links through recordId and recordId2. Each link is written
+// once, after the record it starts from. Confidence, attributes, external
+// identifiers, labels, aliases, terms, claims, competing values and the
+// citation details of sources do not fit the table and are left out; export
+// NDJSON to keep them.
+func (s ExportService) exportCSV(c context.Context, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("error writing csv header: %w", err)
+	}
+
+	err := s.exportRepository.Stream(c, pageSize, func(page []exportAggregate) error {
+		for _, a := range page {
+			rows := [][]string{{
+				"record", a.ID.String(), "", "", a.Title, a.Description, deref(a.Location), deref(a.Significance), a.URL,
+				common.ToDateString(a.StartDate), common.ToDateString(a.EndDate),
+				string(record.TypeFromInt16(a.Type)), string(record.RecordStatusFromInt16(a.Status)), "", "", "",
+			}}
+			for _, i := range a.Impacts {
+				rows = append(rows, []string{
+					"impact", i.ID.String(), a.ID.String(), "", "", i.Description, "", "", "",
+					"", "", "", "", string(record.CategoryFromInt16(i.Category)), strconv.Itoa(int(i.Value)), "",
+				})
+			}
+			for _, source := range a.Sources {
+				rows = append(rows, []string{
+					"source", source.ID.String(), a.ID.String(), "", source.Title, deref(source.Description), "", "", source.URL,
+					"", "", strconv.Itoa(int(source.Type)), "", "", "", "",
+				})
+			}
+			for _, l := range a.Links {
+				if l.RecordID != a.ID {
+					continue
+				}
+				rows = append(rows, []string{
+					"link", l.ID.String(), l.RecordID.String(), l.RecordId2.String(), "", "", "", "", "",
+					"", "", "", "", "", "", strconv.Itoa(int(l.Strength)),
+				})
+			}
+
+			if err := writer.WriteAll(rows); err != nil {
+				return fmt.Errorf("error writing csv: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+
+	"historylink/internal/common"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/samber/lo"
+)
+
+func NewImportResources(conn *sql.DB, logger *slog.Logger) ImportResources {
+	return ImportResources{
+		logger:        logger,
+		ImportService: NewImportService(NewRepository(conn, logger), logger),
+	}
+}
+
+type ImportResources struct {
+	ImportService IImportService
+	logger        *slog.Logger
+}
+
+func (rs ImportResources) importFile(c context.Context, input *struct {
+	common.ActorInput
+	Format    Format `query:"format" enum:"csv,ndjson" default:"ndjson"`
+	ChunkSize int    `query:"chunkSize" minimum:"0" default:"0" doc:"Commit every chunkSize rows in their own transaction, 0 commits everything at once"`
+	DryRun    bool   `query:"dryRun" doc:"Validate the file without writing anything"`
+	RawBody   []byte
+}) (*struct {
+	Body importReport
+}, error) {
+	report, err := rs.ImportService.Import(input.WithActor(c), bytes.NewReader(input.RawBody), ImportOptions{
+		Format:    input.Format,
+		ChunkSize: input.ChunkSize,
+		DryRun:    input.DryRun,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrImportInvalid):
+			return nil, huma.Error422UnprocessableEntity(err.Error(), lo.Map(report.Errors, func(e rowError, index int) error {
+				return &huma.ErrorDetail{Message: e.Message, Location: e.location()}
+			})...)
+		}
+		rs.logger.Error(err.Error())
+		return nil, err
+	}
+
+	return &struct {
+		Body importReport
+	}{
+		Body: report,
+	}, nil
+}
+
+func (rs ImportResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "import",
+		Method:      http.MethodPost,
+		Path:        "/import",
+		Responses: map[string]*huma.Response{
+			"422": {
+				Description: "Import contains invalid rows",
+				Content: map[string]*huma.MediaType{
+					"application/json": {
+						Schema: s.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(huma.ErrorModel{}), true, ""),
+					},
+				},
+			},
+		},
+	}, rs.importFile)
+}
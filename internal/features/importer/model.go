@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"fmt"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+type Kind string
+
+const (
+	RecordKind Kind = "record"
+	ImpactKind Kind = "impact"
+	LinkKind   Kind = "link"
+)
+
+type ImportOptions struct {
+	Format    Format
+	ChunkSize int
+	DryRun    bool
+}
+
+// importRow is a single line of an import file. Which fields are used depends
+// on Kind: records use the record fields, impacts reference their record
+// through Record and links reference both ends through From and To. A
+// reference is a key or title of a record in the same file, or the id or
+// title of an existing record.
+type importRow struct {
+	Kind         Kind                 `json:"kind"`
+	Key          string               `json:"key"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	Location     string               `json:"location"`
+	Significance string               `json:"significance"`
+	Url          string               `json:"url"`
+	StartDate    string               `json:"startDate"`
+	EndDate      string               `json:"endDate"`
+	RecordStatus record.RecordStatus  `json:"recordStatus"`
+	Type         record.Type          `json:"type"`
+	Impacts      []importImpactColumn `json:"impacts"`
+	Record       string               `json:"record"`
+	Category     record.Category      `json:"category"`
+	Value        int16                `json:"value"`
+	From         string               `json:"from"`
+	To           string               `json:"to"`
+	Strength     int16                `json:"strength"`
+
+	line int
+}
+
+type importImpactColumn struct {
+	Description string          `json:"description"`
+	Value       int16           `json:"value"`
+	Category    record.Category `json:"category"`
+}
+
+type rowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// location renders the row and field the way huma reports body errors.
+func (e rowError) location() string {
+	if e.Field == "" {
+		return fmt.Sprintf("body.line[%d]", e.Line)
+	}
+	return fmt.Sprintf("body.line[%d].%s", e.Line, e.Field)
+}
+
+func (e rowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+}
+
+type importReport struct {
+	DryRun  bool                 `json:"dryRun"`
+	Records int                  `json:"records"`
+	Impacts int                  `json:"impacts"`
+	Links   int                  `json:"links"`
+	Chunks  int                  `json:"chunks"`
+	Keys    map[string]uuid.UUID `json:"keys"`
+	Errors  []rowError           `json:"errors,omitempty"`
+}
@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"historylink/internal/features/record"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const maxLineSize = 1024 * 1024
+
+// unsupportedFields are record fields the import does not write. Rows setting
+// them are refused rather than imported without them.
+var unsupportedFields = []string{"confidence", "attributes", "externalIds", "labels", "aliases", "terms"}
+
+const unsupportedMessage = "is not imported, set it through the record API once the record exists"
+
+func parse(r io.Reader, format Format) ([]importRow, []rowError, error) {
+	switch format {
+	case CSV:
+		return parseCSV(r)
+	case NDJSON:
+		return parseNDJSON(r)
+	}
+	return nil, nil, fmt.Errorf("unsupported import format %q", format)
+}
+
+func parseNDJSON(r io.Reader) ([]importRow, []rowError, error) {
+	var rows []importRow
+	var rowErrors []rowError
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(text, &fields); err == nil {
+			var unsupported []rowError
+			for _, field := range unsupportedFields {
+				if _, ok := fields[field]; ok {
+					unsupported = append(unsupported, rowError{Line: line, Field: field, Message: unsupportedMessage})
+				}
+			}
+			if len(unsupported) > 0 {
+				rowErrors = append(rowErrors, unsupported...)
+				continue
+			}
+		}
+
+		var row importRow
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			rowErrors = append(rowErrors, rowError{Line: line, Message: err.Error()})
+			continue
+		}
+		row.line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error reading import file: %w", err)
+	}
+
+	return rows, rowErrors, nil
+}
+
+var csvColumns = map[string]func(row *importRow, value string) error{
+	"kind":         func(row *importRow, v string) error { row.Kind = Kind(v); return nil },
+	"key":          func(row *importRow, v string) error { row.Key = v; return nil },
+	"title":        func(row *importRow, v string) error { row.Title = v; return nil },
+	"description":  func(row *importRow, v string) error { row.Description = v; return nil },
+	"location":     func(row *importRow, v string) error { row.Location = v; return nil },
+	"significance": func(row *importRow, v string) error { row.Significance = v; return nil },
+	"url":          func(row *importRow, v string) error { row.Url = v; return nil },
+	"startDate":    func(row *importRow, v string) error { row.StartDate = v; return nil },
+	"endDate":      func(row *importRow, v string) error { row.EndDate = v; return nil },
+	"recordStatus": func(row *importRow, v string) error { row.RecordStatus = record.RecordStatus(v); return nil },
+	"type":         func(row *importRow, v string) error { row.Type = record.Type(v); return nil },
+	"record":       func(row *importRow, v string) error { row.Record = v; return nil },
+	"category":     func(row *importRow, v string) error { row.Category = record.Category(v); return nil },
+	"value":        func(row *importRow, v string) error { return parseInt16(v, &row.Value) },
+	"from":         func(row *importRow, v string) error { row.From = v; return nil },
+	"to":           func(row *importRow, v string) error { row.To = v; return nil },
+	"strength":     func(row *importRow, v string) error { return parseInt16(v, &row.Strength) },
+}
+
+func parseInt16(v string, dest *int16) error {
+	if v == "" {
+		return nil
+	}
+	i, err := strconv.ParseInt(v, 10, 16)
+	if err != nil {
+		return errors.New("must be a whole number")
+	}
+	*dest = int16(i)
+	return nil
+}
+
+func parseCSV(r io.Reader) ([]importRow, []rowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("error reading csv header: %w", err)
+	}
+
+	for i, name := range header {
+		header[i] = strings.TrimSpace(name)
+		if slices.Contains(unsupportedFields, header[i]) {
+			return nil, []rowError{{Line: 1, Field: header[i], Message: unsupportedMessage}}, nil
+		}
+		if _, ok := csvColumns[header[i]]; !ok {
+			return nil, []rowError{{Line: 1, Field: header[i], Message: "unknown column"}}, nil
+		}
+	}
+
+	var rows []importRow
+	var rowErrors []rowError
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, rowError{Line: parseErr.Line, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("error reading csv: %w", err)
+		}
+		if len(fields) > len(header) {
+			rowErrors = append(rowErrors, rowError{Line: line, Message: "more fields than columns in header"})
+			continue
+		}
+
+		row := importRow{line: line}
+		valid := true
+		for i, value := range fields {
+			if err := csvColumns[header[i]](&row, strings.TrimSpace(value)); err != nil {
+				rowErrors = append(rowErrors, rowError{Line: line, Field: header[i], Message: err.Error()})
+				valid = false
+			}
+		}
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, rowErrors, nil
+}
@@ -0,0 +1,142 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"historylink/internal/features/record"
+)
+
+func TestParseNDJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expected       []importRow
+		expectedErrors []rowError
+	}{
+		{
+			name:  "record and link",
+			input: `{"kind":"record","key":"r1","title":"Treaty of Westphalia","type":"event"}` + "\n" + `{"kind":"link","from":"r1","to":"Thirty Years' War","strength":3}`,
+			expected: []importRow{
+				{Kind: RecordKind, Key: "r1", Title: "Treaty of Westphalia", Type: record.Event, line: 1},
+				{Kind: LinkKind, From: "r1", To: "Thirty Years' War", Strength: 3, line: 2},
+			},
+		},
+		{
+			name:  "blank lines keep their line numbers",
+			input: "\n  \n" + `{"kind":"impact","record":"r1","category":"political","value":7}`,
+			expected: []importRow{
+				{Kind: ImpactKind, Record: "r1", Category: record.Political, Value: 7, line: 3},
+			},
+		},
+		{
+			name:           "unknown field",
+			input:          `{"kind":"record","colour":"red"}`,
+			expectedErrors: []rowError{{Line: 1, Message: `json: unknown field "colour"`}},
+		},
+		{
+			name:  "unsupported fields",
+			input: `{"kind":"record","title":"Congress of Vienna","labels":[{"language":"de","title":"Wiener Kongress"}],"confidence":"high"}`,
+			expectedErrors: []rowError{
+				{Line: 1, Field: "confidence", Message: unsupportedMessage},
+				{Line: 1, Field: "labels", Message: unsupportedMessage},
+			},
+		},
+		{
+			name:  "malformed line is skipped",
+			input: `{"kind":` + "\n" + `{"kind":"record","title":"Battle of Hastings"}`,
+			expected: []importRow{
+				{Kind: RecordKind, Title: "Battle of Hastings", line: 2},
+			},
+			expectedErrors: []rowError{{Line: 1, Message: "unexpected EOF"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, rowErrors, err := parse(strings.NewReader(tt.input), NDJSON)
+			if err != nil {
+				t.Fatalf("parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.expected) {
+				t.Errorf("parse() rows = %+v, expected %+v", rows, tt.expected)
+			}
+			if !reflect.DeepEqual(rowErrors, tt.expectedErrors) {
+				t.Errorf("parse() errors = %+v, expected %+v", rowErrors, tt.expectedErrors)
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expected       []importRow
+		expectedErrors []rowError
+	}{
+		{
+			name:  "records with trimmed values",
+			input: "kind, key, title,startDate\nrecord, r1 , Fall of Constantinople,1453-05-29\n",
+			expected: []importRow{
+				{Kind: RecordKind, Key: "r1", Title: "Fall of Constantinople", StartDate: "1453-05-29", line: 2},
+			},
+		},
+		{
+			name:  "short rows leave the remaining columns empty",
+			input: "kind,from,to,strength\nlink,r1\n",
+			expected: []importRow{
+				{Kind: LinkKind, From: "r1", line: 2},
+			},
+		},
+		{
+			name:  "numbers",
+			input: "kind,record,value\nimpact,r1,7\nimpact,r1,seven\n",
+			expected: []importRow{
+				{Kind: ImpactKind, Record: "r1", Value: 7, line: 2},
+			},
+			expectedErrors: []rowError{{Line: 3, Field: "value", Message: "must be a whole number"}},
+		},
+		{
+			name:           "more fields than columns",
+			input:          "kind,title\nrecord,Magna Carta,1215\n",
+			expectedErrors: []rowError{{Line: 2, Message: "more fields than columns in header"}},
+		},
+		{
+			name:           "unknown column",
+			input:          "kind,colour\nrecord,red\n",
+			expectedErrors: []rowError{{Line: 1, Field: "colour", Message: "unknown column"}},
+		},
+		{
+			name:           "unsupported column",
+			input:          "kind,title,externalIds\nrecord,Magna Carta,Q3003\n",
+			expectedErrors: []rowError{{Line: 1, Field: "externalIds", Message: unsupportedMessage}},
+		},
+		{
+			name:  "empty file",
+			input: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, rowErrors, err := parse(strings.NewReader(tt.input), CSV)
+			if err != nil {
+				t.Fatalf("parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.expected) {
+				t.Errorf("parse() rows = %+v, expected %+v", rows, tt.expected)
+			}
+			if !reflect.DeepEqual(rowErrors, tt.expectedErrors) {
+				t.Errorf("parse() errors = %+v, expected %+v", rowErrors, tt.expectedErrors)
+			}
+		})
+	}
+}
+
+func TestParseUnsupportedFormat(t *testing.T) {
+	if _, _, err := parse(strings.NewReader(""), "xml"); err == nil {
+		t.Errorf("parse() error = nil, expected an unsupported format")
+	}
+}
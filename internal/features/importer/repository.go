@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type IImportRepository interface {
+	GetRecordsByTitles(c context.Context, titles []string) ([]model.Record, error)
+	GetRecordsByIds(c context.Context, ids []uuid.UUID) ([]model.Record, error)
+	GetLinksBetween(c context.Context, ids []uuid.UUID) ([]model.Link, error)
+	Insert(c context.Context, batch importBatch) error
+}
+
+type ImportRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) IImportRepository {
+	return ImportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// importBatch is the unit of work committed in a single transaction. Records
+// carry their ids so impacts and links can reference them up front.
+type importBatch struct {
+	Records []model.Record
+	Impacts []model.Impact
+	Links   []model.Link
+}
+
+func (r ImportRepository) GetRecordsByTitles(c context.Context, titles []string) ([]model.Record, error) {
+	if len(titles) == 0 {
+		return nil, nil
+	}
+
+	stmt := SELECT(Record.ID, Record.Title).
+		FROM(Record).
+		WHERE(Record.Title.IN(lo.Map(titles, func(title string, index int) Expression {
+			return String(title)
+		})...))
+
+	var dest []model.Record
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting records by title: %w", err)
+	}
+
+	return dest, nil
+}
+
+func (r ImportRepository) GetRecordsByIds(c context.Context, ids []uuid.UUID) ([]model.Record, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	stmt := SELECT(Record.ID, Record.Title).
+		FROM(Record).
+		WHERE(Record.ID.IN(uuidExpressions(ids)...))
+
+	var dest []model.Record
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting records by id: %w", err)
+	}
+
+	return dest, nil
+}
+
+func (r ImportRepository) GetLinksBetween(c context.Context, ids []uuid.UUID) ([]model.Link, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	stmt := SELECT(Link.AllColumns).
+		FROM(Link).
+		WHERE(Link.RecordID.IN(uuidExpressions(ids)...).
+			AND(Link.RecordId2.IN(uuidExpressions(ids)...)))
+
+	var dest []model.Link
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting existing links: %w", err)
+	}
+
+	return dest, nil
+}
+
+func (r ImportRepository) Insert(c context.Context, batch importBatch) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = common.SetActor(c, tx); err != nil {
+		return err
+	}
+
+	if len(batch.Records) > 0 {
+		stmt := Record.INSERT(Record.AllColumns.Except(Record.Attributes)).
+			MODELS(batch.Records)
+
+		if _, err = stmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error inserting records: %w", err)
+		}
+	}
+
+	if len(batch.Impacts) > 0 {
+		stmt := Impact.INSERT(Impact.MutableColumns).
+			MODELS(batch.Impacts)
+
+		if _, err = stmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error inserting impacts: %w", err)
+		}
+	}
+
+	if len(batch.Links) > 0 {
+		stmt := Link.INSERT(Link.MutableColumns).
+			MODELS(batch.Links)
+
+		if _, err = stmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error inserting links: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func uuidExpressions(ids []uuid.UUID) []Expression {
+	return lo.Map(ids, func(id uuid.UUID, index int) Expression {
+		return UUID(id)
+	})
+}
@@ -0,0 +1,451 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type IImportService interface {
+	Import(c context.Context, r io.Reader, options ImportOptions) (importReport, error)
+}
+
+type ImportService struct {
+	importRepository IImportRepository
+	logger           *slog.Logger
+}
+
+func NewImportService(importRepository IImportRepository, logger *slog.Logger) IImportService {
+	return ImportService{
+		importRepository: importRepository,
+		logger:           logger,
+	}
+}
+
+// Import parses and validates the whole file before writing anything. When
+// any row is invalid the report lists every problem and common.ErrImportInvalid
+// is returned. Valid files are committed in one transaction, or in chunks of
+// options.ChunkSize rows when it is set, records before links. Unlike records
+// created one by one, imported records are not checked for duplicates.
+func (s ImportService) Import(c context.Context, r io.Reader, options ImportOptions) (importReport, error) {
+	report := importReport{DryRun: options.DryRun}
+
+	rows, rowErrors, err := parse(r, options.Format)
+	if err != nil {
+		return report, err
+	}
+
+	plan := newImportPlan()
+	rowErrors = append(rowErrors, plan.addRecords(rows)...)
+
+	resolver, err := s.newResolver(c, plan, rows)
+	if err != nil {
+		return report, err
+	}
+	rowErrors = append(rowErrors, plan.addImpacts(rows)...)
+	rowErrors = append(rowErrors, plan.addLinks(rows, resolver)...)
+
+	linkErrors, err := s.checkExistingLinks(c, plan)
+	if err != nil {
+		return report, err
+	}
+	rowErrors = append(rowErrors, linkErrors...)
+
+	report.Records = len(plan.records)
+	report.Impacts = len(plan.impacts)
+	report.Links = len(plan.links)
+	report.Keys = plan.keys
+
+	if len(rowErrors) > 0 {
+		sort.SliceStable(rowErrors, func(i, j int) bool {
+			return rowErrors[i].Line < rowErrors[j].Line
+		})
+		report.Errors = rowErrors
+		return report, common.ErrImportInvalid
+	}
+	if options.DryRun {
+		return report, nil
+	}
+
+	batches := plan.batches(options.ChunkSize)
+	for _, batch := range batches {
+		if err := s.importRepository.Insert(c, batch); err != nil {
+			return report, fmt.Errorf("error importing chunk %d of %d: %w", report.Chunks+1, len(batches), err)
+		}
+		report.Chunks++
+	}
+
+	return report, nil
+}
+
+type importPlan struct {
+	records []model.Record
+	impacts []model.Impact
+	links   []model.Link
+
+	keys    map[string]uuid.UUID
+	titles  map[string][]uuid.UUID
+	lines   map[uuid.UUID]int
+	linkRow map[int]int
+}
+
+func newImportPlan() *importPlan {
+	return &importPlan{
+		keys:    map[string]uuid.UUID{},
+		titles:  map[string][]uuid.UUID{},
+		lines:   map[uuid.UUID]int{},
+		linkRow: map[int]int{},
+	}
+}
+
+func (p *importPlan) addRecords(rows []importRow) []rowError {
+	var rowErrors []rowError
+	for _, row := range rows {
+		if row.Kind != RecordKind {
+			if row.Kind != ImpactKind && row.Kind != LinkKind {
+				rowErrors = append(rowErrors, rowError{Line: row.line, Field: "kind", Message: "must be one of record, impact, link"})
+			}
+			continue
+		}
+
+		errs := validateRecord(row)
+		if row.Key != "" {
+			if _, exists := p.keys[row.Key]; exists {
+				errs = append(errs, rowError{Line: row.line, Field: "key", Message: fmt.Sprintf("duplicate key %q", row.Key)})
+			}
+		}
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+
+		status := row.RecordStatus
+		if status == "" {
+			status = record.Draft
+		}
+		id := uuid.New()
+		p.records = append(p.records, model.Record{
+			ID:           id,
+			Title:        row.Title,
+			Description:  row.Description,
+			Location:     &row.Location,
+			Significance: &row.Significance,
+			URL:          row.Url,
+			StartDate:    common.ToTime(row.StartDate),
+			EndDate:      common.ToTime(row.EndDate),
+			Type:         row.Type.ToInt16(),
+			Status:       status.ToInt16(),
+		})
+		p.lines[id] = row.line
+		if row.Key != "" {
+			p.keys[row.Key] = id
+		}
+		p.titles[row.Title] = append(p.titles[row.Title], id)
+
+		for _, impact := range row.Impacts {
+			p.impacts = append(p.impacts, model.Impact{
+				RecordID:    id,
+				Description: impact.Description,
+				Value:       impact.Value,
+				Category:    impact.Category.ToInt16(),
+			})
+		}
+	}
+	return rowErrors
+}
+
+func (p *importPlan) addImpacts(rows []importRow) []rowError {
+	var rowErrors []rowError
+	for _, row := range rows {
+		if row.Kind != ImpactKind {
+			continue
+		}
+
+		errs := validateImpact(row.line, "", importImpactColumn{
+			Description: row.Description,
+			Value:       row.Value,
+			Category:    row.Category,
+		})
+		id, err := p.resolveLocal(row.Record)
+		if err != "" {
+			errs = append(errs, rowError{Line: row.line, Field: "record", Message: err})
+		}
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+
+		p.impacts = append(p.impacts, model.Impact{
+			RecordID:    id,
+			Description: row.Description,
+			Value:       row.Value,
+			Category:    row.Category.ToInt16(),
+		})
+	}
+	return rowErrors
+}
+
+func (p *importPlan) addLinks(rows []importRow, resolver resolver) []rowError {
+	var rowErrors []rowError
+	seen := map[[2]uuid.UUID]int{}
+	for _, row := range rows {
+		if row.Kind != LinkKind {
+			continue
+		}
+
+		from, fromErr := resolver.resolve(row.From)
+		to, toErr := resolver.resolve(row.To)
+		var errs []rowError
+		if fromErr != "" {
+			errs = append(errs, rowError{Line: row.line, Field: "from", Message: fromErr})
+		}
+		if toErr != "" {
+			errs = append(errs, rowError{Line: row.line, Field: "to", Message: toErr})
+		}
+		if len(errs) == 0 {
+			if from == to {
+				errs = append(errs, rowError{Line: row.line, Message: common.ErrLinkToItself.Error()})
+			} else if line, exists := seen[linkKey(from, to)]; exists {
+				errs = append(errs, rowError{Line: row.line, Message: fmt.Sprintf("%s on line %d", common.ErrLinkAlreadyExists.Error(), line)})
+			}
+		}
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+
+		seen[linkKey(from, to)] = row.line
+		p.linkRow[len(p.links)] = row.line
+		p.links = append(p.links, model.Link{
+			RecordID:  from,
+			RecordId2: to,
+			Strength:  row.Strength,
+		})
+	}
+	return rowErrors
+}
+
+// resolveLocal finds a record of the import file by key or title, returning a
+// message describing why the reference could not be resolved otherwise.
+func (p *importPlan) resolveLocal(reference string) (uuid.UUID, string) {
+	if reference == "" {
+		return uuid.Nil, "is required"
+	}
+	if id, ok := p.keys[reference]; ok {
+		return id, ""
+	}
+	switch ids := p.titles[reference]; len(ids) {
+	case 0:
+		return uuid.Nil, fmt.Sprintf("no record with key or title %q in import", reference)
+	case 1:
+		return ids[0], ""
+	}
+	return uuid.Nil, fmt.Sprintf("title %q is ambiguous, use a key", reference)
+}
+
+// batches splits the plan into transactions. Records and their impacts are
+// committed before any link so every chunk only references committed or
+// same-chunk records.
+func (p *importPlan) batches(chunkSize int) []importBatch {
+	if chunkSize <= 0 {
+		return []importBatch{{Records: p.records, Impacts: p.impacts, Links: p.links}}
+	}
+
+	impactsByRecord := lo.GroupBy(p.impacts, func(impact model.Impact) uuid.UUID {
+		return impact.RecordID
+	})
+
+	var batches []importBatch
+	for _, records := range lo.Chunk(p.records, chunkSize) {
+		batch := importBatch{Records: records}
+		for _, r := range records {
+			batch.Impacts = append(batch.Impacts, impactsByRecord[r.ID]...)
+			delete(impactsByRecord, r.ID)
+		}
+		batches = append(batches, batch)
+	}
+	for _, links := range lo.Chunk(p.links, chunkSize) {
+		batches = append(batches, importBatch{Links: links})
+	}
+	return batches
+}
+
+type resolver struct {
+	plan     *importPlan
+	existing map[string][]uuid.UUID
+}
+
+// newResolver looks up every link reference that is not a key or title of
+// the import file among the existing records, by id or by title.
+func (s ImportService) newResolver(c context.Context, plan *importPlan, rows []importRow) (resolver, error) {
+	var ids []uuid.UUID
+	var titles []string
+	for _, row := range rows {
+		if row.Kind != LinkKind {
+			continue
+		}
+		for _, reference := range []string{row.From, row.To} {
+			if _, message := plan.resolveLocal(reference); message == "" || reference == "" {
+				continue
+			}
+			if id, err := uuid.Parse(reference); err == nil {
+				ids = append(ids, id)
+			} else {
+				titles = append(titles, reference)
+			}
+		}
+	}
+
+	r := resolver{plan: plan, existing: map[string][]uuid.UUID{}}
+
+	byId, err := s.importRepository.GetRecordsByIds(c, lo.Uniq(ids))
+	if err != nil {
+		return r, err
+	}
+	for _, existing := range byId {
+		r.existing[existing.ID.String()] = []uuid.UUID{existing.ID}
+	}
+
+	byTitle, err := s.importRepository.GetRecordsByTitles(c, lo.Uniq(titles))
+	if err != nil {
+		return r, err
+	}
+	for _, existing := range byTitle {
+		r.existing[existing.Title] = append(r.existing[existing.Title], existing.ID)
+	}
+
+	return r, nil
+}
+
+func (r resolver) resolve(reference string) (uuid.UUID, string) {
+	id, message := r.plan.resolveLocal(reference)
+	if message == "" || reference == "" {
+		return id, message
+	}
+	if _, ambiguous := r.plan.titles[reference]; ambiguous {
+		return id, message
+	}
+
+	switch ids := r.existing[reference]; len(ids) {
+	case 0:
+		return uuid.Nil, fmt.Sprintf("no record with key, title or id %q", reference)
+	case 1:
+		return ids[0], ""
+	}
+	return uuid.Nil, fmt.Sprintf("%d existing records are titled %q, use the id", len(r.existing[reference]), reference)
+}
+
+// checkExistingLinks rejects links between two existing records that are
+// already linked, mirroring the check of the link service.
+func (s ImportService) checkExistingLinks(c context.Context, plan *importPlan) ([]rowError, error) {
+	var ids []uuid.UUID
+	for _, l := range plan.links {
+		if _, local := plan.lines[l.RecordID]; local {
+			continue
+		}
+		if _, local := plan.lines[l.RecordId2]; local {
+			continue
+		}
+		ids = append(ids, l.RecordID, l.RecordId2)
+	}
+
+	existing, err := s.importRepository.GetLinksBetween(c, lo.Uniq(ids))
+	if err != nil {
+		return nil, err
+	}
+	existingKeys := lo.SliceToMap(existing, func(l model.Link) ([2]uuid.UUID, bool) {
+		return linkKey(l.RecordID, l.RecordId2), true
+	})
+
+	var rowErrors []rowError
+	for i, l := range plan.links {
+		if existingKeys[linkKey(l.RecordID, l.RecordId2)] {
+			rowErrors = append(rowErrors, rowError{Line: plan.linkRow[i], Message: common.ErrLinkAlreadyExists.Error()})
+		}
+	}
+	return rowErrors, nil
+}
+
+// linkKey identifies a link regardless of its direction.
+func linkKey(a, b uuid.UUID) [2]uuid.UUID {
+	if a.String() > b.String() {
+		a, b = b, a
+	}
+	return [2]uuid.UUID{a, b}
+}
+
+func validateRecord(row importRow) []rowError {
+	var errs []rowError
+	required := func(field, value string) {
+		if value == "" {
+			errs = append(errs, rowError{Line: row.line, Field: field, Message: "is required"})
+		}
+	}
+	maxLength := func(field, value string) {
+		if len(value) > 255 {
+			errs = append(errs, rowError{Line: row.line, Field: field, Message: "must be at most 255 characters"})
+		}
+	}
+
+	required("title", row.Title)
+	required("description", row.Description)
+	required("url", row.Url)
+	required("startDate", row.StartDate)
+	required("endDate", row.EndDate)
+	maxLength("title", row.Title)
+	maxLength("description", row.Description)
+	maxLength("location", row.Location)
+	maxLength("significance", row.Significance)
+	maxLength("url", row.Url)
+
+	start, end := common.ToTime(row.StartDate), common.ToTime(row.EndDate)
+	if row.StartDate != "" && start == nil {
+		errs = append(errs, rowError{Line: row.line, Field: "startDate", Message: "must be a date formatted as YYYY-MM-DD"})
+	}
+	if row.EndDate != "" && end == nil {
+		errs = append(errs, rowError{Line: row.line, Field: "endDate", Message: "must be a date formatted as YYYY-MM-DD"})
+	}
+	if start != nil && end != nil && end.Before(*start) {
+		errs = append(errs, rowError{Line: row.line, Field: "endDate", Message: "must not be before startDate"})
+	}
+
+	if row.Type.ToInt16() < 0 {
+		errs = append(errs, rowError{Line: row.line, Field: "type", Message: "must be one of " + strings.Join(record.Types.Names(), ", ")})
+	}
+	if row.RecordStatus != "" && row.RecordStatus.ToInt16() < 0 {
+		errs = append(errs, rowError{Line: row.line, Field: "recordStatus", Message: "must be one of " + strings.Join(record.Statuses.Names(), ", ")})
+	}
+	// Imported records have no sources yet, so nothing backs their facts
+	if row.RecordStatus == record.Reviewed {
+		errs = append(errs, rowError{Line: row.line, Field: "recordStatus", Message: "must not be reviewed before sources back the record"})
+	}
+
+	for i, impact := range row.Impacts {
+		errs = append(errs, validateImpact(row.line, fmt.Sprintf("impacts[%d].", i), impact)...)
+	}
+
+	return errs
+}
+
+func validateImpact(line int, prefix string, impact importImpactColumn) []rowError {
+	var errs []rowError
+	if impact.Description == "" || len(impact.Description) > 255 {
+		errs = append(errs, rowError{Line: line, Field: prefix + "description", Message: "must be between 1 and 255 characters"})
+	}
+	if impact.Value < 1 || impact.Value > 10 {
+		errs = append(errs, rowError{Line: line, Field: prefix + "value", Message: "must be between 1 and 10"})
+	}
+	if impact.Category.ToInt16() < 0 {
+		errs = append(errs, rowError{Line: line, Field: prefix + "category", Message: "must be one of " + strings.Join(record.Categories.Names(), ", ")})
+	}
+	return errs
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"historylink/internal/features/record"
+)
+
+func validRow() importRow {
+	return importRow{
+		Kind:        RecordKind,
+		Title:       "Congress of Vienna",
+		Description: "Conference of ambassadors of European states",
+		Url:         "https://en.wikipedia.org/wiki/Congress_of_Vienna",
+		StartDate:   "1814-09-18",
+		EndDate:     "1815-06-09",
+		Type:        record.Event,
+		line:        4,
+	}
+}
+
+func TestValidateRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(row *importRow)
+		expected []string
+	}{
+		{name: "valid", change: func(row *importRow) {}},
+		{name: "pending", change: func(row *importRow) { row.RecordStatus = record.PendingReview }},
+		{name: "missing title", change: func(row *importRow) { row.Title = "" }, expected: []string{"title: is required"}},
+		{
+			name:     "title too long",
+			change:   func(row *importRow) { row.Title = strings.Repeat("a", 256) },
+			expected: []string{"title: must be at most 255 characters"},
+		},
+		{
+			name:     "malformed date",
+			change:   func(row *importRow) { row.StartDate = "18 September 1814" },
+			expected: []string{"startDate: must be a date formatted as YYYY-MM-DD"},
+		},
+		{
+			name:     "ends before it starts",
+			change:   func(row *importRow) { row.EndDate = "1814-01-01" },
+			expected: []string{"endDate: must not be before startDate"},
+		},
+		{
+			name:     "unknown type",
+			change:   func(row *importRow) { row.Type = "battle" },
+			expected: []string{"type: must be one of arc, event, person, object"},
+		},
+		{
+			name:     "reviewed",
+			change:   func(row *importRow) { row.RecordStatus = record.Reviewed },
+			expected: []string{"recordStatus: must not be reviewed before sources back the record"},
+		},
+		{
+			name: "invalid impact",
+			change: func(row *importRow) {
+				row.Impacts = []importImpactColumn{
+					{Description: "Redrew the map of Europe", Value: 9, Category: record.Political},
+					{Description: "", Value: 11, Category: "military"},
+				}
+			},
+			expected: []string{
+				"impacts[1].description: must be between 1 and 255 characters",
+				"impacts[1].value: must be between 1 and 10",
+				"impacts[1].category: must be one of political, social, economic, cultural, tech",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := validRow()
+			tt.change(&row)
+
+			var got []string
+			for _, err := range validateRecord(row) {
+				if err.Line != row.line {
+					t.Errorf("error on line %d, expected line %d", err.Line, row.line)
+				}
+				got = append(got, err.Field+": "+err.Message)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("validateRecord() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
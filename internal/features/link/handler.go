@@ -27,12 +27,13 @@ type LinkResources struct {
 }
 
 func (rs LinkResources) create(c context.Context, input *struct {
+	common.ActorInput
 	ID   uuid.UUID `path:"record_id"`
 	Body createLinkCommandBody
 }) (*struct {
 	Body linkResponseBody
 }, error) {
-	response, err := rs.LinkService.Create(c, input.Body, input.ID)
+	response, err := rs.LinkService.Create(input.WithActor(c), input.Body, input.ID)
 	if err != nil {
 		switch err {
 		case common.ErrLinkToItself:
@@ -92,10 +93,59 @@ func (rs LinkResources) getByRecordId(c context.Context, input *struct {
 	}, nil
 }
 
+func (rs LinkResources) getSuggestions(c context.Context, input *struct {
+	RecordId uuid.UUID `path:"record_id"`
+	Limit    int       `query:"limit" minimum:"1" maximum:"100" default:"10"`
+}) (*struct {
+	Body []suggestedLinkResponseBody
+}, error) {
+	suggestions, err := rs.LinkService.GetSuggestions(c, input.RecordId, input.Limit)
+	if err != nil {
+		if errors.Is(err, common.ErrRecordNotFound) {
+			return nil, huma.Error404NotFound(fmt.Sprintf("Record with id %v not found", input.RecordId.String()))
+		}
+		return nil, err
+	}
+
+	if suggestions == nil {
+		suggestions = []suggestedLinkResponseBody{}
+	}
+
+	return &struct {
+		Body []suggestedLinkResponseBody
+	}{
+		Body: suggestions,
+	}, nil
+}
+
+func (rs LinkResources) update(c context.Context, input *struct {
+	common.ActorInput
+	ID   uuid.UUID `path:"id"`
+	Body updateLinkCommandBody
+}) (*struct {
+	Body linkResponseBody
+}, error) {
+	response, err := rs.LinkService.Update(input.WithActor(c), input.ID, input.Body)
+	if err != nil {
+		if errors.Is(err, common.ErrLinkNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		rs.logger.Error(err.Error())
+		return nil, err
+	}
+
+	return &struct {
+		Body linkResponseBody
+	}{
+		Body: response,
+	}, nil
+}
+
 func (rs LinkResources) delete(c context.Context, input *struct {
+	common.ActorInput
 	ID uuid.UUID `path:"id"`
 }) (*struct{}, error) {
-	err := rs.LinkService.Delete(c, input.ID)
+	err := rs.LinkService.Delete(input.WithActor(c), input.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +190,26 @@ func (rs LinkResources) MountRoutes(s huma.API) {
 			},
 		},
 	}, rs.create)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-suggested-links",
+		Method:      http.MethodGet,
+		Path:        "/records/{record_id}/suggested-links",
+	}, rs.getSuggestions)
+	huma.Register(s, huma.Operation{
+		OperationID: "update-link",
+		Method:      http.MethodPut,
+		Path:        "/links/{id}",
+		Responses: map[string]*huma.Response{
+			"404": {
+				Description: "Link not found",
+				Content: map[string]*huma.MediaType{
+					"application/json": {
+						Schema: s.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(huma.ErrorModel{}), true, ""),
+					},
+				},
+			},
+		},
+	}, rs.update)
 	huma.Register(s, huma.Operation{
 		OperationID: "delete-link",
 		Method:      http.MethodDelete,
@@ -2,29 +2,93 @@ package link
 
 import (
 	"historylink/.gen/historylink/public/model"
+	"historylink/internal/features/record"
 
 	"github.com/google/uuid"
 )
 
 type createLinkCommandBody struct {
-	RecordID uuid.UUID `json:"recordId"`
-	Strength int16     `json:"strength"`
+	RecordID   uuid.UUID          `json:"recordId"`
+	Strength   int16              `json:"strength"`
+	Confidence *record.Confidence `json:"confidence,omitempty" enum:"low,medium,high"`
 }
 
 type updateLinkCommandBody struct {
 	Strength int16 `json:"strength"`
+	// Confidence is cleared when left out, like the confidence of records
+	Confidence *record.Confidence `json:"confidence,omitempty" enum:"low,medium,high"`
 }
 
 type linkResponseBody struct {
 	ID       uuid.UUID `json:"id"`
 	RecordID uuid.UUID `json:"recordId"`
 	Strength int16     `json:"strength"`
+	// Confidence in the connection, null when not assessed
+	Confidence *record.Confidence `json:"confidence"`
 }
 
 func mapLinkResponseBody(m model.Link, index int) linkResponseBody {
 	return linkResponseBody{
-		ID:       m.ID,
-		RecordID: m.RecordId2,
-		Strength: m.Strength,
+		ID:         m.ID,
+		RecordID:   m.RecordId2,
+		Strength:   m.Strength,
+		Confidence: record.ConfidenceFromNullInt16(m.Confidence),
 	}
 }
+
+type Signal string
+
+const (
+	CommonNeighbors  Signal = "common-neighbors"
+	Description      Signal = "description"
+	ImpactCategories Signal = "impact-categories"
+	Dates            Signal = "dates"
+	Location         Signal = "location"
+)
+
+// suggestionWeights is how much each signal adds to the score of a suggested
+// link when it matches fully. The weights add up to one, so scores are
+// between zero and one.
+var suggestionWeights = map[Signal]float64{
+	CommonNeighbors:  0.35,
+	Description:      0.25,
+	ImpactCategories: 0.15,
+	Dates:            0.15,
+	Location:         0.10,
+}
+
+const (
+	// commonNeighborsCap is the number of shared neighbors that counts as a
+	// full match, as a handful already shows two records belong together.
+	commonNeighborsCap = 3
+	// minSuggestionScore leaves out records that only match on a single
+	// weak signal, like happening in the same years.
+	minSuggestionScore = 0.2
+)
+
+// LinkSuggestion is a record that is not linked to the record suggestions
+// are made for, with how well each signal matches.
+type LinkSuggestion struct {
+	model.Record
+
+	Score                 float64
+	CommonNeighbors       int
+	DescriptionSimilarity float64
+	SharedCategories      int
+	DatesOverlap          bool
+	SameLocation          bool
+}
+
+type suggestedLinkResponseBody struct {
+	RecordID    uuid.UUID          `json:"recordId"`
+	Title       string             `json:"title"`
+	Type        record.Type        `json:"type"`
+	Score       float64            `json:"score"`
+	Explanation []suggestionReason `json:"explanation"`
+}
+
+type suggestionReason struct {
+	Signal       Signal  `json:"signal" enum:"common-neighbors,description,impact-categories,dates,location"`
+	Message      string  `json:"message"`
+	Contribution float64 `json:"contribution"`
+}
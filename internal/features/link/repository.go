@@ -9,9 +9,12 @@ import (
 	"historylink/.gen/historylink/public/model"
 	. "historylink/.gen/historylink/public/table"
 	"historylink/internal/common"
+	"historylink/internal/features/record"
+	"strings"
 
 	. "github.com/go-jet/jet/v2/postgres"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 )
 
 type ILinkRepository interface {
@@ -19,8 +22,10 @@ type ILinkRepository interface {
 	GetById(id uuid.UUID) (model.Link, error)
 	GetByRecordId(c context.Context, recordId uuid.UUID) ([]model.Link, error)
 	GetByRecordIds(c context.Context, recordId uuid.UUID, recordId2 uuid.UUID) (model.Link, error)
-	Update(c context.Context, id uuid.UUID, command model.Link) error
+	Update(c context.Context, id uuid.UUID, command model.Link) (model.Link, error)
 	Delete(c context.Context, id uuid.UUID) error
+	GetRecord(c context.Context, id uuid.UUID) (model.Record, []int16, error)
+	GetSuggestions(c context.Context, source model.Record, categories []int16, neighbors []uuid.UUID, limit int) ([]LinkSuggestion, error)
 }
 
 type LinkRepository struct {
@@ -50,15 +55,29 @@ func (r LinkRepository) Create(c context.Context, command model.Link) (model.Lin
 		return model.Link{}, common.ErrRecordNotFound
 	}
 
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return model.Link{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = common.SetActor(c, tx); err != nil {
+		return model.Link{}, err
+	}
+
 	stmt := Link.INSERT(Link.MutableColumns).
 		MODEL(command).
 		RETURNING(Link.AllColumns)
 
 	var dest model.Link
-	if err := stmt.Query(r.db, &dest); err != nil {
+	if err := stmt.QueryContext(c, tx, &dest); err != nil {
 		return model.Link{}, fmt.Errorf("failed to create link: %w", err)
 	}
 
+	if err = tx.Commit(); err != nil {
+		return model.Link{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return dest, nil
 }
 
@@ -110,25 +129,170 @@ func (r LinkRepository) GetByRecordIds(c context.Context, recordId uuid.UUID, re
 	return dest, nil
 }
 
-func (r LinkRepository) Update(c context.Context, id uuid.UUID, command model.Link) error {
-	stmt := Link.UPDATE(Link.Strength).
-		SET(command).
-		WHERE(Link.ID.EQ(UUID(id)))
+// Update sets the strength and confidence of a link, returning it as updated.
+func (r LinkRepository) Update(c context.Context, id uuid.UUID, command model.Link) (model.Link, error) {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return model.Link{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	if _, err := stmt.Exec(r.db); err != nil {
-		return fmt.Errorf("failed to update link: %w", err)
+	if err = common.SetActor(c, tx); err != nil {
+		return model.Link{}, err
 	}
 
-	return nil
+	stmt := Link.UPDATE(Link.Strength, Link.Confidence).
+		MODEL(command).
+		WHERE(Link.ID.EQ(UUID(id))).
+		RETURNING(Link.AllColumns)
+
+	var dest []model.Link
+	if err = stmt.QueryContext(c, tx, &dest); err != nil {
+		return model.Link{}, fmt.Errorf("failed to update link: %w", err)
+	}
+	if len(dest) == 0 {
+		return model.Link{}, common.ErrLinkNotFound
+	}
+
+	if err = tx.Commit(); err != nil {
+		return model.Link{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return dest[0], nil
 }
 
 func (r LinkRepository) Delete(c context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = common.SetActor(c, tx); err != nil {
+		return err
+	}
+
 	stmt := Link.DELETE().
 		WHERE(Link.ID.EQ(UUID(id)))
 
-	if _, err := stmt.Exec(r.db); err != nil {
+	if _, err := stmt.ExecContext(c, tx); err != nil {
 		return fmt.Errorf("failed to delete link: %w", err)
 	}
 
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
+
+// GetRecord returns a record together with the distinct categories of its
+// impacts.
+func (r LinkRepository) GetRecord(c context.Context, id uuid.UUID) (model.Record, []int16, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.EQ(UUID(id)))
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return model.Record{}, nil, fmt.Errorf("failed to get record: %w", err)
+	}
+	if len(records) == 0 {
+		return model.Record{}, nil, common.ErrRecordNotFound
+	}
+
+	impactStmt := SELECT(Impact.AllColumns).
+		FROM(Impact).
+		WHERE(Impact.RecordID.EQ(UUID(id)))
+
+	var impacts []model.Impact
+	if err := impactStmt.QueryContext(c, r.db, &impacts); err != nil {
+		return model.Record{}, nil, fmt.Errorf("failed to get impacts: %w", err)
+	}
+
+	return records[0], lo.Uniq(lo.Map(impacts, func(impact model.Impact, index int) int16 { return impact.Category })), nil
+}
+
+// GetSuggestions scores every record not yet linked to source on the signals
+// in suggestionWeights and returns the best scoring ones.
+func (r LinkRepository) GetSuggestions(c context.Context, source model.Record, categories []int16, neighbors []uuid.UUID, limit int) ([]LinkSuggestion, error) {
+	neighborIds := lo.Map(neighbors, func(id uuid.UUID, index int) Expression { return UUID(id) })
+
+	// Links from the candidate to one of the neighbors of source
+	commonNeighbors := IntegerExpression(Int32(0))
+	if len(neighborIds) > 0 {
+		commonNeighbors = IntExp(SELECT(COUNT(STAR)).
+			FROM(Link).
+			WHERE(Link.RecordID.EQ(Record.ID).AND(Link.RecordId2.IN(neighborIds...)).
+				OR(Link.RecordId2.EQ(Record.ID).AND(Link.RecordID.IN(neighborIds...)))))
+	}
+
+	descriptionSimilarity := FloatExp(Func("similarity", Record.Description, String(source.Description)))
+
+	// Each category of source the candidate has an impact in counts once
+	sharedCategories := IntegerExpression(Int32(0))
+	for _, category := range categories {
+		sharedCategories = sharedCategories.ADD(IntExp(CASE().
+			WHEN(EXISTS(SELECT(Impact.ID).
+				FROM(Impact).
+				WHERE(Impact.RecordID.EQ(Record.ID).AND(Impact.Category.EQ(Int16(category)))))).
+			THEN(Int32(1)).
+			ELSE(Int32(0))))
+	}
+
+	datesOverlap := BoolExpression(Bool(false))
+	if source.StartDate != nil && source.EndDate != nil {
+		datesOverlap = BoolExp(COALESCE(
+			Record.StartDate.LT_EQ(TimestampT(*source.EndDate)).AND(Record.EndDate.GT_EQ(TimestampT(*source.StartDate))),
+			Bool(false),
+		))
+	}
+
+	sameLocation := BoolExpression(Bool(false))
+	if source.Location != nil && strings.TrimSpace(*source.Location) != "" {
+		sameLocation = BoolExp(COALESCE(
+			LOWER(Record.Location).EQ(String(strings.ToLower(strings.TrimSpace(*source.Location)))),
+			Bool(false),
+		))
+	}
+
+	matched := func(condition BoolExpression) FloatExpression {
+		return FloatExp(CASE().WHEN(condition).THEN(Double(1)).ELSE(Double(0)))
+	}
+	score := Double(suggestionWeights[CommonNeighbors]).MUL(FloatExp(LEAST(FloatExp(commonNeighbors).DIV(Double(commonNeighborsCap)), Double(1)))).
+		ADD(Double(suggestionWeights[Description]).MUL(descriptionSimilarity)).
+		ADD(Double(suggestionWeights[Dates]).MUL(matched(datesOverlap))).
+		ADD(Double(suggestionWeights[Location]).MUL(matched(sameLocation)))
+	if len(categories) > 0 {
+		score = score.ADD(Double(suggestionWeights[ImpactCategories]).MUL(FloatExp(sharedCategories).DIV(Double(float64(len(categories))))))
+	}
+
+	excluded := append([]Expression{UUID(source.ID)}, neighborIds...)
+	stmt := SELECT(
+		Record.AllColumns,
+		score.AS("link_suggestion.score"),
+		commonNeighbors.AS("link_suggestion.common_neighbors"),
+		descriptionSimilarity.AS("link_suggestion.description_similarity"),
+		sharedCategories.AS("link_suggestion.shared_categories"),
+		datesOverlap.AS("link_suggestion.dates_overlap"),
+		sameLocation.AS("link_suggestion.same_location"),
+	).FROM(
+		Record,
+	).WHERE(
+		Record.ID.NOT_IN(excluded...).
+			AND(Record.Status.NOT_EQ(Int16(record.Removed.ToInt16()))),
+	).ORDER_BY(
+		// Sort on the selected score instead of computing it again
+		Raw(`"link_suggestion.score"`).DESC(),
+	).LIMIT(int64(limit))
+
+	var dest []LinkSuggestion
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("failed to get link suggestions: %w", err)
+	}
+
+	// Suggestions are sorted by score, so the weak ones are all at the end
+	return lo.Filter(dest, func(suggestion LinkSuggestion, index int) bool {
+		return suggestion.Score >= minSuggestionScore
+	}), nil
+}
@@ -2,10 +2,13 @@ package link
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"sort"
 
 	"historylink/.gen/historylink/public/model"
 	"historylink/internal/common"
+	"historylink/internal/features/record"
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
@@ -15,7 +18,9 @@ type ILinkService interface {
 	Create(c context.Context, command createLinkCommandBody, targetRecordId uuid.UUID) (linkResponseBody, error)
 	GetById(id uuid.UUID) (linkResponseBody, error)
 	GetByRecordId(c context.Context, recordId uuid.UUID) ([]linkResponseBody, error)
+	Update(c context.Context, id uuid.UUID, command updateLinkCommandBody) (linkResponseBody, error)
 	Delete(c context.Context, id uuid.UUID) error
+	GetSuggestions(c context.Context, recordId uuid.UUID, limit int) ([]suggestedLinkResponseBody, error)
 }
 
 type LinkService struct {
@@ -38,19 +43,16 @@ func (s LinkService) Create(c context.Context, command createLinkCommandBody, ta
 		return linkResponseBody{}, common.ErrLinkAlreadyExists
 	}
 	res, err := s.linkRepository.Create(c, model.Link{
-		RecordID:  targetRecordId,
-		RecordId2: command.RecordID,
-		Strength:  command.Strength,
+		RecordID:   targetRecordId,
+		RecordId2:  command.RecordID,
+		Strength:   command.Strength,
+		Confidence: record.ConfidenceToNullInt16(command.Confidence),
 	})
 	if err != nil {
 		return linkResponseBody{}, err
 	}
 
-	return linkResponseBody{
-		ID:       res.ID,
-		RecordID: res.RecordId2,
-		Strength: res.Strength,
-	}, nil
+	return mapLinkResponseBody(res, 0), nil
 }
 
 func (s LinkService) GetById(id uuid.UUID) (linkResponseBody, error) {
@@ -66,6 +68,85 @@ func (s LinkService) GetByRecordId(c context.Context, recordId uuid.UUID) ([]lin
 	return lo.Map(links, mapLinkResponseBody), nil
 }
 
+func (s LinkService) Update(c context.Context, id uuid.UUID, command updateLinkCommandBody) (linkResponseBody, error) {
+	res, err := s.linkRepository.Update(c, id, model.Link{
+		Strength:   command.Strength,
+		Confidence: record.ConfidenceToNullInt16(command.Confidence),
+	})
+	if err != nil {
+		return linkResponseBody{}, err
+	}
+
+	return mapLinkResponseBody(res, 0), nil
+}
+
 func (s LinkService) Delete(c context.Context, id uuid.UUID) error {
 	return s.linkRepository.Delete(c, id)
 }
+
+func (s LinkService) GetSuggestions(c context.Context, recordId uuid.UUID, limit int) ([]suggestedLinkResponseBody, error) {
+	source, categories, err := s.linkRepository.GetRecord(c, recordId)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.linkRepository.GetByRecordId(c, recordId)
+	if err != nil {
+		return nil, err
+	}
+	neighbors := lo.Map(links, func(link model.Link, index int) uuid.UUID {
+		if link.RecordID == recordId {
+			return link.RecordId2
+		}
+		return link.RecordID
+	})
+
+	suggestions, err := s.linkRepository.GetSuggestions(c, source, categories, lo.Uniq(neighbors), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(suggestions, func(suggestion LinkSuggestion, index int) suggestedLinkResponseBody {
+		return suggestedLinkResponseBody{
+			RecordID:    suggestion.ID,
+			Title:       suggestion.Title,
+			Type:        record.TypeFromInt16(suggestion.Type),
+			Score:       suggestion.Score,
+			Explanation: explain(suggestion, source, len(categories)),
+		}
+	}), nil
+}
+
+// explain lists the signals that contributed to the score of a suggestion,
+// largest contribution first.
+func explain(suggestion LinkSuggestion, source model.Record, categories int) []suggestionReason {
+	var reasons []suggestionReason
+	add := func(signal Signal, match float64, message string) {
+		if match <= 0 {
+			return
+		}
+		reasons = append(reasons, suggestionReason{
+			Signal:       signal,
+			Message:      message,
+			Contribution: suggestionWeights[signal] * match,
+		})
+	}
+
+	add(CommonNeighbors, min(float64(suggestion.CommonNeighbors)/commonNeighborsCap, 1),
+		fmt.Sprintf("Linked to %d of the records %q is linked to", suggestion.CommonNeighbors, source.Title))
+	add(Description, suggestion.DescriptionSimilarity,
+		fmt.Sprintf("Descriptions are %.0f%% similar", suggestion.DescriptionSimilarity*100))
+	if categories > 0 {
+		add(ImpactCategories, float64(suggestion.SharedCategories)/float64(categories),
+			fmt.Sprintf("Has impacts in %d of the %d impact categories of %q", suggestion.SharedCategories, categories, source.Title))
+	}
+	if suggestion.DatesOverlap {
+		add(Dates, 1, fmt.Sprintf("Overlaps in time, from %s to %s", common.ToDateString(suggestion.StartDate), common.ToDateString(suggestion.EndDate)))
+	}
+	if suggestion.SameLocation && suggestion.Location != nil {
+		add(Location, 1, fmt.Sprintf("Shares the location %s", *suggestion.Location))
+	}
+
+	sort.SliceStable(reasons, func(i, j int) bool { return reasons[i].Contribution > reasons[j].Contribution })
+	return reasons
+}
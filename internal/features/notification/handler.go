@@ -0,0 +1,175 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+func NewNotificationResources(conn *sql.DB, logger *slog.Logger) NotificationResources {
+	return NotificationResources{
+		logger:              logger,
+		NotificationService: NewNotificationService(NewRepository(conn, logger), logger),
+	}
+}
+
+type NotificationResources struct {
+	NotificationService INotificationService
+	logger              *slog.Logger
+}
+
+func (rs NotificationResources) getWatches(c context.Context, input *struct {
+	common.UserInput
+}) (*struct {
+	Body []watchResponseBody
+}, error) {
+	watches, err := rs.NotificationService.GetWatches(c, input.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return &struct {
+		Body []watchResponseBody
+	}{
+		Body: watches,
+	}, nil
+}
+
+func (rs NotificationResources) watch(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+}) (*struct {
+	Body watchResponseBody
+}, error) {
+	watch, err := rs.NotificationService.Watch(c, input.User, input.ID)
+	if err != nil {
+		return nil, notificationError(err)
+	}
+
+	return &struct {
+		Body watchResponseBody
+	}{
+		Body: watch,
+	}, nil
+}
+
+func (rs NotificationResources) unwatch(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+}) (*struct{}, error) {
+	if err := rs.NotificationService.Unwatch(c, input.User, input.ID); err != nil {
+		return nil, notificationError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs NotificationResources) getNotifications(c context.Context, input *struct {
+	common.UserInput
+	Unread   bool `query:"unread" doc:"Only list unread notifications"`
+	Page     int  `query:"page" minimum:"1" default:"1"`
+	PageSize int  `query:"pageSize" minimum:"1" maximum:"100" default:"20"`
+}) (*struct {
+	Body notificationFeedResponse
+}, error) {
+	feed, err := rs.NotificationService.GetNotifications(c, input.User, input.Unread, input.Page, input.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &struct {
+		Body notificationFeedResponse
+	}{
+		Body: feed,
+	}, nil
+}
+
+func (rs NotificationResources) markRead(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+}) (*struct{}, error) {
+	if err := rs.NotificationService.SetRead(c, input.User, input.ID, true); err != nil {
+		return nil, notificationError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs NotificationResources) markUnread(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+	common.UserInput
+}) (*struct{}, error) {
+	if err := rs.NotificationService.SetRead(c, input.User, input.ID, false); err != nil {
+		return nil, notificationError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs NotificationResources) markAllRead(c context.Context, input *struct {
+	common.UserInput
+}) (*struct{}, error) {
+	if err := rs.NotificationService.SetAllRead(c, input.User); err != nil {
+		return nil, err
+	}
+
+	return &struct{}{}, nil
+}
+
+func notificationError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound),
+		errors.Is(err, common.ErrWatchNotFound),
+		errors.Is(err, common.ErrNotificationNotFound):
+		return huma.Error404NotFound(err.Error())
+	default:
+		return err
+	}
+}
+
+func (rs NotificationResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "watch-record",
+		Method:      http.MethodPut,
+		Path:        "/records/{id}/watch",
+		Description: "Notifies the user when the record or arc changes, when a link from or to it changes, when a record joins or leaves the arc, and when it is submitted for review. A change notifies each watcher once per record, and never the user who made it when the request names them in X-User.",
+	}, rs.watch)
+	huma.Register(s, huma.Operation{
+		OperationID: "unwatch-record",
+		Method:      http.MethodDelete,
+		Path:        "/records/{id}/watch",
+	}, rs.unwatch)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-my-watches",
+		Method:      http.MethodGet,
+		Path:        "/me/watches",
+		Description: "Lists the records the user watches, most recently watched first.",
+	}, rs.getWatches)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-my-notifications",
+		Method:      http.MethodGet,
+		Path:        "/me/notifications",
+		Description: "Lists the notifications of the user newest first, with the number of them still unread.",
+	}, rs.getNotifications)
+	huma.Register(s, huma.Operation{
+		OperationID: "mark-all-notifications-read",
+		Method:      http.MethodPost,
+		Path:        "/me/notifications/read",
+	}, rs.markAllRead)
+	huma.Register(s, huma.Operation{
+		OperationID: "mark-notification-read",
+		Method:      http.MethodPost,
+		Path:        "/me/notifications/{id}/read",
+	}, rs.markRead)
+	huma.Register(s, huma.Operation{
+		OperationID: "mark-notification-unread",
+		Method:      http.MethodPost,
+		Path:        "/me/notifications/{id}/unread",
+	}, rs.markUnread)
+}
@@ -0,0 +1,161 @@
+package notification
+
+import (
+	"fmt"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+)
+
+// NotificationKind tells what happened to a watched record. A change that
+// does several of these at once notifies of the first of submitted, changed
+// and linked.
+type NotificationKind string
+
+const (
+	// Changed records had their fields or one of their impacts changed
+	Changed NotificationKind = "changed"
+	// Linked records had a link from or to them created, changed or
+	// removed, or, for arcs, a record joining or leaving them
+	Linked NotificationKind = "linked"
+	// Submitted records were made pending to be reviewed
+	Submitted NotificationKind = "submitted"
+	// Mentioned users were named in a comment, whether or not they watch
+	// what it is on
+	Mentioned NotificationKind = "mentioned"
+	// Commented records had a comment written on them or on one of their
+	// links
+	Commented NotificationKind = "commented"
+)
+
+func NotificationKindFromInt16(v int16) NotificationKind {
+	switch v {
+	case 0:
+		return Changed
+	case 1:
+		return Linked
+	case 2:
+		return Submitted
+	case 3:
+		return Mentioned
+	case 4:
+		return Commented
+	}
+	return ""
+}
+
+func (k NotificationKind) ToInt16() int16 {
+	switch k {
+	case Changed:
+		return 0
+	case Linked:
+		return 1
+	case Submitted:
+		return 2
+	case Mentioned:
+		return 3
+	case Commented:
+		return 4
+	}
+	return -1
+}
+
+// WatchEntity is a watch together with the record watched.
+type WatchEntity struct {
+	model.Watch
+	Record model.Record
+}
+
+// NotificationEntity is a notification together with the record it is
+// about, for links the record at the other end and for comments the
+// comment.
+type NotificationEntity struct {
+	model.Notification
+	Record        model.Record
+	RelatedRecord *model.Record `alias:"related_record.*"`
+	Comment       *model.Comment
+}
+
+type watchResponseBody struct {
+	RecordID  uuid.UUID   `json:"recordId"`
+	Title     string      `json:"title"`
+	Type      record.Type `json:"type"`
+	CreatedAt string      `json:"createdAt"`
+}
+
+type notificationResponseBody struct {
+	ID          uuid.UUID        `json:"id"`
+	Kind        NotificationKind `json:"kind"`
+	RecordID    uuid.UUID        `json:"recordId"`
+	RecordTitle string           `json:"recordTitle"`
+	// RelatedRecordID is the record at the other end of a changed link, or
+	// of the link commented on
+	RelatedRecordID    *uuid.UUID `json:"relatedRecordId"`
+	RelatedRecordTitle *string    `json:"relatedRecordTitle"`
+	// CommentID is the comment mentioning the user or written
+	CommentID *uuid.UUID `json:"commentId"`
+	Message   string     `json:"message"`
+	Read      bool       `json:"read"`
+	CreatedAt string     `json:"createdAt"`
+}
+
+type notificationFeedResponse struct {
+	Page  int `json:"page"`
+	Size  int `json:"size"`
+	Total int `json:"total"`
+	// Unread counts every unread notification of the user, on any page
+	Unread        int                        `json:"unread"`
+	Notifications []notificationResponseBody `json:"notifications"`
+}
+
+func mapWatchResponseBody(watch WatchEntity, index int) watchResponseBody {
+	return watchResponseBody{
+		RecordID:  watch.RecordID,
+		Title:     watch.Record.Title,
+		Type:      record.TypeFromInt16(watch.Record.Type),
+		CreatedAt: common.ToDateTimeString(&watch.CreatedAt),
+	}
+}
+
+func mapNotificationResponseBody(notification NotificationEntity, index int) notificationResponseBody {
+	kind := NotificationKindFromInt16(notification.Kind)
+	response := notificationResponseBody{
+		ID:              notification.ID,
+		Kind:            kind,
+		RecordID:        notification.RecordID,
+		RecordTitle:     notification.Record.Title,
+		RelatedRecordID: notification.RelatedRecordID,
+		CommentID:       notification.CommentID,
+		Read:            notification.ReadAt != nil,
+		CreatedAt:       common.ToDateTimeString(&notification.CreatedAt),
+	}
+	if notification.RelatedRecord != nil {
+		response.RelatedRecordTitle = &notification.RelatedRecord.Title
+	}
+
+	author := "Someone"
+	if notification.Comment != nil {
+		author = notification.Comment.Author
+	}
+
+	switch {
+	case kind == Mentioned:
+		response.Message = fmt.Sprintf("%s mentioned you on %q", author, response.RecordTitle)
+	case kind == Commented && response.RelatedRecordTitle != nil:
+		response.Message = fmt.Sprintf("%s commented on the link of %q to %q", author, response.RecordTitle, *response.RelatedRecordTitle)
+	case kind == Commented:
+		response.Message = fmt.Sprintf("%s commented on %q", author, response.RecordTitle)
+	case kind == Submitted:
+		response.Message = fmt.Sprintf("%q was submitted for review", response.RecordTitle)
+	case kind == Linked && response.RelatedRecordTitle != nil:
+		response.Message = fmt.Sprintf("The links of %q to %q changed", response.RecordTitle, *response.RelatedRecordTitle)
+	case kind == Linked:
+		response.Message = fmt.Sprintf("The links of %q changed", response.RecordTitle)
+	default:
+		response.Message = fmt.Sprintf("%q changed", response.RecordTitle)
+	}
+	return response
+}
@@ -0,0 +1,181 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+)
+
+type INotificationRepository interface {
+	GetRecord(c context.Context, id uuid.UUID) (model.Record, error)
+	GetWatches(c context.Context, user string) ([]WatchEntity, error)
+	Watch(c context.Context, user string, recordId uuid.UUID) error
+	Unwatch(c context.Context, user string, recordId uuid.UUID) error
+	GetNotifications(c context.Context, user string, unreadOnly bool, limit int, offset int) ([]NotificationEntity, int, int, error)
+	SetRead(c context.Context, user string, id uuid.UUID, read bool) error
+	SetAllRead(c context.Context, user string) (int64, error)
+}
+
+type NotificationRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) INotificationRepository {
+	return NotificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r NotificationRepository) GetRecord(c context.Context, id uuid.UUID) (model.Record, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.EQ(UUID(id)))
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return model.Record{}, fmt.Errorf("failed to get record: %w", err)
+	}
+	if len(records) == 0 {
+		return model.Record{}, common.ErrRecordNotFound
+	}
+	return records[0], nil
+}
+
+func (r NotificationRepository) GetWatches(c context.Context, user string) ([]WatchEntity, error) {
+	stmt := SELECT(Watch.AllColumns, Record.AllColumns).
+		FROM(Watch.INNER_JOIN(Record, Record.ID.EQ(Watch.RecordID))).
+		WHERE(Watch.UserName.EQ(String(user))).
+		ORDER_BY(Watch.CreatedAt.DESC(), Watch.RecordID)
+
+	var watches []WatchEntity
+	if err := stmt.QueryContext(c, r.db, &watches); err != nil {
+		return nil, fmt.Errorf("failed to get watches: %w", err)
+	}
+	return watches, nil
+}
+
+// Watch makes the user watch a record, doing nothing when they already do.
+func (r NotificationRepository) Watch(c context.Context, user string, recordId uuid.UUID) error {
+	stmt := Watch.INSERT(Watch.UserName, Watch.RecordID).
+		VALUES(user, recordId).
+		ON_CONFLICT(Watch.UserName, Watch.RecordID).
+		DO_NOTHING()
+
+	if _, err := stmt.ExecContext(c, r.db); err != nil {
+		return fmt.Errorf("failed to watch record: %w", err)
+	}
+	return nil
+}
+
+func (r NotificationRepository) Unwatch(c context.Context, user string, recordId uuid.UUID) error {
+	stmt := Watch.DELETE().
+		WHERE(Watch.UserName.EQ(String(user)).AND(Watch.RecordID.EQ(UUID(recordId))))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to unwatch record: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to unwatch record: %w", err)
+	} else if rows == 0 {
+		return common.ErrWatchNotFound
+	}
+	return nil
+}
+
+// GetNotifications lists the notifications of a user newest first, with the
+// number of them matching and the number of them unread.
+func (r NotificationRepository) GetNotifications(c context.Context, user string, unreadOnly bool, limit int, offset int) ([]NotificationEntity, int, int, error) {
+	condition := Notification.UserName.EQ(String(user))
+	if unreadOnly {
+		condition = condition.AND(Notification.ReadAt.IS_NULL())
+	}
+
+	var counts struct {
+		Total int
+		Read  int
+	}
+	countStmt := SELECT(
+		COUNT(STAR).AS("total"),
+		COUNT(Notification.ReadAt).AS("read"),
+	).
+		FROM(Notification).
+		WHERE(Notification.UserName.EQ(String(user)))
+	if err := countStmt.QueryContext(c, r.db, &counts); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	unread := counts.Total - counts.Read
+	total := counts.Total
+	if unreadOnly {
+		total = unread
+	}
+
+	relatedRecord := Record.AS("related_record")
+	stmt := SELECT(Notification.AllColumns, Record.AllColumns, relatedRecord.AllColumns, Comment.AllColumns).
+		FROM(
+			Notification.
+				INNER_JOIN(Record, Record.ID.EQ(Notification.RecordID)).
+				LEFT_JOIN(relatedRecord, relatedRecord.ID.EQ(Notification.RelatedRecordID)).
+				LEFT_JOIN(Comment, Comment.ID.EQ(Notification.CommentID)),
+		).
+		WHERE(condition).
+		ORDER_BY(Notification.CreatedAt.DESC(), Notification.ID).
+		LIMIT(int64(limit)).
+		OFFSET(int64(offset))
+
+	var notifications []NotificationEntity
+	if err := stmt.QueryContext(c, r.db, &notifications); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	return notifications, total, unread, nil
+}
+
+func (r NotificationRepository) SetRead(c context.Context, user string, id uuid.UUID, read bool) error {
+	var readAt Expression = NULL
+	if read {
+		// Notifications already read keep the time they were first read
+		readAt = COALESCE(Notification.ReadAt, LOCALTIMESTAMP())
+	}
+	stmt := Notification.UPDATE(Notification.ReadAt).
+		SET(readAt).
+		WHERE(Notification.ID.EQ(UUID(id)).AND(Notification.UserName.EQ(String(user))))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to mark notification: %w", err)
+	} else if rows == 0 {
+		return common.ErrNotificationNotFound
+	}
+	return nil
+}
+
+// SetAllRead marks every unread notification of the user read, returning
+// how many there were.
+func (r NotificationRepository) SetAllRead(c context.Context, user string) (int64, error) {
+	stmt := Notification.UPDATE().
+		SET(Notification.ReadAt.SET(LOCALTIMESTAMP())).
+		WHERE(Notification.UserName.EQ(String(user)).AND(Notification.ReadAt.IS_NULL()))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications: %w", err)
+	}
+	return rows, nil
+}
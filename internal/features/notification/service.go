@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type INotificationService interface {
+	GetWatches(c context.Context, user string) ([]watchResponseBody, error)
+	Watch(c context.Context, user string, recordId uuid.UUID) (watchResponseBody, error)
+	Unwatch(c context.Context, user string, recordId uuid.UUID) error
+	GetNotifications(c context.Context, user string, unreadOnly bool, page int, size int) (notificationFeedResponse, error)
+	SetRead(c context.Context, user string, id uuid.UUID, read bool) error
+	SetAllRead(c context.Context, user string) error
+}
+
+type NotificationService struct {
+	notificationRepository INotificationRepository
+	logger                 *slog.Logger
+}
+
+func NewNotificationService(notificationRepository INotificationRepository, logger *slog.Logger) INotificationService {
+	return NotificationService{
+		notificationRepository: notificationRepository,
+		logger:                 logger,
+	}
+}
+
+func (s NotificationService) GetWatches(c context.Context, user string) ([]watchResponseBody, error) {
+	watches, err := s.notificationRepository.GetWatches(c, user)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(watches, mapWatchResponseBody), nil
+}
+
+// Watch makes the user watch a record, which may be an arc, so they are
+// notified when it changes.
+func (s NotificationService) Watch(c context.Context, user string, recordId uuid.UUID) (watchResponseBody, error) {
+	if _, err := s.notificationRepository.GetRecord(c, recordId); err != nil {
+		return watchResponseBody{}, err
+	}
+	if err := s.notificationRepository.Watch(c, user, recordId); err != nil {
+		return watchResponseBody{}, err
+	}
+
+	watches, err := s.notificationRepository.GetWatches(c, user)
+	if err != nil {
+		return watchResponseBody{}, err
+	}
+	watch, _ := lo.Find(watches, func(watch WatchEntity) bool {
+		return watch.RecordID == recordId
+	})
+	return mapWatchResponseBody(watch, 0), nil
+}
+
+func (s NotificationService) Unwatch(c context.Context, user string, recordId uuid.UUID) error {
+	return s.notificationRepository.Unwatch(c, user, recordId)
+}
+
+func (s NotificationService) GetNotifications(c context.Context, user string, unreadOnly bool, page int, size int) (notificationFeedResponse, error) {
+	notifications, total, unread, err := s.notificationRepository.GetNotifications(c, user, unreadOnly, size, (page-1)*size)
+	if err != nil {
+		return notificationFeedResponse{}, err
+	}
+	return notificationFeedResponse{
+		Page:          page,
+		Size:          size,
+		Total:         total,
+		Unread:        unread,
+		Notifications: lo.Map(notifications, mapNotificationResponseBody),
+	}, nil
+}
+
+func (s NotificationService) SetRead(c context.Context, user string, id uuid.UUID, read bool) error {
+	return s.notificationRepository.SetRead(c, user, id, read)
+}
+
+func (s NotificationService) SetAllRead(c context.Context, user string) error {
+	_, err := s.notificationRepository.SetAllRead(c, user)
+	return err
+}
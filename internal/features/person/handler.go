@@ -0,0 +1,130 @@
+package person
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+func NewPersonResources(conn *sql.DB, logger *slog.Logger) PersonResources {
+	return PersonResources{
+		logger:        logger,
+		PersonService: NewPersonService(NewRepository(conn, logger), logger),
+	}
+}
+
+type PersonResources struct {
+	PersonService IPersonService
+	logger        *slog.Logger
+}
+
+func (rs PersonResources) getById(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body personResponseBody
+}, error) {
+	person, err := rs.PersonService.GetById(c, input.ID)
+	if err != nil {
+		return nil, personError(input.ID, err)
+	}
+
+	return &struct {
+		Body personResponseBody
+	}{
+		Body: person,
+	}, nil
+}
+
+func (rs PersonResources) update(c context.Context, input *struct {
+	ID   uuid.UUID `path:"id"`
+	Body updatePersonCommandBody
+}) (*struct {
+	Body personResponseBody
+}, error) {
+	person, err := rs.PersonService.Update(c, input.ID, input.Body)
+	if err != nil {
+		return nil, personError(input.ID, err)
+	}
+
+	return &struct {
+		Body personResponseBody
+	}{
+		Body: person,
+	}, nil
+}
+
+func (rs PersonResources) createInvolvement(c context.Context, input *struct {
+	ID   uuid.UUID `path:"id"`
+	Body createInvolvementCommandBody
+}) (*struct {
+	Body involvementResponseBody
+}, error) {
+	involvement, err := rs.PersonService.CreateInvolvement(c, input.ID, input.Body)
+	if err != nil {
+		return nil, personError(input.ID, err)
+	}
+
+	return &struct {
+		Body involvementResponseBody
+	}{
+		Body: involvement,
+	}, nil
+}
+
+func (rs PersonResources) deleteInvolvement(c context.Context, input *struct {
+	ID            uuid.UUID `path:"id"`
+	InvolvementID uuid.UUID `path:"involvementId"`
+}) (*struct{}, error) {
+	err := rs.PersonService.DeleteInvolvement(c, input.ID, input.InvolvementID)
+	if err != nil {
+		return nil, personError(input.ID, err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func personError(id uuid.UUID, err error) error {
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound), errors.Is(err, common.ErrNotAPerson):
+		return huma.Error404NotFound(fmt.Sprintf("Person with id %v not found", id.String()))
+	case errors.Is(err, common.ErrInvolvementNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, common.ErrInvolvementInPerson), errors.Is(err, common.ErrInvalidDateRange):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, common.ErrInvolvementAlreadyExists):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return err
+	}
+}
+
+func (rs PersonResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "get-person",
+		Method:      http.MethodGet,
+		Path:        "/persons/{id}",
+		Description: "Person with their attributes and a timeline of the events, objects and arcs they were involved in.",
+	}, rs.getById)
+	huma.Register(s, huma.Operation{
+		OperationID: "update-person",
+		Method:      http.MethodPut,
+		Path:        "/persons/{id}",
+	}, rs.update)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-involvement",
+		Method:      http.MethodPost,
+		Path:        "/persons/{id}/involvements",
+	}, rs.createInvolvement)
+	huma.Register(s, huma.Operation{
+		OperationID: "delete-involvement",
+		Method:      http.MethodDelete,
+		Path:        "/persons/{id}/involvements/{involvementId}",
+	}, rs.deleteInvolvement)
+}
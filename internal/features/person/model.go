@@ -0,0 +1,110 @@
+package person
+
+import (
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type updatePersonCommandBody struct {
+	BirthDate        string   `json:"birthDate,omitempty" format:"date"`
+	BirthPlace       *string  `json:"birthPlace,omitempty" maxLength:"255"`
+	DeathDate        string   `json:"deathDate,omitempty" format:"date"`
+	DeathPlace       *string  `json:"deathPlace,omitempty" maxLength:"255"`
+	AlternativeNames []string `json:"alternativeNames,omitempty"`
+	Occupations      []string `json:"occupations,omitempty"`
+}
+
+type createInvolvementCommandBody struct {
+	RecordID  uuid.UUID `json:"recordId" doc:"Event, object or arc the person was involved in"`
+	Role      Role      `json:"role" enum:"leader,victim,inventor,author,participant"`
+	StartDate string    `json:"startDate,omitempty" format:"date"`
+	EndDate   string    `json:"endDate,omitempty" format:"date"`
+}
+
+type personResponseBody struct {
+	ID               uuid.UUID           `json:"id"`
+	Title            string              `json:"title"`
+	Description      string              `json:"description"`
+	RecordStatus     record.RecordStatus `json:"recordStatus"`
+	BirthDate        string              `json:"birthDate"`
+	BirthPlace       *string             `json:"birthPlace"`
+	DeathDate        string              `json:"deathDate"`
+	DeathPlace       *string             `json:"deathPlace"`
+	AlternativeNames []string            `json:"alternativeNames"`
+	Occupations      []string            `json:"occupations"`
+	// Timeline lists the involvements of the person in chronological order,
+	// with undated ones last
+	Timeline []involvementResponseBody `json:"timeline"`
+}
+
+type involvementResponseBody struct {
+	ID       uuid.UUID   `json:"id"`
+	RecordID uuid.UUID   `json:"recordId"`
+	Title    string      `json:"title"`
+	Type     record.Type `json:"type"`
+	Role     Role        `json:"role"`
+	// StartDate and EndDate are those of the involvement, or of the record
+	// when the involvement has none of its own
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+// PersonAggregate is a person record with its attributes, which are missing
+// until they are first set.
+type PersonAggregate struct {
+	model.Record
+
+	Profile     *model.PersonProfile
+	Names       []model.PersonName
+	Occupations []model.PersonOccupation
+}
+
+// InvolvementEntity is an involvement joined with the record the person was
+// involved in.
+type InvolvementEntity struct {
+	model.Involvement
+
+	Record model.Record
+}
+
+func (p PersonAggregate) toResponse(involvements []InvolvementEntity) personResponseBody {
+	response := personResponseBody{
+		ID:               p.ID,
+		Title:            p.Title,
+		Description:      p.Description,
+		RecordStatus:     record.RecordStatusFromInt16(p.Status),
+		BirthDate:        common.ToDateString(nil),
+		DeathDate:        common.ToDateString(nil),
+		AlternativeNames: lo.Map(p.Names, func(name model.PersonName, index int) string { return name.Name }),
+		Occupations: lo.Map(p.Occupations, func(occupation model.PersonOccupation, index int) string {
+			return occupation.Occupation
+		}),
+		Timeline: lo.Map(involvements, func(involvement InvolvementEntity, index int) involvementResponseBody {
+			return involvement.toResponse()
+		}),
+	}
+	if p.Profile != nil {
+		response.BirthDate = common.ToDateString(p.Profile.BirthDate)
+		response.BirthPlace = p.Profile.BirthPlace
+		response.DeathDate = common.ToDateString(p.Profile.DeathDate)
+		response.DeathPlace = p.Profile.DeathPlace
+	}
+	return response
+}
+
+func (i InvolvementEntity) toResponse() involvementResponseBody {
+	start, end := i.dates()
+	return involvementResponseBody{
+		ID:        i.ID,
+		RecordID:  i.RecordID,
+		Title:     i.Record.Title,
+		Type:      record.TypeFromInt16(i.Record.Type),
+		Role:      RoleFromInt16(i.Role),
+		StartDate: common.ToDateString(start),
+		EndDate:   common.ToDateString(end),
+	}
+}
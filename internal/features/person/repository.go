@@ -0,0 +1,184 @@
+package person
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type IPersonRepository interface {
+	GetRecord(c context.Context, id uuid.UUID) (model.Record, error)
+	GetPerson(c context.Context, id uuid.UUID) (PersonAggregate, error)
+	Save(c context.Context, person PersonAggregate) error
+	GetInvolvements(c context.Context, personId uuid.UUID) ([]InvolvementEntity, error)
+	CreateInvolvement(c context.Context, involvement model.Involvement) (model.Involvement, error)
+	DeleteInvolvement(c context.Context, personId uuid.UUID, id uuid.UUID) error
+}
+
+type PersonRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) IPersonRepository {
+	return PersonRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r PersonRepository) GetRecord(c context.Context, id uuid.UUID) (model.Record, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.EQ(UUID(id)))
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return model.Record{}, fmt.Errorf("failed to get record: %w", err)
+	}
+	if len(records) == 0 {
+		return model.Record{}, common.ErrRecordNotFound
+	}
+	return records[0], nil
+}
+
+func (r PersonRepository) GetPerson(c context.Context, id uuid.UUID) (PersonAggregate, error) {
+	stmt := SELECT(
+		Record.AllColumns,
+		PersonProfile.AllColumns,
+		PersonName.AllColumns,
+		PersonOccupation.AllColumns,
+	).FROM(
+		Record.
+			LEFT_JOIN(PersonProfile, PersonProfile.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(PersonName, PersonName.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(PersonOccupation, PersonOccupation.RecordID.EQ(Record.ID)),
+	).WHERE(
+		Record.ID.EQ(UUID(id)),
+	).ORDER_BY(PersonName.Name, PersonOccupation.Occupation)
+
+	var dest []PersonAggregate
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return PersonAggregate{}, fmt.Errorf("failed to get person: %w", err)
+	}
+	if len(dest) == 0 {
+		return PersonAggregate{}, common.ErrRecordNotFound
+	}
+	return dest[0], nil
+}
+
+// Save sets the attributes of a person, replacing its alternative names and
+// occupations.
+func (r PersonRepository) Save(c context.Context, person PersonAggregate) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id := person.Profile.RecordID
+	personStmt := PersonProfile.INSERT(PersonProfile.AllColumns).
+		MODEL(person.Profile).
+		ON_CONFLICT(PersonProfile.RecordID).
+		DO_UPDATE(SET(
+			PersonProfile.BirthDate.SET(PersonProfile.EXCLUDED.BirthDate),
+			PersonProfile.BirthPlace.SET(PersonProfile.EXCLUDED.BirthPlace),
+			PersonProfile.DeathDate.SET(PersonProfile.EXCLUDED.DeathDate),
+			PersonProfile.DeathPlace.SET(PersonProfile.EXCLUDED.DeathPlace),
+		))
+	if _, err = personStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to save person: %w", err)
+	}
+
+	if _, err = PersonName.DELETE().WHERE(PersonName.RecordID.EQ(UUID(id))).ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to delete alternative names: %w", err)
+	}
+	if len(person.Names) > 0 {
+		nameStmt := PersonName.INSERT(PersonName.RecordID, PersonName.Name).
+			MODELS(person.Names)
+		if _, err = nameStmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("failed to insert alternative names: %w", err)
+		}
+	}
+
+	if _, err = PersonOccupation.DELETE().WHERE(PersonOccupation.RecordID.EQ(UUID(id))).ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to delete occupations: %w", err)
+	}
+	if len(person.Occupations) > 0 {
+		occupationStmt := PersonOccupation.INSERT(PersonOccupation.RecordID, PersonOccupation.Occupation).
+			MODELS(person.Occupations)
+		if _, err = occupationStmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("failed to insert occupations: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetInvolvements returns the involvements of a person, leaving out those in
+// removed records.
+func (r PersonRepository) GetInvolvements(c context.Context, personId uuid.UUID) ([]InvolvementEntity, error) {
+	stmt := SELECT(
+		Involvement.AllColumns,
+		Record.AllColumns,
+	).FROM(
+		Involvement.INNER_JOIN(Record, Record.ID.EQ(Involvement.RecordID)),
+	).WHERE(
+		Involvement.PersonID.EQ(UUID(personId)).
+			AND(Record.Status.NOT_EQ(Int16(record.Removed.ToInt16()))),
+	)
+
+	var dest []InvolvementEntity
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("failed to get involvements: %w", err)
+	}
+	return dest, nil
+}
+
+func (r PersonRepository) CreateInvolvement(c context.Context, involvement model.Involvement) (model.Involvement, error) {
+	stmt := Involvement.INSERT(Involvement.MutableColumns).
+		MODEL(involvement).
+		RETURNING(Involvement.AllColumns)
+
+	var dest model.Involvement
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.Involvement{}, common.ErrInvolvementAlreadyExists
+		}
+		return model.Involvement{}, fmt.Errorf("failed to create involvement: %w", err)
+	}
+	return dest, nil
+}
+
+func (r PersonRepository) DeleteInvolvement(c context.Context, personId uuid.UUID, id uuid.UUID) error {
+	stmt := Involvement.DELETE().
+		WHERE(Involvement.ID.EQ(UUID(id)).AND(Involvement.PersonID.EQ(UUID(personId))))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to delete involvement: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete involvement: %w", err)
+	}
+	if deleted == 0 {
+		return common.ErrInvolvementNotFound
+	}
+	return nil
+}
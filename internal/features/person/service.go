@@ -0,0 +1,206 @@
+package person
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type IPersonService interface {
+	GetById(c context.Context, id uuid.UUID) (personResponseBody, error)
+	Update(c context.Context, id uuid.UUID, command updatePersonCommandBody) (personResponseBody, error)
+	CreateInvolvement(c context.Context, personId uuid.UUID, command createInvolvementCommandBody) (involvementResponseBody, error)
+	DeleteInvolvement(c context.Context, personId uuid.UUID, id uuid.UUID) error
+}
+
+type PersonService struct {
+	personRepository IPersonRepository
+	logger           *slog.Logger
+}
+
+func NewPersonService(personRepository IPersonRepository, logger *slog.Logger) IPersonService {
+	return PersonService{
+		personRepository: personRepository,
+		logger:           logger,
+	}
+}
+
+type Role string
+
+const (
+	Leader      Role = "leader"
+	Victim      Role = "victim"
+	Inventor    Role = "inventor"
+	Author      Role = "author"
+	Participant Role = "participant"
+)
+
+func RoleFromInt16(v int16) Role {
+	switch v {
+	case 0:
+		return Leader
+	case 1:
+		return Victim
+	case 2:
+		return Inventor
+	case 3:
+		return Author
+	case 4:
+		return Participant
+	}
+	return ""
+}
+
+func (r Role) ToInt16() int16 {
+	switch r {
+	case Leader:
+		return 0
+	case Victim:
+		return 1
+	case Inventor:
+		return 2
+	case Author:
+		return 3
+	case Participant:
+		return 4
+	}
+	return -1
+}
+
+func (s PersonService) GetById(c context.Context, id uuid.UUID) (personResponseBody, error) {
+	person, err := s.personRepository.GetPerson(c, id)
+	if err != nil {
+		return personResponseBody{}, err
+	}
+	if record.TypeFromInt16(person.Type) != record.Person {
+		return personResponseBody{}, common.ErrNotAPerson
+	}
+
+	involvements, err := s.personRepository.GetInvolvements(c, id)
+	if err != nil {
+		return personResponseBody{}, err
+	}
+	sortTimeline(involvements)
+
+	return person.toResponse(involvements), nil
+}
+
+func (s PersonService) Update(c context.Context, id uuid.UUID, command updatePersonCommandBody) (personResponseBody, error) {
+	person, err := s.personRepository.GetRecord(c, id)
+	if err != nil {
+		return personResponseBody{}, err
+	}
+	if record.TypeFromInt16(person.Type) != record.Person {
+		return personResponseBody{}, common.ErrNotAPerson
+	}
+
+	birth, death := common.ToTime(command.BirthDate), common.ToTime(command.DeathDate)
+	if birth != nil && death != nil && death.Before(*birth) {
+		return personResponseBody{}, common.ErrInvalidDateRange
+	}
+
+	err = s.personRepository.Save(c, PersonAggregate{
+		Profile: &model.PersonProfile{
+			RecordID:   id,
+			BirthDate:  birth,
+			BirthPlace: command.BirthPlace,
+			DeathDate:  death,
+			DeathPlace: command.DeathPlace,
+		},
+		Names: lo.Map(cleanValues(command.AlternativeNames), func(name string, index int) model.PersonName {
+			return model.PersonName{RecordID: id, Name: name}
+		}),
+		Occupations: lo.Map(cleanValues(command.Occupations), func(occupation string, index int) model.PersonOccupation {
+			return model.PersonOccupation{RecordID: id, Occupation: occupation}
+		}),
+	})
+	if err != nil {
+		return personResponseBody{}, err
+	}
+
+	return s.GetById(c, id)
+}
+
+func (s PersonService) CreateInvolvement(c context.Context, personId uuid.UUID, command createInvolvementCommandBody) (involvementResponseBody, error) {
+	person, err := s.personRepository.GetRecord(c, personId)
+	if err != nil {
+		return involvementResponseBody{}, err
+	}
+	if record.TypeFromInt16(person.Type) != record.Person {
+		return involvementResponseBody{}, common.ErrNotAPerson
+	}
+
+	target, err := s.personRepository.GetRecord(c, command.RecordID)
+	if err != nil {
+		return involvementResponseBody{}, err
+	}
+	if record.TypeFromInt16(target.Type) == record.Person {
+		return involvementResponseBody{}, common.ErrInvolvementInPerson
+	}
+
+	start, end := common.ToTime(command.StartDate), common.ToTime(command.EndDate)
+	if start != nil && end != nil && end.Before(*start) {
+		return involvementResponseBody{}, common.ErrInvalidDateRange
+	}
+
+	involvement, err := s.personRepository.CreateInvolvement(c, model.Involvement{
+		PersonID:  personId,
+		RecordID:  command.RecordID,
+		Role:      command.Role.ToInt16(),
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		return involvementResponseBody{}, err
+	}
+
+	return InvolvementEntity{Involvement: involvement, Record: target}.toResponse(), nil
+}
+
+func (s PersonService) DeleteInvolvement(c context.Context, personId uuid.UUID, id uuid.UUID) error {
+	return s.personRepository.DeleteInvolvement(c, personId, id)
+}
+
+// dates returns the dates of the involvement, taking those of the record the
+// person was involved in when the involvement has none of its own.
+func (i InvolvementEntity) dates() (*time.Time, *time.Time) {
+	if i.StartDate != nil || i.EndDate != nil {
+		return i.StartDate, i.EndDate
+	}
+	return i.Record.StartDate, i.Record.EndDate
+}
+
+// sortTimeline orders involvements by when they started, putting undated
+// ones last in order of title.
+func sortTimeline(involvements []InvolvementEntity) {
+	sort.SliceStable(involvements, func(i, j int) bool {
+		a, _ := involvements[i].dates()
+		b, _ := involvements[j].dates()
+		switch {
+		case a == nil && b == nil:
+			return involvements[i].Record.Title < involvements[j].Record.Title
+		case a == nil || b == nil:
+			return b == nil
+		default:
+			return a.Before(*b)
+		}
+	})
+}
+
+// cleanValues trims the given names or occupations and drops empty and
+// repeated ones.
+func cleanValues(values []string) []string {
+	return lo.Uniq(lo.FilterMap(values, func(value string, index int) (string, bool) {
+		value = strings.TrimSpace(value)
+		return value, value != ""
+	}))
+}
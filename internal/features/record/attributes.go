@@ -0,0 +1,149 @@
+package record
+
+import (
+	"encoding/json"
+	"fmt"
+	"historylink/internal/common"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	. "github.com/go-jet/jet/v2/postgres"
+)
+
+// ParseAttributeSchema reads the JSON Schema describing the attributes of a
+// record type. It supports the subset of JSON Schema that huma validates
+// request bodies with; references to other schemas are not supported.
+func ParseAttributeSchema(raw []byte) (*huma.Schema, error) {
+	var schema huma.Schema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInvalidAttributeSchema, err)
+	}
+	if schema.Type != huma.TypeObject {
+		return nil, fmt.Errorf("%w: attributes must be described as an object", common.ErrInvalidAttributeSchema)
+	}
+	if err := prepareSchema(&schema); err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInvalidAttributeSchema, err)
+	}
+	schema.PrecomputeMessages()
+	return &schema, nil
+}
+
+// prepareSchema checks the parts of a schema huma would otherwise panic on
+// and decodes nested additionalProperties schemas, which JSON leaves as maps.
+func prepareSchema(s *huma.Schema) error {
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q", s.Pattern)
+		}
+	}
+
+	switch additional := s.AdditionalProperties.(type) {
+	case nil, bool:
+	case map[string]any:
+		raw, err := json.Marshal(additional)
+		if err != nil {
+			return err
+		}
+		var sub huma.Schema
+		if err = json.Unmarshal(raw, &sub); err != nil {
+			return err
+		}
+		if err = prepareSchema(&sub); err != nil {
+			return err
+		}
+		sub.PrecomputeMessages()
+		s.AdditionalProperties = &sub
+	default:
+		return fmt.Errorf("additionalProperties must be a boolean or a schema")
+	}
+
+	subs := []*huma.Schema{s.Items, s.Not}
+	for _, property := range s.Properties {
+		subs = append(subs, property)
+	}
+	subs = append(subs, s.OneOf...)
+	subs = append(subs, s.AnyOf...)
+	subs = append(subs, s.AllOf...)
+	for _, sub := range subs {
+		if sub == nil {
+			continue
+		}
+		if err := prepareSchema(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateAttributes checks attributes against the schema of the type of the
+// record. Without a schema a type has no attributes.
+func validateAttributes(recordType Type, schema *huma.Schema, attributes map[string]any) error {
+	if schema == nil {
+		if len(attributes) > 0 {
+			return fmt.Errorf("%w: records of type %s have no attributes", common.ErrInvalidAttributes, recordType)
+		}
+		return nil
+	}
+
+	registry := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	result := &huma.ValidateResult{}
+	path := huma.NewPathBuffer([]byte{}, 0)
+	path.Push("attributes")
+	huma.Validate(registry, schema, path, huma.ModeWriteToServer, attributes, result)
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, err := range result.Errors {
+			messages[i] = err.Error()
+		}
+		return fmt.Errorf("%w: %s", common.ErrInvalidAttributes, strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// AttributeFilter matches records by the value of one of their attributes.
+type AttributeFilter struct {
+	Name     string
+	Operator string
+	Value    string
+}
+
+var attributeFilterPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+)(>=|<=|=|>|<)(.*)$`)
+
+// ParseAttributeFilter reads a filter written as name=value, or with >, >=,
+// < or <= to compare numbers.
+func ParseAttributeFilter(filter string) (AttributeFilter, error) {
+	match := attributeFilterPattern.FindStringSubmatch(filter)
+	if match == nil {
+		return AttributeFilter{}, fmt.Errorf("%w: %q is not written as name=value", common.ErrInvalidAttributeFilter, filter)
+	}
+
+	f := AttributeFilter{Name: match[1], Operator: match[2], Value: match[3]}
+	if f.Operator != "=" {
+		if _, err := strconv.ParseFloat(f.Value, 64); err != nil {
+			return AttributeFilter{}, fmt.Errorf("%w: %q can only be compared with a number", common.ErrInvalidAttributeFilter, f.Name)
+		}
+	}
+	return f, nil
+}
+
+func (f AttributeFilter) condition() BoolExpression {
+	if f.Operator == "=" {
+		// Values that read as JSON, such as numbers and booleans, match as
+		// such and anything else matches as a string
+		var value any = f.Value
+		var parsed any
+		if err := json.Unmarshal([]byte(f.Value), &parsed); err == nil {
+			value = parsed
+		}
+		contained, _ := json.Marshal(map[string]any{f.Name: value})
+		return RawBool("record.attributes @> #contained::jsonb", RawArgs{"#contained": string(contained)})
+	}
+
+	number, _ := strconv.ParseFloat(f.Value, 64)
+	return RawBool(
+		"CASE WHEN jsonb_typeof(record.attributes -> #name) = 'number' THEN (record.attributes ->> #name)::numeric "+f.Operator+" #value ELSE false END",
+		RawArgs{"#name": f.Name, "#value": number},
+	)
+}
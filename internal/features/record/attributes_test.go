@@ -0,0 +1,124 @@
+package record
+
+import (
+	"errors"
+	"testing"
+
+	"historylink/internal/common"
+)
+
+func TestParseAttributeFilter(t *testing.T) {
+	tests := []struct {
+		filter   string
+		expected AttributeFilter
+		valid    bool
+	}{
+		{filter: "regnal_name=Louis XIV", expected: AttributeFilter{Name: "regnal_name", Operator: "=", Value: "Louis XIV"}, valid: true},
+		{filter: "casualties>=10000", expected: AttributeFilter{Name: "casualties", Operator: ">=", Value: "10000"}, valid: true},
+		{filter: "casualties<=1e4", expected: AttributeFilter{Name: "casualties", Operator: "<=", Value: "1e4"}, valid: true},
+		{filter: "height>1.8", expected: AttributeFilter{Name: "height", Operator: ">", Value: "1.8"}, valid: true},
+		{filter: "height<-2", expected: AttributeFilter{Name: "height", Operator: "<", Value: "-2"}, valid: true},
+		{filter: "motto=a=b", expected: AttributeFilter{Name: "motto", Operator: "=", Value: "a=b"}, valid: true},
+		{filter: "victor=", expected: AttributeFilter{Name: "victor", Operator: "=", Value: ""}, valid: true},
+		{filter: "victor", valid: false},
+		{filter: "=Prussia", valid: false},
+		{filter: "victor's=Prussia", valid: false},
+		{filter: "casualties>many", valid: false},
+		{filter: "casualties>", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			got, err := ParseAttributeFilter(tt.filter)
+			if !tt.valid {
+				if !errors.Is(err, common.ErrInvalidAttributeFilter) {
+					t.Fatalf("ParseAttributeFilter(%q) error = %v, expected ErrInvalidAttributeFilter", tt.filter, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAttributeFilter(%q) error = %v", tt.filter, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseAttributeFilter(%q) = %+v, expected %+v", tt.filter, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseAttributeSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		valid  bool
+	}{
+		{
+			name:   "object",
+			schema: `{"type":"object","properties":{"casualties":{"type":"integer","minimum":0},"victor":{"type":"string"}},"required":["victor"]}`,
+			valid:  true,
+		},
+		{
+			name:   "nested additional properties",
+			schema: `{"type":"object","additionalProperties":{"type":"object","properties":{"code":{"type":"string","pattern":"^[A-Z]{3}$"}}}}`,
+			valid:  true,
+		},
+		{name: "closed object", schema: `{"type":"object","additionalProperties":false}`, valid: true},
+		{name: "not an object", schema: `{"type":"array","items":{"type":"string"}}`},
+		{name: "malformed", schema: `{"type":"object"`},
+		{name: "invalid pattern", schema: `{"type":"object","properties":{"code":{"type":"string","pattern":"[A-Z"}}}`},
+		{name: "invalid nested pattern", schema: `{"type":"object","additionalProperties":{"type":"string","pattern":"(?<"}}`},
+		{name: "additional properties neither boolean nor schema", schema: `{"type":"object","additionalProperties":"yes"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ParseAttributeSchema([]byte(tt.schema))
+			if !tt.valid {
+				if !errors.Is(err, common.ErrInvalidAttributeSchema) {
+					t.Fatalf("ParseAttributeSchema() error = %v, expected ErrInvalidAttributeSchema", err)
+				}
+				return
+			}
+			if err != nil || schema == nil {
+				t.Fatalf("ParseAttributeSchema() = %v, %v", schema, err)
+			}
+		})
+	}
+}
+
+func TestValidateAttributes(t *testing.T) {
+	schema, err := ParseAttributeSchema([]byte(`{"type":"object","properties":{"casualties":{"type":"integer","minimum":0},"victor":{"type":"string"}},"required":["victor"],"additionalProperties":false}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		schema     bool
+		attributes map[string]any
+		valid      bool
+	}{
+		{name: "valid", schema: true, attributes: map[string]any{"victor": "Prussia", "casualties": 8000.0}, valid: true},
+		{name: "missing required", schema: true, attributes: map[string]any{"casualties": 8000.0}},
+		{name: "below minimum", schema: true, attributes: map[string]any{"victor": "Prussia", "casualties": -1.0}},
+		{name: "unknown attribute", schema: true, attributes: map[string]any{"victor": "Prussia", "weather": "rain"}},
+		{name: "type without schema", attributes: map[string]any{"victor": "Prussia"}},
+		{name: "type without schema or attributes", attributes: map[string]any{}, valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typeSchema := schema
+			if !tt.schema {
+				typeSchema = nil
+			}
+			err := validateAttributes(Event, typeSchema, tt.attributes)
+			if tt.valid && err != nil {
+				t.Errorf("validateAttributes() error = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, common.ErrInvalidAttributes) {
+				t.Errorf("validateAttributes() error = %v, expected ErrInvalidAttributes", err)
+			}
+		})
+	}
+}
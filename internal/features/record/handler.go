@@ -5,35 +5,49 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"historylink/internal/common"
 	"log/slog"
 	"net/http"
+	"reflect"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/negotiation"
+	"github.com/go-jet/jet/v2/qrm"
 	"github.com/google/uuid"
 )
 
-func NewRecordResources(conn *sql.DB, logger *slog.Logger) RecordResources {
+func NewRecordResources(conn *sql.DB, logger *slog.Logger, baseURL string, influenceDecay float64, defaultLanguage string) RecordResources {
 	return RecordResources{
-		logger:        logger,
-		RecordService: NewRecordService(NewRepository(conn, logger), logger),
+		logger:          logger,
+		baseURL:         baseURL,
+		defaultLanguage: defaultLanguage,
+		RecordService:   NewRecordService(NewRepository(conn, logger), logger, influenceDecay, defaultLanguage),
 	}
 }
 
 type RecordResources struct {
-	RecordService IRecordService
-	logger        *slog.Logger
+	RecordService   IRecordService
+	logger          *slog.Logger
+	baseURL         string
+	defaultLanguage string
 }
 
 func (rs RecordResources) create(c context.Context, input *struct {
-	Body createRecordCommandBody
+	common.ActorInput
+	AcceptLanguage string `header:"Accept-Language"`
+	Body           createRecordCommandBody
 }) (*struct {
 	Body recordResponseBody
 }, error) {
-	response, err := rs.RecordService.Create(c, input.Body)
+	response, err := rs.RecordService.Create(input.WithActor(c), input.Body)
 	if err != nil {
+		if humaErr := commandError(err); humaErr != nil {
+			return nil, humaErr
+		}
 		rs.logger.Error(err.Error())
 		return nil, err
 	}
+	response.localize(input.AcceptLanguage, rs.defaultLanguage)
 
 	return &struct {
 		Body recordResponseBody
@@ -42,20 +56,152 @@ func (rs RecordResources) create(c context.Context, input *struct {
 	}, nil
 }
 
+// recordOutput carries either a recordResponseBody, negotiated like any other
+// response, or a linked data document with its content type set explicitly.
+// Records merged into another one answer with a redirect instead.
+type recordOutput struct {
+	Status          int
+	Location        string `header:"Location"`
+	ContentType     string `header:"Content-Type"`
+	ContentLanguage string `header:"Content-Language"`
+	Body            any
+}
+
 func (rs RecordResources) getById(c context.Context, input *struct {
-	ID uuid.UUID `path:"id"`
+	ID             uuid.UUID `path:"id"`
+	Accept         string    `header:"Accept"`
+	AcceptLanguage string    `header:"Accept-Language"`
+}) (*recordOutput, error) {
+	survivorId, err := rs.RecordService.GetRedirect(c, input.ID)
+	switch {
+	case err == nil:
+		return &recordOutput{
+			Status:   http.StatusMovedPermanently,
+			Location: fmt.Sprintf("/records/%s", survivorId),
+			Body:     []byte{},
+		}, nil
+	case !errors.Is(err, common.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if contentType := negotiation.SelectQValueFast(input.Accept, []string{"application/json", JSONLD, Turtle}); contentType == JSONLD || contentType == Turtle {
+		document, err := rs.RecordService.GetLinkedData(c, input.ID, contentType, rs.baseURL)
+		if err != nil {
+			return nil, rs.getByIdError(input.ID, err)
+		}
+		return &recordOutput{
+			Status:      http.StatusOK,
+			ContentType: contentType,
+			Body:        document,
+		}, nil
+	}
+
+	record, err := rs.RecordService.GetById(c, input.ID)
+	if err != nil {
+		return nil, rs.getByIdError(input.ID, err)
+	}
+
+	return &recordOutput{
+		Status:          http.StatusOK,
+		ContentLanguage: record.localize(input.AcceptLanguage, rs.defaultLanguage),
+		Body:            record,
+	}, nil
+}
+
+func (rs RecordResources) getByIdError(id uuid.UUID, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, qrm.ErrNoRows):
+		return huma.Error404NotFound(fmt.Sprintf("Record with id %v not found", id.String()))
+	default:
+		return err
+	}
+}
+
+func (rs RecordResources) getByExternalId(c context.Context, input *struct {
+	Scheme         ExternalIdScheme `path:"scheme" enum:"wikidata,viaf,geonames,isni,custom"`
+	Value          string           `path:"value" maxLength:"255"`
+	AcceptLanguage string           `header:"Accept-Language"`
 }) (*struct {
 	Body recordResponseBody
 }, error) {
-	record, err := rs.RecordService.GetById(input.ID)
+	record, err := rs.RecordService.GetByExternalId(c, input.Scheme, input.Value)
 	if err != nil {
 		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			return nil, huma.Error404NotFound(fmt.Sprintf("Record with id %v not found", input.ID.String()))
+		case errors.Is(err, common.ErrRecordNotFound):
+			return nil, huma.Error404NotFound(fmt.Sprintf("Record with %s identifier %s not found", input.Scheme, input.Value))
+		case errors.Is(err, common.ErrInvalidExternalId):
+			return nil, huma.Error400BadRequest(err.Error())
 		default:
 			return nil, err
 		}
 	}
+	record.localize(input.AcceptLanguage, rs.defaultLanguage)
+
+	return &struct {
+		Body recordResponseBody
+	}{
+		Body: record,
+	}, nil
+}
+
+// commandError maps the errors of validating the external identifiers and
+// labels of a record onto HTTP errors, or returns nil for any other error.
+func commandError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrInvalidExternalId), errors.Is(err, common.ErrInvalidLabel), errors.Is(err, common.ErrInvalidAttributes), errors.Is(err, common.ErrMissingProvenance):
+		return huma.Error422UnprocessableEntity(err.Error())
+	case errors.Is(err, common.ErrExternalIdAlreadyExists):
+		return huma.Error409Conflict(err.Error())
+	}
+	return nil
+}
+
+func (rs RecordResources) getDuplicates(c context.Context, input *struct {
+	ID    uuid.UUID `path:"id"`
+	Limit int       `query:"limit" minimum:"1" maximum:"100" default:"10"`
+}) (*struct {
+	Body []duplicateResponse
+}, error) {
+	duplicates, err := rs.RecordService.GetDuplicates(c, input.ID, input.Limit)
+	if err != nil {
+		return nil, rs.getByIdError(input.ID, err)
+	}
+
+	if duplicates == nil {
+		duplicates = []duplicateResponse{}
+	}
+
+	return &struct {
+		Body []duplicateResponse
+	}{
+		Body: duplicates,
+	}, nil
+}
+
+func (rs RecordResources) merge(c context.Context, input *struct {
+	common.ActorInput
+	ID             uuid.UUID `path:"id"`
+	AcceptLanguage string    `header:"Accept-Language"`
+	Body           mergeRecordCommandBody
+}) (*struct {
+	Body recordResponseBody
+}, error) {
+	record, err := rs.RecordService.Merge(input.WithActor(c), input.ID, input.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrMergeWithItself):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, common.ErrRecordNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, common.ErrRecordAlreadyMerged),
+			errors.Is(err, common.ErrRecordRemoved),
+			errors.Is(err, common.ErrArcCycle):
+			return nil, huma.Error409Conflict(err.Error())
+		}
+		rs.logger.Error(err.Error())
+		return nil, err
+	}
+	record.localize(input.AcceptLanguage, rs.defaultLanguage)
 
 	return &struct {
 		Body recordResponseBody
@@ -65,11 +211,15 @@ func (rs RecordResources) getById(c context.Context, input *struct {
 }
 
 func (rs RecordResources) update(c context.Context, input *struct {
+	common.ActorInput
 	ID   uuid.UUID `path:"id"`
 	Body updateRecordCommandBody
 }) (*struct{}, error) {
-	err := rs.RecordService.Update(c, input.ID, input.Body)
+	err := rs.RecordService.Update(input.WithActor(c), input.ID, input.Body)
 	if err != nil {
+		if humaErr := commandError(err); humaErr != nil {
+			return nil, humaErr
+		}
 		return nil, err
 	}
 
@@ -77,16 +227,45 @@ func (rs RecordResources) update(c context.Context, input *struct {
 }
 
 func (rs RecordResources) getPaged(c context.Context, input *struct {
-	Page     int `query:"page" minimum:"1" default:"1"`
-	PageSize int `query:"pageSize" minimum:"1" default:"10"`
+	Page       int        `query:"page" minimum:"1" default:"1"`
+	PageSize   int        `query:"pageSize" minimum:"1" default:"10"`
+	Sort       RecordSort `query:"sort" enum:"id,influence" default:"id" doc:"Order of the records, influence lists the most influential first"`
+	Search     string     `query:"q" maxLength:"255" doc:"Only list records whose title, translated title or alias contains this"`
+	Terms      []string   `query:"term" doc:"Only list records tagged with all of these term ids, or with terms narrower than them"`
+	Attributes []string   `query:"attribute" doc:"Only list records whose attributes match all of these, written as name=value, or as name>number and the like for numeric attributes"`
+
+	AcceptLanguage string `header:"Accept-Language"`
 }) (*struct {
 	Body pagedResponse[recordResponseBody]
 }, error) {
-	records, total, err := rs.RecordService.GetPaged(c, input.Page, input.PageSize)
+	filter := RecordFilter{Search: input.Search}
+	for _, term := range input.Terms {
+		id, err := uuid.Parse(term)
+		if err != nil {
+			return nil, huma.Error422UnprocessableEntity(fmt.Sprintf("invalid term id %q", term))
+		}
+		filter.Terms = append(filter.Terms, id)
+	}
+	for _, attribute := range input.Attributes {
+		f, err := ParseAttributeFilter(attribute)
+		if err != nil {
+			return nil, huma.Error422UnprocessableEntity(err.Error())
+		}
+		filter.Attributes = append(filter.Attributes, f)
+	}
+
+	records, total, err := rs.RecordService.GetPaged(c, input.Page, input.PageSize, input.Sort, filter)
 	if err != nil {
+		if errors.Is(err, common.ErrTermNotFound) {
+			return nil, huma.Error422UnprocessableEntity(err.Error())
+		}
 		return nil, err
 	}
 
+	for i := range records {
+		records[i].localize(input.AcceptLanguage, rs.defaultLanguage)
+	}
+
 	if records == nil {
 		records = []recordResponseBody{}
 	}
@@ -120,7 +299,46 @@ func (rs RecordResources) MountRoutes(s huma.API) {
 		Method:        http.MethodGet,
 		Path:          "/records/{id}",
 		DefaultStatus: http.StatusOK,
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Record, or the record as linked data when requested through the Accept header",
+				Content: map[string]*huma.MediaType{
+					"application/json": {
+						Schema: s.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(recordResponseBody{}), true, ""),
+					},
+					JSONLD: {
+						Schema: &huma.Schema{Type: huma.TypeObject},
+					},
+					Turtle: {
+						Schema: &huma.Schema{Type: huma.TypeString},
+					},
+				},
+			},
+			"301": {
+				Description: "Record was merged into the record in the Location header",
+				Headers: map[string]*huma.Param{
+					"Location": {Schema: &huma.Schema{Type: huma.TypeString}},
+				},
+			},
+		},
 	}, rs.getById)
+	huma.Register(s, huma.Operation{
+		OperationID:   "get-record-by-external-id",
+		Method:        http.MethodGet,
+		Path:          "/records/by-external-id/{scheme}/{value}",
+		DefaultStatus: http.StatusOK,
+	}, rs.getByExternalId)
+	huma.Register(s, huma.Operation{
+		OperationID:   "get-record-duplicates",
+		Method:        http.MethodGet,
+		Path:          "/records/{id}/duplicates",
+		DefaultStatus: http.StatusOK,
+	}, rs.getDuplicates)
+	huma.Register(s, huma.Operation{
+		OperationID: "merge-records",
+		Method:      http.MethodPost,
+		Path:        "/records/{id}/merge",
+	}, rs.merge)
 	huma.Register(s, huma.Operation{
 		OperationID: "create-record",
 		Method:      http.MethodPost,
@@ -0,0 +1,88 @@
+package record
+
+import (
+	"container/heap"
+	"math"
+
+	"historylink/.gen/historylink/public/model"
+
+	"github.com/google/uuid"
+)
+
+// influenceCutoff stops following links once the share of an impact that
+// would still reach a record is negligible.
+const influenceCutoff = 1e-4
+
+// computeInfluence propagates impact downstream along links, from the record a
+// link starts at to the record it points to. The influence of a record is its
+// own impact plus the impact of every record it reaches, scaled by the decay
+// for every link followed and by the strength of that link relative to the
+// strongest link. A record reached along several paths counts once, through
+// the path that passes on the most, so cycles do not inflate scores.
+func computeInfluence(links []model.Link, impacts map[uuid.UUID]float64, decay float64) map[uuid.UUID]float64 {
+	maxStrength := 1.0
+	for _, link := range links {
+		maxStrength = math.Max(maxStrength, float64(link.Strength))
+	}
+
+	outgoing := map[uuid.UUID]map[uuid.UUID]float64{}
+	for _, link := range links {
+		if link.RecordID == link.RecordId2 {
+			continue
+		}
+		if outgoing[link.RecordID] == nil {
+			outgoing[link.RecordID] = map[uuid.UUID]float64{}
+		}
+		// Links without a positive strength still pass on some influence
+		factor := decay * math.Max(float64(link.Strength), 1) / maxStrength
+		outgoing[link.RecordID][link.RecordId2] = math.Max(outgoing[link.RecordID][link.RecordId2], factor)
+	}
+
+	influence := make(map[uuid.UUID]float64, len(impacts))
+	for id, impact := range impacts {
+		influence[id] = impact
+	}
+
+	for source := range outgoing {
+		// Best-first search, as multiplying by factors of at most one never
+		// raises the share passed on by a longer path
+		reached := map[uuid.UUID]bool{}
+		queue := &influenceQueue{{id: source, share: 1}}
+		for queue.Len() > 0 {
+			item := heap.Pop(queue).(influenceItem)
+			if reached[item.id] {
+				continue
+			}
+			reached[item.id] = true
+			if item.id != source {
+				influence[source] += impacts[item.id] * item.share
+			}
+
+			for target, factor := range outgoing[item.id] {
+				if share := item.share * factor; !reached[target] && share >= influenceCutoff {
+					heap.Push(queue, influenceItem{id: target, share: share})
+				}
+			}
+		}
+	}
+
+	return influence
+}
+
+type influenceItem struct {
+	id    uuid.UUID
+	share float64
+}
+
+type influenceQueue []influenceItem
+
+func (q influenceQueue) Len() int           { return len(q) }
+func (q influenceQueue) Less(i, j int) bool { return q[i].share > q[j].share }
+func (q influenceQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *influenceQueue) Push(x any)        { *q = append(*q, x.(influenceItem)) }
+func (q *influenceQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
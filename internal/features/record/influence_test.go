@@ -0,0 +1,103 @@
+package record
+
+import (
+	"math"
+	"testing"
+
+	"historylink/.gen/historylink/public/model"
+
+	"github.com/google/uuid"
+)
+
+func TestComputeInfluence(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	link := func(from, to uuid.UUID, strength int16) model.Link {
+		return model.Link{ID: uuid.New(), RecordID: from, RecordId2: to, Strength: strength}
+	}
+	impacts := map[uuid.UUID]float64{a: 1, b: 2, c: 4}
+
+	tests := []struct {
+		name     string
+		links    []model.Link
+		impacts  map[uuid.UUID]float64
+		decay    float64
+		expected map[uuid.UUID]float64
+	}{
+		{
+			name:     "without links",
+			impacts:  impacts,
+			decay:    0.5,
+			expected: map[uuid.UUID]float64{a: 1, b: 2, c: 4},
+		},
+		{
+			name:     "downstream only",
+			links:    []model.Link{link(a, b, 5), link(b, c, 5)},
+			impacts:  impacts,
+			decay:    0.5,
+			expected: map[uuid.UUID]float64{a: 1 + 2*0.5 + 4*0.25, b: 2 + 4*0.5, c: 4},
+		},
+		{
+			name:     "cycles count every record once",
+			links:    []model.Link{link(a, b, 5), link(b, a, 5)},
+			impacts:  impacts,
+			decay:    0.5,
+			expected: map[uuid.UUID]float64{a: 1 + 2*0.5, b: 2 + 1*0.5, c: 4},
+		},
+		{
+			name:     "the path passing on the most counts",
+			links:    []model.Link{link(a, b, 5), link(b, c, 5), link(a, c, 5)},
+			impacts:  impacts,
+			decay:    0.5,
+			expected: map[uuid.UUID]float64{a: 1 + 2*0.5 + 4*0.5, b: 2 + 4*0.5, c: 4},
+		},
+		{
+			name:     "weaker links pass on less",
+			links:    []model.Link{link(a, b, 10), link(c, b, 5)},
+			impacts:  impacts,
+			decay:    0.5,
+			expected: map[uuid.UUID]float64{a: 1 + 2*0.5, b: 2, c: 4 + 2*0.5*0.5},
+		},
+		{
+			name:     "links without strength",
+			links:    []model.Link{link(a, b, 0)},
+			impacts:  impacts,
+			decay:    0.5,
+			expected: map[uuid.UUID]float64{a: 1 + 2*0.5, b: 2, c: 4},
+		},
+		{
+			name:     "records without impact",
+			links:    []model.Link{link(a, b, 5)},
+			impacts:  map[uuid.UUID]float64{b: 2},
+			decay:    0.5,
+			expected: map[uuid.UUID]float64{a: 2 * 0.5, b: 2},
+		},
+		{
+			name:     "links to itself",
+			links:    []model.Link{link(a, a, 5)},
+			impacts:  impacts,
+			decay:    0.5,
+			expected: map[uuid.UUID]float64{a: 1, b: 2, c: 4},
+		},
+		{
+			name:     "negligible shares are cut off",
+			links:    []model.Link{link(a, b, 5)},
+			impacts:  impacts,
+			decay:    influenceCutoff / 2,
+			expected: map[uuid.UUID]float64{a: 1, b: 2, c: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeInfluence(tt.links, tt.impacts, tt.decay)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("computeInfluence() = %v, expected %v", got, tt.expected)
+			}
+			for id, expected := range tt.expected {
+				if math.Abs(got[id]-expected) > 1e-9 {
+					t.Errorf("computeInfluence()[%s] = %f, expected %f", id, got[id], expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package record
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+)
+
+const (
+	JSONLD = "application/ld+json"
+	Turtle = "text/turtle"
+)
+
+// ldNode is a resource of the linked data graph. Nodes without an ID are
+// blank nodes and are written nested inside the node referencing them.
+type ldNode struct {
+	ID         string
+	Types      []string
+	Properties []ldProperty
+}
+
+type ldProperty struct {
+	Predicate string
+	// Object is an ldLiteral, an ldRef or a nested *ldNode.
+	Object any
+}
+
+type ldLiteral struct {
+	Value    string
+	Datatype string
+}
+
+type ldRef string
+
+func ldPrefixes(baseURL string) map[string]string {
+	return map[string]string{
+		"schema":  "https://schema.org/",
+		"crm":     "http://www.cidoc-crm.org/cidoc-crm/",
+		"dcterms": "http://purl.org/dc/terms/",
+		"xsd":     "http://www.w3.org/2001/XMLSchema#",
+		"hl":      strings.TrimSuffix(baseURL, "/") + "/vocab#",
+	}
+}
+
+// ldTypes maps record types onto schema.org classes for general consumers
+// and CIDOC-CRM classes for cultural heritage triple stores.
+var ldTypes = map[Type][]string{
+	Arc:    {"schema:Event", "crm:E4_Period"},
+	Event:  {"schema:Event", "crm:E5_Event"},
+	Person: {"schema:Person", "crm:E21_Person"},
+	Object: {"schema:Thing", "crm:E22_Human-Made_Object"},
+}
+
+func recordIRI(baseURL string, id fmt.Stringer) string {
+	return fmt.Sprintf("%s/records/%s", strings.TrimSuffix(baseURL, "/"), id.String())
+}
+
+// linkedDataAggregate is a record together with what it is connected to,
+// which is only loaded when linked data is requested.
+type linkedDataAggregate struct {
+	RecordAggregate
+
+	Sources []model.Source
+	Links   []LinkedRecord
+}
+
+// LinkedRecord is a link seen from one of its records, with the record at
+// the other end.
+type LinkedRecord struct {
+	model.Link
+
+	Record model.Record
+}
+
+func (record linkedDataAggregate) toLinkedData(baseURL string) *ldNode {
+	node := &ldNode{
+		ID:    recordIRI(baseURL, record.ID),
+		Types: ldTypes[TypeFromInt16(record.Type)],
+	}
+	add := func(predicate string, object any) {
+		node.Properties = append(node.Properties, ldProperty{Predicate: predicate, Object: object})
+	}
+
+	add("schema:name", ldLiteral{Value: record.Title})
+	add("schema:description", ldLiteral{Value: record.Description})
+	if record.StartDate != nil {
+		add("schema:startDate", ldLiteral{Value: common.ToDateString(record.StartDate), Datatype: "xsd:date"})
+	}
+	if record.EndDate != nil {
+		add("schema:endDate", ldLiteral{Value: common.ToDateString(record.EndDate), Datatype: "xsd:date"})
+	}
+	if record.Location != nil && *record.Location != "" {
+		add("schema:location", ldLiteral{Value: *record.Location})
+	}
+	if record.Significance != nil && *record.Significance != "" {
+		add("hl:significance", ldLiteral{Value: *record.Significance})
+	}
+	if record.URL != "" {
+		add("schema:url", ldRef(record.URL))
+	}
+	add("hl:status", ldLiteral{Value: string(RecordStatusFromInt16(record.Status))})
+
+	for _, impact := range record.Impacts {
+		add("hl:impact", &ldNode{
+			Types: []string{"hl:Impact"},
+			Properties: []ldProperty{
+				{Predicate: "hl:category", Object: ldLiteral{Value: string(CategoryFromInt16(impact.Category))}},
+				{Predicate: "hl:value", Object: ldLiteral{Value: strconv.Itoa(int(impact.Value)), Datatype: "xsd:integer"}},
+				{Predicate: "schema:description", Object: ldLiteral{Value: impact.Description}},
+			},
+		})
+	}
+
+	for _, link := range record.Links {
+		target := ldRef(recordIRI(baseURL, link.Record.ID))
+		add("dcterms:relation", target)
+		add("hl:link", &ldNode{
+			Types: []string{"hl:Link"},
+			Properties: []ldProperty{
+				{Predicate: "hl:target", Object: target},
+				{Predicate: "hl:strength", Object: ldLiteral{Value: strconv.Itoa(int(link.Strength)), Datatype: "xsd:integer"}},
+			},
+		})
+	}
+
+	for _, source := range record.Sources {
+		citation := &ldNode{
+			Types: []string{"schema:CreativeWork"},
+			Properties: []ldProperty{
+				{Predicate: "schema:name", Object: ldLiteral{Value: source.Title}},
+			},
+		}
+		if source.URL != "" {
+			citation.Properties = append(citation.Properties, ldProperty{Predicate: "schema:url", Object: ldRef(source.URL)})
+		}
+		if source.Description != nil && *source.Description != "" {
+			citation.Properties = append(citation.Properties, ldProperty{Predicate: "schema:description", Object: ldLiteral{Value: *source.Description}})
+		}
+		add("schema:citation", citation)
+	}
+
+	return node
+}
+
+// marshalJSONLD renders the node as compacted JSON-LD, using the prefixes as
+// context so property names stay readable.
+func marshalJSONLD(node *ldNode, baseURL string) ([]byte, error) {
+	document := jsonLDObject(node)
+	document["@context"] = ldPrefixes(baseURL)
+	return json.Marshal(document)
+}
+
+func jsonLDObject(node *ldNode) map[string]any {
+	object := map[string]any{}
+	if node.ID != "" {
+		object["@id"] = node.ID
+	}
+	if len(node.Types) > 0 {
+		object["@type"] = node.Types
+	}
+
+	for _, property := range node.Properties {
+		var value any
+		switch o := property.Object.(type) {
+		case ldLiteral:
+			if o.Datatype == "" {
+				value = o.Value
+			} else {
+				value = map[string]any{"@value": o.Value, "@type": o.Datatype}
+			}
+		case ldRef:
+			value = map[string]any{"@id": string(o)}
+		case *ldNode:
+			value = jsonLDObject(o)
+		}
+
+		switch existing := object[property.Predicate].(type) {
+		case nil:
+			object[property.Predicate] = value
+		case []any:
+			object[property.Predicate] = append(existing, value)
+		default:
+			object[property.Predicate] = []any{existing, value}
+		}
+	}
+
+	return object
+}
+
+// marshalTurtle renders the node as RDF Turtle with blank nodes written
+// inline.
+func marshalTurtle(node *ldNode, baseURL string) []byte {
+	var b strings.Builder
+
+	prefixes := ldPrefixes(baseURL)
+	names := make([]string, 0, len(prefixes))
+	for name := range prefixes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "@prefix %s: <%s> .\n", name, prefixes[name])
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "<%s>", node.ID)
+	writeTurtleProperties(&b, node, 1)
+	b.WriteString(" .\n")
+
+	return []byte(b.String())
+}
+
+func writeTurtleProperties(b *strings.Builder, node *ldNode, depth int) {
+	indent := strings.Repeat("    ", depth)
+	separator := "\n" + indent
+
+	if len(node.Types) > 0 {
+		fmt.Fprintf(b, "%sa %s", separator, strings.Join(node.Types, ", "))
+		separator = " ;\n" + indent
+	}
+
+	for _, property := range node.Properties {
+		fmt.Fprintf(b, "%s%s ", separator, property.Predicate)
+		switch o := property.Object.(type) {
+		case ldLiteral:
+			b.WriteString(quoteTurtle(o.Value))
+			if o.Datatype != "" {
+				b.WriteString("^^" + o.Datatype)
+			}
+		case ldRef:
+			fmt.Fprintf(b, "<%s>", escapeIRI(string(o)))
+		case *ldNode:
+			b.WriteString("[")
+			writeTurtleProperties(b, o, depth+1)
+			b.WriteString("\n" + indent + "]")
+		}
+		separator = " ;\n" + indent
+	}
+}
+
+func quoteTurtle(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// escapeIRI percent-encodes the characters Turtle does not allow inside an
+// IRI reference, since record and source URLs are free text.
+func escapeIRI(iri string) string {
+	var b strings.Builder
+	for _, r := range iri {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&b, "%%%02X", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -1,10 +1,14 @@
 package record
 
 import (
+	"encoding/json"
+	"fmt"
+	"historylink/.gen/historylink/public/model"
 	"historylink/internal/common"
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
+	"golang.org/x/text/language"
 )
 
 type pagedResponse[t any] struct {
@@ -14,28 +18,132 @@ type pagedResponse[t any] struct {
 	Records []t `json:"records"`
 }
 
+type RecordSort string
+
+const (
+	SortById RecordSort = "id"
+	// SortByInfluence lists the most influential records first
+	SortByInfluence RecordSort = "influence"
+)
+
 type impactResponse struct {
-	Value       int16     `json:"value"`
-	Category    Category  `json:"category"`
-	Description string    `json:"description"`
+	Value       int16       `json:"value"`
+	Category    Category    `json:"category"`
+	Confidence  *Confidence `json:"confidence"`
+	Description string      `json:"description"`
+	ID          uuid.UUID   `json:"id"`
+	RecordID    uuid.UUID   `json:"recordId"`
+}
+
+type externalIdResponse struct {
+	Scheme ExternalIdScheme `json:"scheme"`
+	Value  string           `json:"value"`
+	Url    *string          `json:"url"`
+}
+
+type labelResponse struct {
+	Language    string  `json:"language"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+}
+
+type aliasResponse struct {
+	Name     string  `json:"name"`
+	Language *string `json:"language"`
+}
+
+type claimResponse struct {
 	ID          uuid.UUID `json:"id"`
-	RecordID    uuid.UUID `json:"recordId"`
+	SourceID    uuid.UUID `json:"sourceId"`
+	SourceTitle string    `json:"sourceTitle"`
+	// Field or ImpactID is the part of the record the source backs
+	Field    *ClaimField `json:"field"`
+	ImpactID *uuid.UUID  `json:"impactId"`
+	// AlternativeID is set when the source backs a competing value of the
+	// field rather than the value the record holds
+	AlternativeID *uuid.UUID `json:"alternativeId"`
+	Confidence    Confidence `json:"confidence"`
+	Page          *string    `json:"page"`
+	Quote         *string    `json:"quote"`
+}
+
+// alternativeResponse is a value competing with the value a field holds,
+// which is the preferred value.
+type alternativeResponse struct {
+	ID    uuid.UUID  `json:"id"`
+	Field ClaimField `json:"field"`
+	Value string     `json:"value"`
 }
 
 type recordResponseBody struct {
-	ID           uuid.UUID        `json:"id"`
-	Title        string           `json:"title"`
-	Description  string           `json:"description"`
-	Location     *string          `json:"location"`
-	Significance *string          `json:"significance"`
-	Url          string           `json:"url"`
-	StartDate    string           `json:"startDate"`
-	EndDate      string           `json:"endDate"`
-	RecordStatus RecordStatus     `json:"recordStatus"`
-	Type         Type             `json:"type"`
-	UpdatedAt    string           `json:"updatedAt"`
-	CreatedAt    string           `json:"createdAt"`
-	Impacts      []impactResponse `json:"impacts"`
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	// Language is the language of the title and description, picked from the
+	// labels of the record according to the Accept-Language header
+	Language     string       `json:"language"`
+	Location     *string      `json:"location"`
+	Significance *string      `json:"significance"`
+	Url          string       `json:"url"`
+	StartDate    string       `json:"startDate"`
+	EndDate      string       `json:"endDate"`
+	RecordStatus RecordStatus `json:"recordStatus"`
+	Type         Type         `json:"type"`
+	// Confidence in the record as a whole, null when not assessed
+	Confidence *Confidence `json:"confidence"`
+	// Attributes are the extra fields described by the schema of the type
+	Attributes map[string]any `json:"attributes"`
+	// Revision identifies the current revision of the record, which stays
+	// citable at /records/{id}/revisions/{revision} after later changes.
+	// Revisions keep the title, description, location, significance, url,
+	// dates, type and status; impacts, attributes, labels, aliases and
+	// confidence are not versioned
+	Revision  uuid.UUID        `json:"revision"`
+	UpdatedAt string           `json:"updatedAt"`
+	CreatedAt string           `json:"createdAt"`
+	Impacts   []impactResponse `json:"impacts"`
+	// ImpactTotal and ImpactByCategory sum the values of the impacts
+	ImpactTotal      int              `json:"impactTotal"`
+	ImpactByCategory map[Category]int `json:"impactByCategory"`
+	// Influence is the impact of the record and of the records downstream of
+	// it, decaying with every link followed. It is recomputed in the
+	// background, so it can lag behind changes for a few seconds
+	Influence   float64              `json:"influence"`
+	ExternalIds []externalIdResponse `json:"externalIds"`
+	// Labels translate the title and description into languages other than
+	// the default language
+	Labels  []labelResponse `json:"labels"`
+	Aliases []aliasResponse `json:"aliases"`
+	// Claims state which source backs which field or impact of the record.
+	// They are only given when fetching a single record.
+	Claims []claimResponse `json:"claims,omitempty"`
+	// Alternatives compete with the preferred values the fields hold. They
+	// are only given when fetching a single record.
+	Alternatives []alternativeResponse `json:"alternatives,omitempty"`
+	// Warnings are only given when creating a record and point out problems
+	// that did not prevent creating it, such as records it might duplicate.
+	Warnings []recordWarning `json:"warnings,omitempty"`
+}
+
+type WarningCode string
+
+const (
+	PossibleDuplicate WarningCode = "possible-duplicate"
+)
+
+type recordWarning struct {
+	Code      WarningCode        `json:"code" enum:"possible-duplicate"`
+	Message   string             `json:"message"`
+	Duplicate *duplicateResponse `json:"duplicate,omitempty"`
+}
+
+type duplicateResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Type      Type      `json:"type"`
+	StartDate string    `json:"startDate"`
+	EndDate   string    `json:"endDate"`
+	Score     float64   `json:"score"`
 }
 
 type createRecordCommandBody struct {
@@ -46,9 +154,14 @@ type createRecordCommandBody struct {
 	Url          string                    `json:"url" minLength:"1" maxLength:"255"`
 	StartDate    string                    `json:"startDate" format:"date"`
 	EndDate      string                    `json:"endDate" format:"date"`
-	RecordStatus RecordStatus              `json:"recordStatus" enum:"removed,draft,pending,reviewed"`
-	Type         Type                      `json:"type" enum:"arc,event,person,object"`
+	RecordStatus RecordStatus              `json:"recordStatus"`
+	Type         Type                      `json:"type"`
+	Confidence   *Confidence               `json:"confidence,omitempty" enum:"low,medium,high"`
+	Attributes   map[string]any            `json:"attributes,omitempty" doc:"Extra fields, checked against the attribute schema of the type"`
 	Impacts      []createImpactCommandBody `json:"impacts"`
+	ExternalIds  []externalIdCommandBody   `json:"externalIds,omitempty"`
+	Labels       []labelCommandBody        `json:"labels,omitempty"`
+	Aliases      []aliasCommandBody        `json:"aliases,omitempty"`
 }
 
 type updateRecordCommandBody struct {
@@ -60,26 +173,66 @@ type updateRecordCommandBody struct {
 	Url          string                    `json:"url" minLength:"1" maxLength:"255"`
 	StartDate    string                    `json:"startDate" format:"date"`
 	EndDate      string                    `json:"endDate" format:"date"`
-	RecordStatus RecordStatus              `json:"recordStatus" enum:"removed,draft,pending,reviewed"`
-	Type         Type                      `json:"type" enum:"arc,event,person,object"`
+	RecordStatus RecordStatus              `json:"recordStatus"`
+	Type         Type                      `json:"type"`
+	Confidence   *Confidence               `json:"confidence,omitempty" enum:"low,medium,high"`
 	Impacts      []updateImpactCommandBody `json:"impacts"`
+	// ExternalIds replaces the identifiers of the record when given and leaves
+	// them untouched when left out.
+	ExternalIds []externalIdCommandBody `json:"externalIds,omitempty"`
+	// Labels and Aliases are replaced in the same way as ExternalIds
+	Labels  []labelCommandBody `json:"labels,omitempty"`
+	Aliases []aliasCommandBody `json:"aliases,omitempty"`
+	// Attributes are replaced in the same way too. Attributes left out are
+	// still checked against the schema of the type, which may have changed.
+	Attributes map[string]any `json:"attributes,omitempty" doc:"Extra fields, checked against the attribute schema of the type"`
+}
+
+type labelCommandBody struct {
+	Language    string  `json:"language" minLength:"2" maxLength:"35" doc:"BCP 47 language tag, such as nl or fr-BE"`
+	Title       string  `json:"title" minLength:"1" maxLength:"255"`
+	Description *string `json:"description,omitempty" maxLength:"255"`
+}
+
+type aliasCommandBody struct {
+	Name     string  `json:"name" minLength:"1" maxLength:"255"`
+	Language *string `json:"language,omitempty" maxLength:"35"`
 }
 
 type createImpactCommandBody struct {
-	Description string   `json:"description" minLength:"1" maxLength:"255"`
-	Value       int16    `json:"value" minimum:"1" maximum:"10"`
-	Category    Category `json:"category" enum:"economic,political,social,cultural,tech"`
+	Description string      `json:"description" minLength:"1" maxLength:"255"`
+	Value       int16       `json:"value" minimum:"1" maximum:"10"`
+	Category    Category    `json:"category"`
+	Confidence  *Confidence `json:"confidence,omitempty" enum:"low,medium,high"`
 }
 
 type updateImpactCommandBody struct {
-	ID          uuid.UUID `json:"id,omitempty"`
-	Description string    `json:"description" minLength:"1" maxLength:"255"`
-	Value       int16     `json:"value" minimum:"1" maximum:"10"`
-	Category    Category  `json:"category" enum:"economic,political,social,cultural,tech"`
-	RecordId    uuid.UUID `json:"recordId,omitempty"`
+	ID          uuid.UUID   `json:"id,omitempty"`
+	Description string      `json:"description" minLength:"1" maxLength:"255"`
+	Value       int16       `json:"value" minimum:"1" maximum:"10"`
+	Category    Category    `json:"category"`
+	Confidence  *Confidence `json:"confidence,omitempty" enum:"low,medium,high"`
+	RecordId    uuid.UUID   `json:"recordId,omitempty"`
+}
+
+type mergeRecordCommandBody struct {
+	DuplicateID uuid.UUID `json:"duplicateId" doc:"Record to merge into this one, which afterwards redirects here"`
+}
+
+type externalIdCommandBody struct {
+	Scheme ExternalIdScheme `json:"scheme" enum:"wikidata,viaf,geonames,isni,custom"`
+	Value  string           `json:"value" minLength:"1" maxLength:"255" doc:"Identifier within the scheme, written as namespace:id without slashes for custom identifiers"`
 }
 
 func (record RecordAggregate) toResponse() recordResponseBody {
+	impactTotal, impactByCategory := ImpactTotals(lo.Map(record.Impacts, func(impact ImpactEntity, index int) model.Impact {
+		return impact.Impact
+	}))
+	influence := 0.0
+	if record.Influence != nil {
+		influence = record.Influence.Influence
+	}
+
 	return recordResponseBody{
 		ID:           record.ID,
 		Title:        record.Title,
@@ -91,20 +244,114 @@ func (record RecordAggregate) toResponse() recordResponseBody {
 		EndDate:      common.ToDateString(record.EndDate),
 		RecordStatus: RecordStatusFromInt16(record.Status),
 		Type:         TypeFromInt16(record.Type),
+		Confidence:   ConfidenceFromNullInt16(record.Confidence),
+		Attributes:   record.attributes(),
 		Impacts: lo.Map(record.Impacts, func(impact ImpactEntity, index int) impactResponse {
 			return impact.toResponse()
 		}),
+		ImpactTotal:      impactTotal,
+		ImpactByCategory: impactByCategory,
+		Influence:        influence,
+		ExternalIds: lo.Map(record.ExternalIds, func(externalId ExternalIdEntity, index int) externalIdResponse {
+			return externalId.toResponse()
+		}),
+		Labels: lo.Map(record.Labels, func(label LabelEntity, index int) labelResponse {
+			return labelResponse{Language: label.Language, Title: label.Title, Description: label.Description}
+		}),
+		Aliases: lo.Map(record.Aliases, func(alias AliasEntity, index int) aliasResponse {
+			return aliasResponse{Name: alias.Name, Language: alias.Language}
+		}),
+		Revision:  record.History.ID,
 		UpdatedAt: common.ToDateTimeString(&record.History.UpdatedAt),
 		CreatedAt: common.ToDateTimeString(&record.History.CreatedAt),
 	}
 }
 
+// attributes decodes the attributes of a record, which are stored as JSON.
+func (record RecordAggregate) attributes() map[string]any {
+	attributes := map[string]any{}
+	if record.Attributes != "" {
+		_ = json.Unmarshal([]byte(record.Attributes), &attributes)
+	}
+	return attributes
+}
+
+// ImpactTotals sums impact values overall and per category, listing every
+// category so charts get a zero rather than a gap.
+func ImpactTotals(impacts []model.Impact) (int, map[Category]int) {
+	total := 0
+	categories := Categories.Names()
+	byCategory := make(map[Category]int, len(categories))
+	for _, category := range categories {
+		byCategory[Category(category)] = 0
+	}
+	for _, impact := range impacts {
+		total += int(impact.Value)
+		byCategory[CategoryFromInt16(impact.Category)] += int(impact.Value)
+	}
+	return total, byCategory
+}
+
 func (i ImpactEntity) toResponse() impactResponse {
 	return impactResponse{
 		ID:          i.ID,
 		Value:       i.Value,
 		Category:    CategoryFromInt16(i.Category),
+		Confidence:  ConfidenceFromNullInt16(i.Confidence),
 		Description: i.Description,
 		RecordID:    i.RecordID,
 	}
 }
+
+func (e ExternalIdEntity) toResponse() externalIdResponse {
+	scheme := ExternalIdSchemeFromInt16(e.Scheme)
+	response := externalIdResponse{
+		Scheme: scheme,
+		Value:  e.Value,
+	}
+	if format, ok := externalIdUrls[scheme]; ok {
+		url := fmt.Sprintf(format, e.Value)
+		response.Url = &url
+	}
+	return response
+}
+
+func (d DuplicateCandidate) toResponse() duplicateResponse {
+	return duplicateResponse{
+		ID:        d.ID,
+		Title:     d.Title,
+		Type:      TypeFromInt16(d.Type),
+		StartDate: common.ToDateString(d.StartDate),
+		EndDate:   common.ToDateString(d.EndDate),
+		Score:     d.Score,
+	}
+}
+
+// localize picks the title and description in the language that best matches
+// the Accept-Language header among the default language and the labels,
+// returning the language picked.
+func (r *recordResponseBody) localize(acceptLanguage string, defaultLanguage string) string {
+	r.Language = defaultLanguage
+
+	desired, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(desired) == 0 || len(r.Labels) == 0 {
+		return r.Language
+	}
+
+	supported := []language.Tag{language.Make(defaultLanguage)}
+	for _, label := range r.Labels {
+		supported = append(supported, language.Make(label.Language))
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(desired...)
+	if confidence == language.No || index == 0 {
+		return r.Language
+	}
+
+	label := r.Labels[index-1]
+	r.Language = label.Language
+	r.Title = label.Title
+	if label.Description != nil {
+		r.Description = *label.Description
+	}
+	return r.Language
+}
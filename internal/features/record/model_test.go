@@ -0,0 +1,49 @@
+package record
+
+import "testing"
+
+func TestLocalize(t *testing.T) {
+	description := "Friedensschluss von Münster und Osnabrück"
+	labels := []labelResponse{
+		{Language: "de", Title: "Westfälischer Friede", Description: &description},
+		{Language: "fr", Title: "Traités de Westphalie"},
+		{Language: "pt-BR", Title: "Paz de Vestfália"},
+	}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		labels         []labelResponse
+		language       string
+		title          string
+		description    string
+	}{
+		{name: "no header", language: "en", labels: labels, title: "Peace of Westphalia", description: "Treaties ending the Thirty Years' War"},
+		{name: "default language", acceptLanguage: "en-GB", labels: labels, language: "en", title: "Peace of Westphalia", description: "Treaties ending the Thirty Years' War"},
+		{name: "label", acceptLanguage: "de", labels: labels, language: "de", title: "Westfälischer Friede", description: description},
+		{name: "label without description", acceptLanguage: "fr-CH", labels: labels, language: "fr", title: "Traités de Westphalie", description: "Treaties ending the Thirty Years' War"},
+		{name: "preferred language", acceptLanguage: "nl, fr;q=0.9, de;q=0.8", labels: labels, language: "fr", title: "Traités de Westphalie", description: "Treaties ending the Thirty Years' War"},
+		{name: "regional label", acceptLanguage: "pt-BR", labels: labels, language: "pt-BR", title: "Paz de Vestfália", description: "Treaties ending the Thirty Years' War"},
+		{name: "unsupported language", acceptLanguage: "ja", labels: labels, language: "en", title: "Peace of Westphalia", description: "Treaties ending the Thirty Years' War"},
+		{name: "without labels", acceptLanguage: "de", language: "en", title: "Peace of Westphalia", description: "Treaties ending the Thirty Years' War"},
+		{name: "malformed header", acceptLanguage: "de;q=x", labels: labels, language: "en", title: "Peace of Westphalia", description: "Treaties ending the Thirty Years' War"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := recordResponseBody{
+				Title:       "Peace of Westphalia",
+				Description: "Treaties ending the Thirty Years' War",
+				Labels:      tt.labels,
+			}
+
+			got := record.localize(tt.acceptLanguage, "en")
+			if got != tt.language || record.Language != tt.language {
+				t.Errorf("localize(%q) = %q with language %q, expected %q", tt.acceptLanguage, got, record.Language, tt.language)
+			}
+			if record.Title != tt.title || record.Description != tt.description {
+				t.Errorf("localize(%q) gave %q: %q, expected %q: %q", tt.acceptLanguage, record.Title, record.Description, tt.title, tt.description)
+			}
+		})
+	}
+}
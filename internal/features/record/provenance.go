@@ -0,0 +1,207 @@
+package record
+
+import (
+	"encoding/json"
+	"fmt"
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// ClaimField is a field of a record a source can back.
+type ClaimField string
+
+const (
+	ClaimTitle        ClaimField = "title"
+	ClaimDescription  ClaimField = "description"
+	ClaimLocation     ClaimField = "location"
+	ClaimSignificance ClaimField = "significance"
+	ClaimStartDate    ClaimField = "startDate"
+	ClaimEndDate      ClaimField = "endDate"
+	ClaimType         ClaimField = "type"
+)
+
+// reviewedFields must each be backed by a source, as must every impact,
+// before a record can become reviewed.
+var reviewedFields = []ClaimField{ClaimStartDate, ClaimEndDate}
+
+type Confidence string
+
+const (
+	LowConfidence    Confidence = "low"
+	MediumConfidence Confidence = "medium"
+	HighConfidence   Confidence = "high"
+)
+
+func ConfidenceFromInt16(v int16) Confidence {
+	switch v {
+	case 0:
+		return LowConfidence
+	case 1:
+		return MediumConfidence
+	case 2:
+		return HighConfidence
+	}
+	return ""
+}
+
+func (c Confidence) ToInt16() int16 {
+	switch c {
+	case LowConfidence:
+		return 0
+	case MediumConfidence:
+		return 1
+	case HighConfidence:
+		return 2
+	}
+	return -1
+}
+
+// SourceType is the kind of publication a source is, which decides how it is
+// cited.
+type SourceType string
+
+const (
+	Book    SourceType = "book"
+	Article SourceType = "article"
+	Chapter SourceType = "chapter"
+	Website SourceType = "website"
+	Archive SourceType = "archive"
+	Other   SourceType = "other"
+)
+
+func SourceTypeFromInt16(v int16) SourceType {
+	switch v {
+	case 0:
+		return Book
+	case 1:
+		return Article
+	case 2:
+		return Chapter
+	case 3:
+		return Website
+	case 4:
+		return Archive
+	}
+	return Other
+}
+
+func (t SourceType) ToInt16() int16 {
+	switch t {
+	case Book:
+		return 0
+	case Article:
+		return 1
+	case Chapter:
+		return 2
+	case Website:
+		return 3
+	case Archive:
+		return 4
+	}
+	return 5
+}
+
+// SourceAuthors decodes the authors of a source, which are stored as a JSON
+// array of names written as "Family, Given".
+func SourceAuthors(source model.Source) []string {
+	var authors []string
+	if source.Authors != "" {
+		_ = json.Unmarshal([]byte(source.Authors), &authors)
+	}
+	return authors
+}
+
+// ConfidenceFromNullInt16 reads an optional confidence, nil when unknown.
+func ConfidenceFromNullInt16(v *int16) *Confidence {
+	if v == nil {
+		return nil
+	}
+	confidence := ConfidenceFromInt16(*v)
+	return &confidence
+}
+
+// ConfidenceToNullInt16 stores an optional confidence.
+func ConfidenceToNullInt16(c *Confidence) *int16 {
+	if c == nil {
+		return nil
+	}
+	v := c.ToInt16()
+	return &v
+}
+
+// ClaimEntity is a claim together with the source making it.
+type ClaimEntity struct {
+	model.Claim
+	Source model.Source
+}
+
+func (e ClaimEntity) toResponse() claimResponse {
+	response := claimResponse{
+		ID:            e.ID,
+		SourceID:      e.SourceID,
+		SourceTitle:   e.Source.Title,
+		ImpactID:      e.ImpactID,
+		AlternativeID: e.AlternativeID,
+		Confidence:    ConfidenceFromInt16(e.Confidence),
+		Page:          e.Page,
+		Quote:         e.Quote,
+	}
+	if e.Field != nil {
+		field := ClaimField(*e.Field)
+		response.Field = &field
+	}
+	return response
+}
+
+// CheckProvenance tells which of the fields and impacts a reviewed record
+// needs sources for are not backed by any of the claims.
+func CheckProvenance(claims []ClaimEntity, impacts []ImpactEntity) error {
+	fields := map[ClaimField]bool{}
+	backedImpacts := map[uuid.UUID]bool{}
+	for _, claim := range claims {
+		// Claims backing a competing value do not back the value held
+		if claim.Field != nil && claim.AlternativeID == nil {
+			fields[ClaimField(*claim.Field)] = true
+		}
+		if claim.ImpactID != nil {
+			backedImpacts[*claim.ImpactID] = true
+		}
+	}
+
+	var missing []string
+	for _, field := range reviewedFields {
+		if !fields[field] {
+			missing = append(missing, string(field))
+		}
+	}
+	for _, impact := range impacts {
+		if impact.ID == uuid.Nil || !backedImpacts[impact.ID] {
+			missing = append(missing, fmt.Sprintf("impact %q", impact.Description))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: no source backs %s", common.ErrMissingProvenance, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func alternativeResponses(alternatives []model.AlternativeValue) []alternativeResponse {
+	return lo.Map(alternatives, func(alternative model.AlternativeValue, index int) alternativeResponse {
+		return alternativeResponse{
+			ID:    alternative.ID,
+			Field: ClaimField(alternative.Field),
+			Value: alternative.Value,
+		}
+	})
+}
+
+func claimResponses(claims []ClaimEntity) []claimResponse {
+	return lo.Map(claims, func(claim ClaimEntity, index int) claimResponse {
+		return claim.toResponse()
+	})
+}
@@ -3,15 +3,21 @@ package record
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"historylink/.gen/historylink/public/model"
 	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
 	"log/slog"
 	"reflect"
+	"strings"
+	"time"
 
 	. "github.com/go-jet/jet/v2/postgres"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
 )
 
 func NewRepository(db *sql.DB, logger *slog.Logger) IRecordRepository {
@@ -26,7 +32,20 @@ type IRecordRepository interface {
 	Create(c context.Context, command RecordAggregate) (RecordAggregate, error)
 	Update(c context.Context, command RecordAggregate) error
 	Delete(c context.Context, id uuid.UUID) error
-	GetPaged(c context.Context, limit int, offset int) ([]RecordAggregate, int, error)
+	GetPaged(c context.Context, limit int, offset int, sort RecordSort, filter RecordFilter) ([]RecordAggregate, int, error)
+	GetSources(c context.Context, id uuid.UUID) ([]model.Source, error)
+	GetClaims(c context.Context, id uuid.UUID) ([]ClaimEntity, error)
+	GetAlternatives(c context.Context, id uuid.UUID) ([]model.AlternativeValue, error)
+	GetLinks(c context.Context, id uuid.UUID) ([]LinkedRecord, error)
+	GetByExternalId(c context.Context, scheme int16, value string) (RecordAggregate, error)
+	GetDuplicates(c context.Context, record model.Record, limit int) ([]DuplicateCandidate, error)
+	GetRedirect(c context.Context, id uuid.UUID) (uuid.UUID, error)
+	Merge(c context.Context, survivorId uuid.UUID, duplicateId uuid.UUID) error
+	GetInfluenceState(c context.Context) (model.GraphRevision, error)
+	GetInfluenceInput(c context.Context) (model.GraphRevision, []model.Link, map[uuid.UUID]float64, error)
+	SaveInfluence(c context.Context, state model.GraphRevision, decay float64, influence map[uuid.UUID]float64) error
+	FoldGraphChanges(c context.Context) error
+	GetAttributeSchema(c context.Context, recordType int16) (*string, error)
 }
 type RecordRepository struct {
 	db     *sql.DB
@@ -37,33 +56,72 @@ type ImpactEntity struct {
 	model.Impact
 }
 
+type ExternalIdEntity struct {
+	model.ExternalIdentifier
+}
+
+type LabelEntity struct {
+	model.RecordLabel
+}
+
+type AliasEntity struct {
+	model.RecordAlias
+}
+
 type RecordAggregate struct {
 	model.Record
 	History model.RecordHistory
 
-	Impacts []ImpactEntity
+	Influence   *model.RecordInfluence
+	Impacts     []ImpactEntity
+	ExternalIds []ExternalIdEntity
+	Labels      []LabelEntity
+	Aliases     []AliasEntity
 }
 
+// RecordFilter narrows down the records listed.
+type RecordFilter struct {
+	// Search matches titles, translated titles and aliases containing it,
+	// ignoring case
+	Search string
+	// Terms matches records tagged with every one of these terms, or with a
+	// term narrower than it
+	Terms []uuid.UUID
+	// Attributes matches records whose attributes pass every one of these
+	Attributes []AttributeFilter
+}
+
+// LatestRevision matches the history row holding the current revision of a
+// record, the one written last. Rows written in the same transaction share
+// their timestamp, so they are ordered by the sequence they were written in.
+var LatestRevision = RecordHistory.ID.IN(
+	SELECT(RecordHistory.ID).
+		FROM(RecordHistory).
+		WHERE(RecordHistory.RecordID.EQ(Record.ID)).
+		ORDER_BY(RecordHistory.Seq.DESC()).
+		LIMIT(1),
+)
+
 func (r RecordRepository) GetById(id uuid.UUID) (RecordAggregate, error) {
 	stmt := SELECT(
 		Record.AllColumns,
 		Impact.AllColumns,
+		ExternalIdentifier.AllColumns,
 		RecordHistory.AllColumns,
+		RecordInfluence.AllColumns,
+		RecordLabel.AllColumns,
+		RecordAlias.AllColumns,
 	).FROM(
 		Record.
 			LEFT_JOIN(Impact, Impact.RecordID.EQ(Record.ID)).
-			LEFT_JOIN(
-				RecordHistory,
-				RecordHistory.RecordID.EQ(Record.ID).
-					AND(RecordHistory.UpdatedAt.IN(
-						SELECT(MAX(RecordHistory.UpdatedAt)).
-							FROM(RecordHistory).
-							WHERE(RecordHistory.RecordID.EQ(Record.ID)),
-					)),
-			),
+			LEFT_JOIN(ExternalIdentifier, ExternalIdentifier.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(RecordInfluence, RecordInfluence.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(RecordLabel, RecordLabel.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(RecordAlias, RecordAlias.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(RecordHistory, RecordHistory.RecordID.EQ(Record.ID).AND(LatestRevision)),
 	).WHERE(
 		Record.ID.EQ(UUID(id)),
-	)
+	).ORDER_BY(RecordLabel.Language, RecordAlias.Name)
 
 	var dest RecordAggregate
 	err := stmt.Query(r.db, &dest)
@@ -81,6 +139,10 @@ func (r RecordRepository) Create(c context.Context, command RecordAggregate) (Re
 	}
 	defer tx.Rollback()
 
+	if err = common.SetActor(c, tx); err != nil {
+		return RecordAggregate{}, err
+	}
+
 	var result RecordAggregate
 
 	recordStmt := Record.INSERT(Record.MutableColumns).
@@ -108,6 +170,40 @@ func (r RecordRepository) Create(c context.Context, command RecordAggregate) (Re
 		result.Impacts = impacts
 	}
 
+	for i := range command.ExternalIds {
+		command.ExternalIds[i].RecordID = result.ID
+	}
+
+	if len(command.ExternalIds) > 0 {
+		externalIdStmt := ExternalIdentifier.INSERT(ExternalIdentifier.MutableColumns).
+			MODELS(command.ExternalIds).
+			RETURNING(ExternalIdentifier.AllColumns)
+
+		var externalIds []ExternalIdEntity
+		if err = externalIdStmt.Query(tx, &externalIds); err != nil {
+			return RecordAggregate{}, externalIdWriteError(err)
+		}
+
+		result.ExternalIds = externalIds
+	}
+
+	if result.Labels, err = r.insertLabels(c, tx, result.ID, command.Labels); err != nil {
+		return RecordAggregate{}, err
+	}
+	if result.Aliases, err = r.insertAliases(c, tx, result.ID, command.Aliases); err != nil {
+		return RecordAggregate{}, err
+	}
+
+	// The history trigger has written the first revision of the record
+	historyStmt := SELECT(RecordHistory.AllColumns).
+		FROM(RecordHistory).
+		WHERE(RecordHistory.RecordID.EQ(UUID(result.ID))).
+		ORDER_BY(RecordHistory.Seq.DESC()).
+		LIMIT(1)
+	if err = historyStmt.QueryContext(c, tx, &result.History); err != nil {
+		return RecordAggregate{}, fmt.Errorf("error getting record history: %w", err)
+	}
+
 	if err = tx.Commit(); err != nil {
 		return RecordAggregate{}, fmt.Errorf("error committing transaction: %w", err)
 	}
@@ -121,16 +217,28 @@ func (a RecordAggregate) Equal(b RecordAggregate) bool {
 		reflect.DeepEqual(a.Location, b.Location) &&
 		reflect.DeepEqual(a.Significance, b.Significance) &&
 		a.URL == b.URL &&
-		a.StartDate.Equal(*b.StartDate) &&
-		a.EndDate.Equal(*b.EndDate) &&
+		equalDates(a.StartDate, b.StartDate) &&
+		equalDates(a.EndDate, b.EndDate) &&
 		a.Type == b.Type &&
-		a.Status == b.Status
+		a.Status == b.Status &&
+		reflect.DeepEqual(a.Confidence, b.Confidence) &&
+		reflect.DeepEqual(a.attributes(), b.attributes())
+}
+
+// equalDates compares optional dates by the instant they hold, as records
+// imported from Wikidata may lack a start or end date.
+func equalDates(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
 }
 
 func (a ImpactEntity) Equal(b ImpactEntity) bool {
 	return a.Description == b.Description &&
 		a.Value == b.Value &&
-		a.Category == b.Category
+		a.Category == b.Category &&
+		reflect.DeepEqual(a.Confidence, b.Confidence)
 }
 
 func (r RecordRepository) Update(c context.Context, command RecordAggregate) error {
@@ -140,6 +248,10 @@ func (r RecordRepository) Update(c context.Context, command RecordAggregate) err
 	}
 	defer tx.Rollback()
 
+	if err = common.SetActor(c, tx); err != nil {
+		return err
+	}
+
 	// Handle impacts - first get existing impacts
 	existingImpactsStmt := SELECT(Impact.AllColumns).
 		FROM(Impact).
@@ -174,7 +286,7 @@ func (r RecordRepository) Update(c context.Context, command RecordAggregate) err
 		if existingImpact, exists := existingImpactsMap[id]; exists {
 			// Only update if something changed
 			if !newImpact.Equal(existingImpact) {
-				updateStmt := Impact.UPDATE(Impact.Description, Impact.Value, Impact.Category).
+				updateStmt := Impact.UPDATE(Impact.Description, Impact.Value, Impact.Category, Impact.Confidence).
 					MODEL(newImpact).
 					WHERE(Impact.ID.EQ(UUID(id)))
 
@@ -203,7 +315,7 @@ func (r RecordRepository) Update(c context.Context, command RecordAggregate) err
 		if impact.ID == uuid.Nil {
 			impact.RecordID = command.ID
 
-			insertStmt := Impact.INSERT(Impact.Description, Impact.Value, Impact.Category, Impact.RecordID).
+			insertStmt := Impact.INSERT(Impact.Description, Impact.Value, Impact.Category, Impact.Confidence, Impact.RecordID).
 				MODEL(impact)
 
 			_, err = insertStmt.Exec(tx)
@@ -213,6 +325,31 @@ func (r RecordRepository) Update(c context.Context, command RecordAggregate) err
 		}
 	}
 
+	// A nil slice means the identifiers were not part of the update
+	if command.ExternalIds != nil {
+		if err = r.replaceExternalIds(c, tx, command.ID, command.ExternalIds); err != nil {
+			return err
+		}
+	}
+
+	if command.Labels != nil {
+		if _, err = RecordLabel.DELETE().WHERE(RecordLabel.RecordID.EQ(UUID(command.ID))).ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error deleting labels: %w", err)
+		}
+		if _, err = r.insertLabels(c, tx, command.ID, command.Labels); err != nil {
+			return err
+		}
+	}
+
+	if command.Aliases != nil {
+		if _, err = RecordAlias.DELETE().WHERE(RecordAlias.RecordID.EQ(UUID(command.ID))).ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error deleting aliases: %w", err)
+		}
+		if _, err = r.insertAliases(c, tx, command.ID, command.Aliases); err != nil {
+			return err
+		}
+	}
+
 	stmt := SELECT(Record.AllColumns).
 		FROM(Record).
 		WHERE(Record.ID.EQ(UUID(command.ID)))
@@ -235,7 +372,7 @@ func (r RecordRepository) Update(c context.Context, command RecordAggregate) err
 	}
 
 	// Update the record
-	recordStmt := Record.UPDATE(Record.Title, Record.Description, Record.Location, Record.Significance, Record.URL, Record.StartDate, Record.EndDate, Record.Type, Record.Status).
+	recordStmt := Record.UPDATE(Record.Title, Record.Description, Record.Location, Record.Significance, Record.URL, Record.StartDate, Record.EndDate, Record.Type, Record.Status, Record.Attributes, Record.Confidence).
 		MODEL(command.Record).
 		WHERE(Record.ID.EQ(UUID(command.ID)))
 
@@ -251,6 +388,734 @@ func (r RecordRepository) Update(c context.Context, command RecordAggregate) err
 	return nil
 }
 
+func (r RecordRepository) insertLabels(c context.Context, tx *sql.Tx, id uuid.UUID, labels []LabelEntity) ([]LabelEntity, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	for i := range labels {
+		labels[i].RecordID = id
+	}
+
+	stmt := RecordLabel.INSERT(RecordLabel.MutableColumns).
+		MODELS(labels).
+		RETURNING(RecordLabel.AllColumns)
+
+	var dest []LabelEntity
+	if err := stmt.QueryContext(c, tx, &dest); err != nil {
+		return nil, fmt.Errorf("error inserting labels: %w", err)
+	}
+	return dest, nil
+}
+
+func (r RecordRepository) insertAliases(c context.Context, tx *sql.Tx, id uuid.UUID, aliases []AliasEntity) ([]AliasEntity, error) {
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+	for i := range aliases {
+		aliases[i].RecordID = id
+	}
+
+	stmt := RecordAlias.INSERT(RecordAlias.MutableColumns).
+		MODELS(aliases).
+		RETURNING(RecordAlias.AllColumns)
+
+	var dest []AliasEntity
+	if err := stmt.QueryContext(c, tx, &dest); err != nil {
+		return nil, fmt.Errorf("error inserting aliases: %w", err)
+	}
+	return dest, nil
+}
+
+// replaceExternalIds makes the identifiers of a record match the given set,
+// keeping the rows of identifiers that did not change.
+func (r RecordRepository) replaceExternalIds(c context.Context, tx *sql.Tx, id uuid.UUID, externalIds []ExternalIdEntity) error {
+	stmt := SELECT(ExternalIdentifier.AllColumns).
+		FROM(ExternalIdentifier).
+		WHERE(ExternalIdentifier.RecordID.EQ(UUID(id)))
+
+	var existing []ExternalIdEntity
+	if err := stmt.QueryContext(c, tx, &existing); err != nil {
+		return fmt.Errorf("error getting existing external identifiers: %w", err)
+	}
+
+	wanted := make(map[externalIdKey]bool)
+	for _, externalId := range externalIds {
+		wanted[externalId.key()] = true
+	}
+
+	for _, externalId := range existing {
+		if wanted[externalId.key()] {
+			delete(wanted, externalId.key())
+			continue
+		}
+		deleteStmt := ExternalIdentifier.DELETE().WHERE(ExternalIdentifier.ID.EQ(UUID(externalId.ID)))
+		if _, err := deleteStmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error deleting external identifier: %w", err)
+		}
+	}
+
+	for _, externalId := range externalIds {
+		if !wanted[externalId.key()] {
+			continue
+		}
+		externalId.RecordID = id
+
+		insertStmt := ExternalIdentifier.INSERT(ExternalIdentifier.MutableColumns).
+			MODEL(externalId)
+		if _, err := insertStmt.ExecContext(c, tx); err != nil {
+			return externalIdWriteError(err)
+		}
+	}
+
+	return nil
+}
+
+type externalIdKey struct {
+	scheme int16
+	value  string
+}
+
+func (e ExternalIdEntity) key() externalIdKey {
+	return externalIdKey{scheme: e.Scheme, value: e.Value}
+}
+
+// externalIdWriteError reports a clash with the identifier of another record as
+// ErrExternalIdAlreadyExists, for writes racing past the check in the service.
+func externalIdWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "external_identifier_scheme_value_key" {
+		return common.ErrExternalIdAlreadyExists
+	}
+	return fmt.Errorf("error saving external identifier: %w", err)
+}
+
+func (r RecordRepository) GetByExternalId(c context.Context, scheme int16, value string) (RecordAggregate, error) {
+	stmt := SELECT(ExternalIdentifier.AllColumns).
+		FROM(ExternalIdentifier).
+		WHERE(ExternalIdentifier.Scheme.EQ(Int16(scheme)).
+			AND(ExternalIdentifier.Value.EQ(String(value))))
+
+	var dest []model.ExternalIdentifier
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return RecordAggregate{}, fmt.Errorf("error getting external identifier: %w", err)
+	}
+	if len(dest) == 0 {
+		return RecordAggregate{}, common.ErrRecordNotFound
+	}
+
+	return r.GetById(dest[0].RecordID)
+}
+
+// DuplicateCandidate is a record that might describe the same thing as
+// another one, with the trigram similarity of their titles as score.
+type DuplicateCandidate struct {
+	model.Record
+
+	Score float64
+}
+
+// GetDuplicates finds records of the same type whose title is similar to the
+// one of the given record and whose dates overlap with it. Missing dates are
+// treated as overlapping, and removed records are left out.
+func (r RecordRepository) GetDuplicates(c context.Context, record model.Record, limit int) ([]DuplicateCandidate, error) {
+	title := String(record.Title)
+	score := FloatExp(Func("similarity", Record.Title, title))
+
+	condition := BoolExp(BinaryOperator(Record.Title, title, "%")).
+		AND(Record.Type.EQ(Int16(record.Type))).
+		AND(Record.Status.NOT_EQ(Int16(Removed.ToInt16()))).
+		AND(Record.ID.NOT_EQ(UUID(record.ID)))
+	if record.StartDate != nil {
+		condition = condition.AND(Record.EndDate.IS_NULL().OR(Record.EndDate.GT_EQ(TimestampT(*record.StartDate))))
+	}
+	if record.EndDate != nil {
+		condition = condition.AND(Record.StartDate.IS_NULL().OR(Record.StartDate.LT_EQ(TimestampT(*record.EndDate))))
+	}
+
+	stmt := SELECT(
+		Record.AllColumns,
+		score.AS("duplicate_candidate.score"),
+	).FROM(
+		Record,
+	).WHERE(
+		condition,
+	).ORDER_BY(
+		score.DESC(),
+	).LIMIT(int64(limit))
+
+	var dest []DuplicateCandidate
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting duplicate candidates: %w", err)
+	}
+	return dest, nil
+}
+
+// GetRedirect returns the record another record was merged into, or
+// ErrRecordNotFound when the record was never merged.
+func (r RecordRepository) GetRedirect(c context.Context, id uuid.UUID) (uuid.UUID, error) {
+	stmt := SELECT(RecordRedirect.AllColumns).
+		FROM(RecordRedirect).
+		WHERE(RecordRedirect.OldRecordID.EQ(UUID(id)))
+
+	var dest []model.RecordRedirect
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return uuid.Nil, fmt.Errorf("error getting redirect: %w", err)
+	}
+	if len(dest) == 0 {
+		return uuid.Nil, common.ErrRecordNotFound
+	}
+	return dest[0].RecordID, nil
+}
+
+// Merge moves everything attached to the duplicate onto the survivor and
+// redirects the duplicate to it. The duplicate itself is kept as a removed
+// record, so its history stays available. Neither record may have been
+// merged or removed already.
+func (r RecordRepository) Merge(c context.Context, survivorId uuid.UUID, duplicateId uuid.UUID) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = common.SetActor(c, tx); err != nil {
+		return err
+	}
+
+	// Lock both records so links cannot be added to the duplicate meanwhile,
+	// and so concurrent merges of either record wait for this one and then
+	// see it merged
+	lockStmt := SELECT(Record.ID, Record.Status).
+		FROM(Record).
+		WHERE(Record.ID.IN(UUID(survivorId), UUID(duplicateId))).
+		FOR(UPDATE())
+
+	var locked []model.Record
+	if err = lockStmt.QueryContext(c, tx, &locked); err != nil {
+		return fmt.Errorf("error locking records: %w", err)
+	}
+	if len(locked) != 2 {
+		return common.ErrRecordNotFound
+	}
+
+	redirectedStmt := SELECT(RecordRedirect.AllColumns).
+		FROM(RecordRedirect).
+		WHERE(RecordRedirect.OldRecordID.IN(UUID(survivorId), UUID(duplicateId)))
+
+	var redirected []model.RecordRedirect
+	if err = redirectedStmt.QueryContext(c, tx, &redirected); err != nil {
+		return fmt.Errorf("error getting redirects: %w", err)
+	}
+	if len(redirected) > 0 {
+		return common.ErrRecordAlreadyMerged
+	}
+	for _, record := range locked {
+		if record.Status == Removed.ToInt16() {
+			return common.ErrRecordRemoved
+		}
+	}
+
+	if err = r.mergeLinks(c, tx, survivorId, duplicateId); err != nil {
+		return err
+	}
+	if err = r.mergeArcMembers(c, tx, survivorId, duplicateId); err != nil {
+		return err
+	}
+	if err = r.mergeInvolvements(c, tx, survivorId, duplicateId); err != nil {
+		return err
+	}
+	if err = r.mergePersonProfile(c, tx, survivorId, duplicateId); err != nil {
+		return err
+	}
+
+	impactStmt := Impact.UPDATE(Impact.RecordID).
+		SET(UUID(survivorId)).
+		WHERE(Impact.RecordID.EQ(UUID(duplicateId)))
+	if _, err = impactStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error moving impacts: %w", err)
+	}
+
+	// Claims about fields of the duplicate do not back the values of the
+	// survivor, unlike claims about the impacts and competing values moved
+	// along
+	if err = r.mergeAlternatives(c, tx, survivorId, duplicateId); err != nil {
+		return err
+	}
+	claimStmt := Claim.DELETE().
+		WHERE(
+			Claim.Field.IS_NOT_NULL().
+				AND(Claim.AlternativeID.IS_NULL()).
+				AND(Claim.SourceID.IN(
+					SELECT(Source.ID).
+						FROM(Source).
+						WHERE(Source.RecordID.EQ(UUID(duplicateId))),
+				)),
+		)
+	if _, err = claimStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error removing claims: %w", err)
+	}
+
+	sourceStmt := Source.UPDATE(Source.RecordID).
+		SET(UUID(survivorId)).
+		WHERE(Source.RecordID.EQ(UUID(duplicateId)))
+	if _, err = sourceStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error moving sources: %w", err)
+	}
+
+	externalIdStmt := ExternalIdentifier.UPDATE(ExternalIdentifier.RecordID).
+		SET(UUID(survivorId)).
+		WHERE(ExternalIdentifier.RecordID.EQ(UUID(duplicateId)))
+	if _, err = externalIdStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error moving external identifiers: %w", err)
+	}
+
+	termStmt := RecordTerm.INSERT(RecordTerm.RecordID, RecordTerm.TermID).
+		QUERY(
+			SELECT(CAST(UUID(survivorId)).AS("uuid"), RecordTerm.TermID).
+				FROM(RecordTerm).
+				WHERE(RecordTerm.RecordID.EQ(UUID(duplicateId))),
+		).
+		ON_CONFLICT(RecordTerm.RecordID, RecordTerm.TermID).
+		DO_NOTHING()
+	if _, err = termStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error copying terms: %w", err)
+	}
+
+	// The survivor keeps its own label in a language both have one in
+	labelStmt := RecordLabel.INSERT(RecordLabel.RecordID, RecordLabel.Language, RecordLabel.Title, RecordLabel.Description).
+		QUERY(
+			SELECT(CAST(UUID(survivorId)).AS("uuid"), RecordLabel.Language, RecordLabel.Title, RecordLabel.Description).
+				FROM(RecordLabel).
+				WHERE(RecordLabel.RecordID.EQ(UUID(duplicateId))),
+		).
+		ON_CONFLICT(RecordLabel.RecordID, RecordLabel.Language).
+		DO_NOTHING()
+	if _, err = labelStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error copying labels: %w", err)
+	}
+
+	aliasStmt := RecordAlias.INSERT(RecordAlias.RecordID, RecordAlias.Name, RecordAlias.Language).
+		QUERY(
+			SELECT(CAST(UUID(survivorId)).AS("uuid"), RecordAlias.Name, RecordAlias.Language).
+				FROM(RecordAlias).
+				WHERE(RecordAlias.RecordID.EQ(UUID(duplicateId))),
+		).
+		ON_CONFLICT(RecordAlias.RecordID, RecordAlias.Name).
+		DO_NOTHING()
+	if _, err = aliasStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error copying aliases: %w", err)
+	}
+
+	commentStmt := Comment.UPDATE(Comment.RecordID).
+		SET(UUID(survivorId)).
+		WHERE(Comment.RecordID.EQ(UUID(duplicateId)))
+	if _, err = commentStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error moving comments: %w", err)
+	}
+
+	// Records merged into the duplicate earlier now redirect to the survivor
+	redirectsStmt := RecordRedirect.UPDATE(RecordRedirect.RecordID).
+		SET(UUID(survivorId)).
+		WHERE(RecordRedirect.RecordID.EQ(UUID(duplicateId)))
+	if _, err = redirectsStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error updating redirects: %w", err)
+	}
+
+	redirectStmt := RecordRedirect.INSERT(RecordRedirect.OldRecordID, RecordRedirect.RecordID).
+		VALUES(UUID(duplicateId), UUID(survivorId))
+	if _, err = redirectStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error creating redirect: %w", err)
+	}
+
+	statusStmt := Record.UPDATE(Record.Status).
+		SET(Int16(Removed.ToInt16())).
+		WHERE(Record.ID.EQ(UUID(duplicateId)))
+	if _, err = statusStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error removing duplicate: %w", err)
+	}
+
+	// Watches move once the duplicate is removed, so its watchers still hear
+	// of the merge
+	watchStmt := Watch.INSERT(Watch.UserName, Watch.RecordID, Watch.CreatedAt).
+		QUERY(
+			SELECT(Watch.UserName, CAST(UUID(survivorId)).AS("uuid"), Watch.CreatedAt).
+				FROM(Watch).
+				WHERE(Watch.RecordID.EQ(UUID(duplicateId))),
+		).
+		ON_CONFLICT(Watch.UserName, Watch.RecordID).
+		DO_NOTHING()
+	if _, err = watchStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error copying watches: %w", err)
+	}
+
+	unwatchStmt := Watch.DELETE().
+		WHERE(Watch.RecordID.EQ(UUID(duplicateId)))
+	if _, err = unwatchStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error deleting watches: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// mergeLinks moves the links of the duplicate onto the survivor. Links
+// between the two would become self links and are dropped, and links to a
+// record the survivor is already linked to are collapsed into one, keeping
+// the highest strength. Comments on dropped links move to the survivor and
+// comments on collapsed links to the link they are collapsed into.
+func (r RecordRepository) mergeLinks(c context.Context, tx *sql.Tx, survivorId uuid.UUID, duplicateId uuid.UUID) error {
+	stmt := SELECT(Link.AllColumns).
+		FROM(Link).
+		WHERE(Link.RecordID.IN(UUID(survivorId), UUID(duplicateId)).
+			OR(Link.RecordId2.IN(UUID(survivorId), UUID(duplicateId))))
+
+	var links []model.Link
+	if err := stmt.QueryContext(c, tx, &links); err != nil {
+		return fmt.Errorf("error getting links: %w", err)
+	}
+
+	for _, merge := range planLinkMerge(links, survivorId, duplicateId) {
+		link := merge.Link
+		if merge.Into == nil && !merge.Dropped {
+			column := Link.RecordID
+			if link.RecordId2 == duplicateId {
+				column = Link.RecordId2
+			}
+			updateStmt := Link.UPDATE(column).
+				SET(UUID(survivorId)).
+				WHERE(Link.ID.EQ(UUID(link.ID)))
+			if _, err := updateStmt.ExecContext(c, tx); err != nil {
+				return fmt.Errorf("error moving link: %w", err)
+			}
+			continue
+		}
+
+		// Comments on a dropped link are about the survivor now
+		var commentStmt Statement = Comment.UPDATE(Comment.RecordID, Comment.LinkID).
+			SET(UUID(survivorId), NULL).
+			WHERE(Comment.LinkID.EQ(UUID(link.ID)))
+		if merge.Into != nil {
+			updateStmt := Link.UPDATE(Link.Strength).
+				SET(Int16(link.Strength)).
+				WHERE(Link.ID.EQ(UUID(*merge.Into)).AND(Link.Strength.LT(Int16(link.Strength))))
+			if _, err := updateStmt.ExecContext(c, tx); err != nil {
+				return fmt.Errorf("error updating link: %w", err)
+			}
+
+			commentStmt = Comment.UPDATE(Comment.LinkID).
+				SET(UUID(*merge.Into)).
+				WHERE(Comment.LinkID.EQ(UUID(link.ID)))
+		}
+		if _, err := commentStmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error moving link comments: %w", err)
+		}
+
+		deleteStmt := Link.DELETE().WHERE(Link.ID.EQ(UUID(link.ID)))
+		if _, err := deleteStmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error deleting link: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// linkMerge is what becomes of a link of the duplicate when merging: it is
+// moved onto the survivor, collapsed into a link of the survivor to the same
+// record, or dropped as it joins the two.
+type linkMerge struct {
+	Link    model.Link
+	Into    *uuid.UUID
+	Dropped bool
+}
+
+// planLinkMerge decides what becomes of each link of the duplicate, in the
+// order given. A link is collapsed into the survivor's own link to the same
+// record, or into a link of the duplicate moved to that record before it.
+func planLinkMerge(links []model.Link, survivorId uuid.UUID, duplicateId uuid.UUID) []linkMerge {
+	other := func(link model.Link, id uuid.UUID) uuid.UUID {
+		if link.RecordID == id {
+			return link.RecordId2
+		}
+		return link.RecordID
+	}
+
+	survivorLinks := make(map[uuid.UUID]uuid.UUID)
+	for _, link := range links {
+		if link.RecordID == survivorId || link.RecordId2 == survivorId {
+			if target := other(link, survivorId); target != duplicateId {
+				survivorLinks[target] = link.ID
+			}
+		}
+	}
+
+	var merges []linkMerge
+	for _, link := range links {
+		if link.RecordID != duplicateId && link.RecordId2 != duplicateId {
+			continue
+		}
+		target := other(link, duplicateId)
+
+		if target == survivorId {
+			merges = append(merges, linkMerge{Link: link, Dropped: true})
+		} else if existing, exists := survivorLinks[target]; exists {
+			merges = append(merges, linkMerge{Link: link, Into: &existing})
+		} else {
+			merges = append(merges, linkMerge{Link: link})
+			survivorLinks[target] = link.ID
+		}
+	}
+	return merges
+}
+
+// mergeAlternatives moves the competing values of the duplicate onto the
+// survivor along with the claims backing them. Values the survivor already
+// has for the same field are kept, and the claims backing the duplicate's
+// copy move to them.
+func (r RecordRepository) mergeAlternatives(c context.Context, tx *sql.Tx, survivorId uuid.UUID, duplicateId uuid.UUID) error {
+	existing := AlternativeValue.AS("existing")
+	sameValue := existing.RecordID.EQ(UUID(survivorId)).
+		AND(existing.Field.EQ(AlternativeValue.Field)).
+		AND(existing.Value.EQ(AlternativeValue.Value))
+
+	moveStmt := AlternativeValue.UPDATE(AlternativeValue.RecordID).
+		SET(UUID(survivorId)).
+		WHERE(AlternativeValue.RecordID.EQ(UUID(duplicateId)).AND(NOT(EXISTS(
+			SELECT(existing.ID).
+				FROM(existing).
+				WHERE(sameValue),
+		))))
+	if _, err := moveStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error moving alternative values: %w", err)
+	}
+
+	// The values left on the duplicate are the ones the survivor has too
+	claimStmt := Claim.UPDATE(Claim.AlternativeID).
+		SET(
+			SELECT(existing.ID).
+				FROM(existing.INNER_JOIN(AlternativeValue, sameValue)).
+				WHERE(AlternativeValue.ID.EQ(Claim.AlternativeID)),
+		).
+		WHERE(Claim.AlternativeID.IN(
+			SELECT(AlternativeValue.ID).
+				FROM(AlternativeValue).
+				WHERE(AlternativeValue.RecordID.EQ(UUID(duplicateId))),
+		))
+	if _, err := claimStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error moving claims: %w", err)
+	}
+
+	deleteStmt := AlternativeValue.DELETE().
+		WHERE(AlternativeValue.RecordID.EQ(UUID(duplicateId)))
+	if _, err := deleteStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error removing alternative values: %w", err)
+	}
+
+	return nil
+}
+
+// mergeArcMembers moves the arc memberships of the duplicate onto the
+// survivor: the arcs the duplicate is in and, for arcs, the records in it,
+// which join the survivor after its own members. Memberships the survivor
+// already has are kept as they are, and memberships that would put the
+// survivor inside itself are dropped.
+func (r RecordRepository) mergeArcMembers(c context.Context, tx *sql.Tx, survivorId uuid.UUID, duplicateId uuid.UUID) error {
+	// Membership changes run one at a time, as the arcs do for their cycle
+	// check
+	if _, err := ArcMember.LOCK().IN(LOCK_SHARE_ROW_EXCLUSIVE).ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error locking arc members: %w", err)
+	}
+
+	stmt := SELECT(ArcMember.AllColumns).
+		FROM(ArcMember).
+		WHERE(ArcMember.ArcID.IN(UUID(survivorId), UUID(duplicateId)).
+			OR(ArcMember.RecordID.IN(UUID(survivorId), UUID(duplicateId)))).
+		ORDER_BY(ArcMember.ArcID, ArcMember.Position)
+
+	var members []model.ArcMember
+	if err := stmt.QueryContext(c, tx, &members); err != nil {
+		return fmt.Errorf("error getting arc members: %w", err)
+	}
+
+	survivorArcs := map[uuid.UUID]bool{}
+	survivorMembers := map[uuid.UUID]bool{}
+	var lastPosition int32
+	for _, member := range members {
+		if member.RecordID == survivorId {
+			survivorArcs[member.ArcID] = true
+		}
+		if member.ArcID == survivorId {
+			survivorMembers[member.RecordID] = true
+			lastPosition = max(lastPosition, member.Position)
+		}
+	}
+
+	for _, member := range members {
+		membership := ArcMember.ArcID.EQ(UUID(member.ArcID)).AND(ArcMember.RecordID.EQ(UUID(member.RecordID)))
+
+		var moveStmt Statement
+		switch {
+		case member.RecordID == duplicateId && member.ArcID != survivorId && !survivorArcs[member.ArcID]:
+			survivorArcs[member.ArcID] = true
+			moveStmt = ArcMember.UPDATE(ArcMember.RecordID).
+				SET(UUID(survivorId)).
+				WHERE(membership)
+		case member.ArcID == duplicateId && member.RecordID != survivorId && !survivorMembers[member.RecordID]:
+			survivorMembers[member.RecordID] = true
+			lastPosition++
+			moveStmt = ArcMember.UPDATE(ArcMember.ArcID, ArcMember.Position).
+				SET(UUID(survivorId), Int32(lastPosition)).
+				WHERE(membership)
+		case member.RecordID == duplicateId || member.ArcID == duplicateId:
+			moveStmt = ArcMember.DELETE().WHERE(membership)
+		default:
+			continue
+		}
+		if _, err := moveStmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error moving arc member: %w", err)
+		}
+	}
+
+	// Walk down from the members of the survivor; reaching it means it ended
+	// up inside itself
+	seen := map[uuid.UUID]bool{}
+	for level := []uuid.UUID{survivorId}; len(level) > 0; {
+		childStmt := SELECT(ArcMember.RecordID).
+			FROM(ArcMember).
+			WHERE(ArcMember.ArcID.IN(lo.Map(level, func(id uuid.UUID, index int) Expression { return UUID(id) })...))
+
+		var children []model.ArcMember
+		if err := childStmt.QueryContext(c, tx, &children); err != nil {
+			return fmt.Errorf("error getting arc members: %w", err)
+		}
+
+		level = nil
+		for _, child := range children {
+			if child.RecordID == survivorId {
+				return common.ErrArcCycle
+			}
+			if !seen[child.RecordID] {
+				seen[child.RecordID] = true
+				level = append(level, child.RecordID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// mergeInvolvements moves the involvements of the duplicate onto the
+// survivor, both those in the duplicate and, for people, those of the
+// duplicate itself. Involvements of the two in each other would involve the
+// survivor in itself and are dropped, as are those the survivor already has
+// in the same role.
+func (r RecordRepository) mergeInvolvements(c context.Context, tx *sql.Tx, survivorId uuid.UUID, duplicateId uuid.UUID) error {
+	ids := []Expression{UUID(survivorId), UUID(duplicateId)}
+	selfStmt := Involvement.DELETE().
+		WHERE(Involvement.PersonID.IN(ids...).AND(Involvement.RecordID.IN(ids...)))
+	if _, err := selfStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error removing involvements: %w", err)
+	}
+
+	// Involvements of the duplicate the survivor already has in the same role
+	// are dropped, moving the others
+	existing := Involvement.AS("existing")
+	moveStmts := []Statement{
+		Involvement.DELETE().
+			WHERE(Involvement.RecordID.EQ(UUID(duplicateId)).AND(EXISTS(
+				SELECT(existing.ID).
+					FROM(existing).
+					WHERE(existing.RecordID.EQ(UUID(survivorId)).
+						AND(existing.PersonID.EQ(Involvement.PersonID)).
+						AND(existing.Role.EQ(Involvement.Role))),
+			))),
+		Involvement.UPDATE(Involvement.RecordID).
+			SET(UUID(survivorId)).
+			WHERE(Involvement.RecordID.EQ(UUID(duplicateId))),
+		Involvement.DELETE().
+			WHERE(Involvement.PersonID.EQ(UUID(duplicateId)).AND(EXISTS(
+				SELECT(existing.ID).
+					FROM(existing).
+					WHERE(existing.PersonID.EQ(UUID(survivorId)).
+						AND(existing.RecordID.EQ(Involvement.RecordID)).
+						AND(existing.Role.EQ(Involvement.Role))),
+			))),
+		Involvement.UPDATE(Involvement.PersonID).
+			SET(UUID(survivorId)).
+			WHERE(Involvement.PersonID.EQ(UUID(duplicateId))),
+	}
+	for _, stmt := range moveStmts {
+		if _, err := stmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error moving involvements: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// mergePersonProfile moves the person attributes of the duplicate onto the
+// survivor. Attributes the survivor already has are kept, and alternative
+// names and occupations of both are combined.
+func (r RecordRepository) mergePersonProfile(c context.Context, tx *sql.Tx, survivorId uuid.UUID, duplicateId uuid.UUID) error {
+	profileStmt := PersonProfile.INSERT(PersonProfile.AllColumns).
+		QUERY(
+			SELECT(
+				CAST(UUID(survivorId)).AS("uuid"),
+				PersonProfile.BirthDate,
+				PersonProfile.BirthPlace,
+				PersonProfile.DeathDate,
+				PersonProfile.DeathPlace,
+			).
+				FROM(PersonProfile).
+				WHERE(PersonProfile.RecordID.EQ(UUID(duplicateId))),
+		).
+		ON_CONFLICT(PersonProfile.RecordID).
+		DO_UPDATE(SET(
+			PersonProfile.BirthDate.SET(TimestampExp(COALESCE(PersonProfile.BirthDate, PersonProfile.EXCLUDED.BirthDate))),
+			PersonProfile.BirthPlace.SET(StringExp(COALESCE(PersonProfile.BirthPlace, PersonProfile.EXCLUDED.BirthPlace))),
+			PersonProfile.DeathDate.SET(TimestampExp(COALESCE(PersonProfile.DeathDate, PersonProfile.EXCLUDED.DeathDate))),
+			PersonProfile.DeathPlace.SET(StringExp(COALESCE(PersonProfile.DeathPlace, PersonProfile.EXCLUDED.DeathPlace))),
+		))
+	if _, err := profileStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error moving person: %w", err)
+	}
+
+	nameStmt := PersonName.INSERT(PersonName.RecordID, PersonName.Name).
+		QUERY(
+			SELECT(CAST(UUID(survivorId)).AS("uuid"), PersonName.Name).
+				FROM(PersonName).
+				WHERE(PersonName.RecordID.EQ(UUID(duplicateId))),
+		).
+		ON_CONFLICT(PersonName.RecordID, PersonName.Name).
+		DO_NOTHING()
+	if _, err := nameStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error copying alternative names: %w", err)
+	}
+
+	occupationStmt := PersonOccupation.INSERT(PersonOccupation.RecordID, PersonOccupation.Occupation).
+		QUERY(
+			SELECT(CAST(UUID(survivorId)).AS("uuid"), PersonOccupation.Occupation).
+				FROM(PersonOccupation).
+				WHERE(PersonOccupation.RecordID.EQ(UUID(duplicateId))),
+		).
+		ON_CONFLICT(PersonOccupation.RecordID, PersonOccupation.Occupation).
+		DO_NOTHING()
+	if _, err := occupationStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error copying occupations: %w", err)
+	}
+
+	// Names and occupations of the duplicate go along with its attributes
+	deleteStmt := PersonProfile.DELETE().
+		WHERE(PersonProfile.RecordID.EQ(UUID(duplicateId)))
+	if _, err := deleteStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error removing person: %w", err)
+	}
+
+	return nil
+}
+
 func (r RecordRepository) Delete(c context.Context, id uuid.UUID) error {
 	tx, err := r.db.BeginTx(c, nil)
 	if err != nil {
@@ -275,24 +1140,54 @@ type Count struct {
 	C int
 }
 
-func (r RecordRepository) GetPaged(c context.Context, limit int, offset int) ([]RecordAggregate, int, error) {
+func (r RecordRepository) GetPaged(c context.Context, limit int, offset int, sort RecordSort, filter RecordFilter) ([]RecordAggregate, int, error) {
+	narrower, err := r.getNarrowerTerms(c, filter.Terms)
+	if err != nil {
+		return nil, 0, err
+	}
+	condition := filter.condition(narrower)
+
 	var total Count
-	stmt := SELECT(COUNT(Record.ID).AS("count.c")).FROM(Record)
+	stmt := SELECT(COUNT(Record.ID).AS("count.c")).FROM(Record).WHERE(condition)
 
-	err := stmt.Query(r.db, &total)
+	err = stmt.Query(r.db, &total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error getting total count: %w", err)
 	}
 
+	// Page over records before joining, as the joins return a row for every
+	// impact and identifier of a record
+	order := []OrderByClause{Record.ID}
+	if sort == SortByInfluence {
+		order = []OrderByClause{COALESCE(RecordInfluence.Influence, Double(0)).DESC(), Record.ID}
+	}
+
+	page := SELECT(Record.ID).
+		FROM(Record.LEFT_JOIN(RecordInfluence, RecordInfluence.RecordID.EQ(Record.ID))).
+		WHERE(condition).
+		ORDER_BY(order...).
+		LIMIT(int64(limit)).
+		OFFSET(int64(offset))
+
 	stmt = SELECT(
 		Record.AllColumns,
 		Impact.AllColumns,
+		ExternalIdentifier.AllColumns,
 		RecordHistory.AllColumns,
+		RecordInfluence.AllColumns,
+		RecordLabel.AllColumns,
+		RecordAlias.AllColumns,
 	).FROM(
 		Record.
 			LEFT_JOIN(Impact, Impact.RecordID.EQ(Record.ID)).
-			LEFT_JOIN(RecordHistory, RecordHistory.RecordID.EQ(Record.ID)),
-	).LIMIT(int64(limit)).OFFSET(int64(offset))
+			LEFT_JOIN(ExternalIdentifier, ExternalIdentifier.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(RecordHistory, RecordHistory.RecordID.EQ(Record.ID).AND(LatestRevision)).
+			LEFT_JOIN(RecordInfluence, RecordInfluence.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(RecordLabel, RecordLabel.RecordID.EQ(Record.ID)).
+			LEFT_JOIN(RecordAlias, RecordAlias.RecordID.EQ(Record.ID)),
+	).WHERE(
+		Record.ID.IN(page),
+	).ORDER_BY(append(order, RecordLabel.Language, RecordAlias.Name)...)
 
 	var dest []RecordAggregate
 	err = stmt.Query(r.db, &dest)
@@ -301,3 +1196,356 @@ func (r RecordRepository) GetPaged(c context.Context, limit int, offset int) ([]
 	}
 	return dest, total.C, nil
 }
+
+// getNarrowerTerms returns every given term together with the terms below
+// it in the hierarchy, or ErrTermNotFound when one of them does not exist.
+func (r RecordRepository) getNarrowerTerms(c context.Context, ids []uuid.UUID) ([][]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	stmt := SELECT(Term.ID, Term.ParentID).FROM(Term)
+
+	var terms []model.Term
+	if err := stmt.QueryContext(c, r.db, &terms); err != nil {
+		return nil, fmt.Errorf("error getting terms: %w", err)
+	}
+
+	children := make(map[uuid.UUID][]uuid.UUID)
+	exists := make(map[uuid.UUID]bool)
+	for _, term := range terms {
+		exists[term.ID] = true
+		if term.ParentID != nil {
+			children[*term.ParentID] = append(children[*term.ParentID], term.ID)
+		}
+	}
+
+	narrower := make([][]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !exists[id] {
+			return nil, common.ErrTermNotFound
+		}
+		set := []uuid.UUID{id}
+		for i := 0; i < len(set); i++ {
+			set = append(set, children[set[i]]...)
+		}
+		narrower = append(narrower, set)
+	}
+	return narrower, nil
+}
+
+// condition builds the filter on records, given the sets of terms that match
+// each of the terms filtered on.
+func (f RecordFilter) condition(narrower [][]uuid.UUID) BoolExpression {
+	condition := Bool(true)
+	if f.Search != "" {
+		pattern := String("%" + likeEscaper.Replace(f.Search) + "%")
+		condition = condition.AND(
+			ilike(Record.Title, pattern).
+				OR(EXISTS(
+					SELECT(RecordLabel.ID).
+						FROM(RecordLabel).
+						WHERE(RecordLabel.RecordID.EQ(Record.ID).AND(ilike(RecordLabel.Title, pattern))),
+				)).
+				OR(EXISTS(
+					SELECT(RecordAlias.ID).
+						FROM(RecordAlias).
+						WHERE(RecordAlias.RecordID.EQ(Record.ID).AND(ilike(RecordAlias.Name, pattern))),
+				)),
+		)
+	}
+	for _, attribute := range f.Attributes {
+		condition = condition.AND(attribute.condition())
+	}
+	for _, ids := range narrower {
+		terms := make([]Expression, len(ids))
+		for i, id := range ids {
+			terms[i] = UUID(id)
+		}
+		condition = condition.AND(EXISTS(
+			SELECT(RecordTerm.TermID).
+				FROM(RecordTerm).
+				WHERE(RecordTerm.RecordID.EQ(Record.ID).AND(RecordTerm.TermID.IN(terms...))),
+		))
+	}
+	return condition
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns so searches match them
+// literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func ilike(column StringExpression, pattern StringExpression) BoolExpression {
+	return BoolExp(BinaryOperator(column, pattern, "ILIKE"))
+}
+
+// GetAttributeSchema returns the JSON Schema of the attributes of a record
+// type, or nil when the type has no attributes.
+func (r RecordRepository) GetAttributeSchema(c context.Context, recordType int16) (*string, error) {
+	stmt := SELECT(TypeDefinition.AttributeSchema).
+		FROM(TypeDefinition).
+		WHERE(TypeDefinition.ID.EQ(Int16(recordType)))
+
+	var dest []model.TypeDefinition
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting attribute schema: %w", err)
+	}
+	if len(dest) == 0 {
+		return nil, nil
+	}
+	return dest[0].AttributeSchema, nil
+}
+
+func (r RecordRepository) GetSources(c context.Context, id uuid.UUID) ([]model.Source, error) {
+	stmt := SELECT(Source.AllColumns).
+		FROM(Source).
+		WHERE(Source.RecordID.EQ(UUID(id)))
+
+	var dest []model.Source
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting sources: %w", err)
+	}
+	return dest, nil
+}
+
+// GetClaims returns the claims the sources of a record make about it.
+func (r RecordRepository) GetClaims(c context.Context, id uuid.UUID) ([]ClaimEntity, error) {
+	stmt := SELECT(Claim.AllColumns, Source.AllColumns).
+		FROM(Claim.INNER_JOIN(Source, Source.ID.EQ(Claim.SourceID))).
+		WHERE(Source.RecordID.EQ(UUID(id))).
+		ORDER_BY(Claim.CreatedAt, Claim.ID)
+
+	var dest []ClaimEntity
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting claims: %w", err)
+	}
+	return dest, nil
+}
+
+// GetAlternatives returns the values competing with the values a record
+// holds.
+func (r RecordRepository) GetAlternatives(c context.Context, id uuid.UUID) ([]model.AlternativeValue, error) {
+	stmt := SELECT(AlternativeValue.AllColumns).
+		FROM(AlternativeValue).
+		WHERE(AlternativeValue.RecordID.EQ(UUID(id))).
+		ORDER_BY(AlternativeValue.Field, AlternativeValue.CreatedAt)
+
+	var dest []model.AlternativeValue
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting alternative values: %w", err)
+	}
+	return dest, nil
+}
+
+// GetLinks returns the links of a record in either direction, each joined with
+// the record on the other end.
+func (r RecordRepository) GetLinks(c context.Context, id uuid.UUID) ([]LinkedRecord, error) {
+	stmt := SELECT(
+		Link.AllColumns,
+		Record.AllColumns,
+	).FROM(
+		Link.INNER_JOIN(Record, Record.ID.EQ(Link.RecordID).OR(Record.ID.EQ(Link.RecordId2))),
+	).WHERE(
+		Link.RecordID.EQ(UUID(id)).OR(Link.RecordId2.EQ(UUID(id))).
+			AND(Record.ID.NOT_EQ(UUID(id))),
+	)
+
+	var dest []LinkedRecord
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting links: %w", err)
+	}
+	return dest, nil
+}
+
+// GetInfluenceState returns the revisions of the link graph and of the
+// impacts and statuses, together with the revisions and decay the stored
+// influence scores were computed with.
+func (r RecordRepository) GetInfluenceState(c context.Context) (model.GraphRevision, error) {
+	stmt := selectGraphRevision()
+
+	var dest model.GraphRevision
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return model.GraphRevision{}, fmt.Errorf("error getting graph revision: %w", err)
+	}
+	return dest, nil
+}
+
+// pendingChanges sums a column of the changes not yet folded into the
+// revisions of graph_revision.
+func pendingChanges(column ColumnInteger) IntegerExpression {
+	return IntExp(SELECT(CAST(COALESCE(SUM(column), Int64(0))).AS_BIGINT()).FROM(GraphChange))
+}
+
+// selectGraphRevision selects the revision row with the pending changes added
+// onto its revisions.
+func selectGraphRevision() SelectStatement {
+	return SELECT(
+		GraphRevision.ID,
+		GraphRevision.Revision.ADD(pendingChanges(GraphChange.LinkChanges)).AS("graph_revision.revision"),
+		GraphRevision.InfluenceRevision,
+		GraphRevision.InfluenceDecay,
+		GraphRevision.InfluenceInputsRevision.ADD(pendingChanges(GraphChange.InfluenceInputChanges)).AS("graph_revision.influence_inputs_revision"),
+		GraphRevision.InfluenceComputedInputsRevision,
+	).FROM(GraphRevision)
+}
+
+// FoldGraphChanges adds the changes appended by writes to links, impacts and
+// statuses onto the revisions of graph_revision and deletes them, so they do
+// not pile up. The revisions read stay the same.
+func (r RecordRepository) FoldGraphChanges(c context.Context) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var changes []model.GraphChange
+	deleteStmt := GraphChange.DELETE().
+		WHERE(Bool(true)).
+		RETURNING(GraphChange.AllColumns)
+	if err = deleteStmt.QueryContext(c, tx, &changes); err != nil {
+		return fmt.Errorf("error deleting graph changes: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var links, inputs int64
+	for _, change := range changes {
+		links += change.LinkChanges
+		inputs += change.InfluenceInputChanges
+	}
+	updateStmt := GraphRevision.UPDATE(GraphRevision.Revision, GraphRevision.InfluenceInputsRevision).
+		SET(GraphRevision.Revision.ADD(Int64(links)), GraphRevision.InfluenceInputsRevision.ADD(Int64(inputs))).
+		WHERE(Bool(true))
+	if _, err = updateStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error folding graph changes: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+type impactTotal struct {
+	RecordID uuid.UUID
+	Total    int64
+}
+
+// GetInfluenceInput returns the links and the impact total of every record
+// together with the revisions they belong to, read from the same snapshot.
+// Removed records and their links are left out.
+func (r RecordRepository) GetInfluenceInput(c context.Context) (model.GraphRevision, []model.Link, map[uuid.UUID]float64, error) {
+	tx, err := r.db.BeginTx(c, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return model.GraphRevision{}, nil, nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var revision model.GraphRevision
+	if err = selectGraphRevision().QueryContext(c, tx, &revision); err != nil {
+		return model.GraphRevision{}, nil, nil, fmt.Errorf("error getting graph revision: %w", err)
+	}
+
+	source := Record.AS("source")
+	target := Record.AS("target")
+	linkStmt := SELECT(Link.AllColumns).
+		FROM(
+			Link.
+				INNER_JOIN(source, source.ID.EQ(Link.RecordID)).
+				INNER_JOIN(target, target.ID.EQ(Link.RecordId2)),
+		).
+		WHERE(
+			source.Status.NOT_EQ(Int16(Removed.ToInt16())).
+				AND(target.Status.NOT_EQ(Int16(Removed.ToInt16()))),
+		)
+
+	var links []model.Link
+	if err = linkStmt.QueryContext(c, tx, &links); err != nil {
+		return model.GraphRevision{}, nil, nil, fmt.Errorf("error getting links: %w", err)
+	}
+
+	impactStmt := SELECT(
+		Impact.RecordID.AS("impact_total.record_id"),
+		SUM(Impact.Value).AS("impact_total.total"),
+	).FROM(
+		Impact.INNER_JOIN(Record, Record.ID.EQ(Impact.RecordID)),
+	).WHERE(
+		Record.Status.NOT_EQ(Int16(Removed.ToInt16())),
+	).GROUP_BY(Impact.RecordID)
+
+	var totals []impactTotal
+	if err = impactStmt.QueryContext(c, tx, &totals); err != nil {
+		return model.GraphRevision{}, nil, nil, fmt.Errorf("error getting impact totals: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return model.GraphRevision{}, nil, nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	impacts := make(map[uuid.UUID]float64, len(totals))
+	for _, total := range totals {
+		impacts[total.RecordID] = float64(total.Total)
+	}
+	return revision, links, impacts, nil
+}
+
+// influenceChunkSize keeps inserts of influence scores below the limit on
+// query parameters.
+const influenceChunkSize = 1000
+
+// SaveInfluence replaces the stored influence scores with ones computed for
+// the given revisions. Scores computed for revisions that have since been
+// superseded are discarded, as they would be stale already.
+func (r RecordRepository) SaveInfluence(c context.Context, state model.GraphRevision, decay float64, influence map[uuid.UUID]float64) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = RecordInfluence.DELETE().WHERE(Bool(true)).ExecContext(c, tx); err != nil {
+		return fmt.Errorf("error deleting influence: %w", err)
+	}
+
+	var rows []model.RecordInfluence
+	for id, score := range influence {
+		if score != 0 {
+			rows = append(rows, model.RecordInfluence{RecordID: id, Influence: score})
+		}
+	}
+	for start := 0; start < len(rows); start += influenceChunkSize {
+		insertStmt := RecordInfluence.INSERT(RecordInfluence.AllColumns).
+			MODELS(rows[start:min(start+influenceChunkSize, len(rows))])
+		if _, err = insertStmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error inserting influence: %w", err)
+		}
+	}
+
+	// The revision row is updated last, as folding graph changes updates it
+	// and would otherwise wait for the scores to be written
+	stateStmt := GraphRevision.UPDATE(GraphRevision.InfluenceRevision, GraphRevision.InfluenceComputedInputsRevision, GraphRevision.InfluenceDecay).
+		SET(Int64(state.Revision), Int64(state.InfluenceInputsRevision), Double(decay)).
+		WHERE(
+			GraphRevision.Revision.ADD(pendingChanges(GraphChange.LinkChanges)).EQ(Int64(state.Revision)).
+				AND(GraphRevision.InfluenceInputsRevision.ADD(pendingChanges(GraphChange.InfluenceInputChanges)).EQ(Int64(state.InfluenceInputsRevision))),
+		)
+
+	result, err := stateStmt.ExecContext(c, tx)
+	if err != nil {
+		return fmt.Errorf("error updating influence revision: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error updating influence revision: %w", err)
+	}
+	if updated == 0 {
+		return nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
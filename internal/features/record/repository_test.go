@@ -0,0 +1,69 @@
+package record
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"historylink/.gen/historylink/public/model"
+
+	"github.com/google/uuid"
+)
+
+func TestPlanLinkMerge(t *testing.T) {
+	record := func(i int) uuid.UUID {
+		return uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+	}
+	survivor, duplicate := record(1), record(2)
+	link := func(i int, from uuid.UUID, to uuid.UUID) model.Link {
+		return model.Link{ID: record(100 + i), RecordID: from, RecordId2: to}
+	}
+	into := func(i int) *uuid.UUID {
+		id := record(100 + i)
+		return &id
+	}
+
+	tests := []struct {
+		name     string
+		links    []model.Link
+		expected []linkMerge
+	}{
+		{name: "no links"},
+		{
+			name:  "survivor links stay",
+			links: []model.Link{link(1, survivor, record(3)), link(2, record(4), survivor)},
+		},
+		{
+			name:     "moved in either direction",
+			links:    []model.Link{link(1, duplicate, record(3)), link(2, record(4), duplicate)},
+			expected: []linkMerge{{Link: link(1, duplicate, record(3))}, {Link: link(2, record(4), duplicate)}},
+		},
+		{
+			name:     "link between the two is dropped",
+			links:    []model.Link{link(1, record(3), survivor), link(2, duplicate, survivor), link(3, survivor, duplicate)},
+			expected: []linkMerge{{Link: link(2, duplicate, survivor), Dropped: true}, {Link: link(3, survivor, duplicate), Dropped: true}},
+		},
+		{
+			name:     "collapsed into the survivor's link",
+			links:    []model.Link{link(1, duplicate, record(3)), link(2, record(3), survivor)},
+			expected: []linkMerge{{Link: link(1, duplicate, record(3)), Into: into(2)}},
+		},
+		{
+			name:  "collapsed into a moved link",
+			links: []model.Link{link(1, duplicate, record(3)), link(2, record(3), duplicate)},
+			expected: []linkMerge{
+				{Link: link(1, duplicate, record(3))},
+				{Link: link(2, record(3), duplicate), Into: into(1)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planLinkMerge(tt.links, survivor, duplicate)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("planLinkMerge() = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,35 +2,70 @@ package record
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"historylink/.gen/historylink/public/model"
 	"historylink/internal/common"
 	"log/slog"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
+	"golang.org/x/text/language"
 )
 
-func NewRecordService(recordRepository IRecordRepository, logger *slog.Logger) IRecordService {
+func NewRecordService(recordRepository IRecordRepository, logger *slog.Logger, influenceDecay float64, defaultLanguage string) IRecordService {
 	return RecordService{
 		recordRepository: recordRepository,
 		logger:           logger,
+		influenceDecay:   influenceDecay,
+		defaultLanguage:  defaultLanguage,
 	}
 }
 
 type IRecordService interface {
 	Create(c context.Context, command createRecordCommandBody) (recordResponseBody, error)
 	Update(c context.Context, id uuid.UUID, command updateRecordCommandBody) error
-	GetById(id uuid.UUID) (recordResponseBody, error)
-	GetPaged(c context.Context, page, pageSize int) ([]recordResponseBody, int, error)
+	GetById(c context.Context, id uuid.UUID) (recordResponseBody, error)
+	GetPaged(c context.Context, page, pageSize int, sort RecordSort, filter RecordFilter) ([]recordResponseBody, int, error)
 	Delete(c context.Context, id uuid.UUID) error
+	GetLinkedData(c context.Context, id uuid.UUID, contentType string, baseURL string) ([]byte, error)
+	GetByExternalId(c context.Context, scheme ExternalIdScheme, value string) (recordResponseBody, error)
+	GetDuplicates(c context.Context, id uuid.UUID, limit int) ([]duplicateResponse, error)
+	GetRedirect(c context.Context, id uuid.UUID) (uuid.UUID, error)
+	Merge(c context.Context, id uuid.UUID, command mergeRecordCommandBody) (recordResponseBody, error)
+	RunInfluence(c context.Context) error
 }
 
+const (
+	// influencePollInterval is how often the stored influence scores are
+	// checked for being stale
+	influencePollInterval = 5 * time.Second
+	// influenceMaxDelay bounds how long recomputing stale scores waits for
+	// the graph to stop changing
+	influenceMaxDelay = time.Minute
+)
+
+// duplicateWarningLimit caps the number of possible duplicates reported when
+// creating a record.
+const duplicateWarningLimit = 5
+
 type RecordService struct {
 	recordRepository IRecordRepository
 	logger           *slog.Logger
+	// influenceDecay is the share of influence passed on per link followed
+	influenceDecay float64
+	// defaultLanguage is the language of the title and description of records
+	defaultLanguage string
 }
 
+// RecordStatus, Type and Category are stored as codes, named through the
+// Statuses, Types and Categories vocabularies. The constants are the built-in
+// values.
 type RecordStatus string
 
 const (
@@ -59,96 +94,107 @@ const (
 	Tech      Category = "tech"
 )
 
-func CategoryFromInt16(v int16) Category {
+type ExternalIdScheme string
+
+const (
+	Wikidata ExternalIdScheme = "wikidata"
+	VIAF     ExternalIdScheme = "viaf"
+	GeoNames ExternalIdScheme = "geonames"
+	ISNI     ExternalIdScheme = "isni"
+	Custom   ExternalIdScheme = "custom"
+)
+
+func ExternalIdSchemeFromInt16(v int16) ExternalIdScheme {
 	switch v {
 	case 0:
-		return Political
+		return Wikidata
 	case 1:
-		return Social
+		return VIAF
 	case 2:
-		return Economic
+		return GeoNames
 	case 3:
-		return Cultural
+		return ISNI
 	case 4:
-		return Tech
+		return Custom
 	}
 	return ""
 }
 
-func (c Category) ToInt16() int16 {
-	switch c {
-	case Political:
+func (s ExternalIdScheme) ToInt16() int16 {
+	switch s {
+	case Wikidata:
 		return 0
-	case Social:
+	case VIAF:
 		return 1
-	case Economic:
+	case GeoNames:
 		return 2
-	case Cultural:
+	case ISNI:
 		return 3
-	case Tech:
+	case Custom:
 		return 4
 	}
 	return -1
 }
 
-func TypeFromInt16(v int16) Type {
-	switch v {
-	case 0:
-		return Arc
-	case 1:
-		return Event
-	case 2:
-		return Person
-	case 3:
-		return Object
-	}
-	return ""
+var externalIdPatterns = map[ExternalIdScheme]*regexp.Regexp{
+	Wikidata: regexp.MustCompile(`^Q[1-9][0-9]*$`),
+	VIAF:     regexp.MustCompile(`^[1-9][0-9]*$`),
+	GeoNames: regexp.MustCompile(`^[1-9][0-9]*$`),
+	ISNI:     regexp.MustCompile(`^[0-9]{15}[0-9X]$`),
+	// Custom identifiers are namespaced so different datasets cannot clash.
+	// They cannot hold a slash, as they are looked up as a path segment.
+	Custom: regexp.MustCompile(`^[A-Za-z0-9_.-]+:[^/]+$`),
 }
 
-func (t Type) ToInt16() int16 {
-	switch t {
-	case Arc:
-		return 0
-	case Event:
-		return 1
-	case Person:
-		return 2
-	case Object:
-		return 3
-	}
-	return -1
+var externalIdUrls = map[ExternalIdScheme]string{
+	Wikidata: "https://www.wikidata.org/wiki/%s",
+	VIAF:     "https://viaf.org/viaf/%s",
+	GeoNames: "https://www.geonames.org/%s",
+	ISNI:     "https://isni.org/isni/%s",
 }
 
-func RecordStatusFromInt16(v int16) RecordStatus {
-	switch v {
-	case 0:
-		return Removed
-	case 1:
-		return Draft
-	case 2:
-		return PendingReview
-	case 3:
-		return Reviewed
+// NormalizeExternalId brings a value into the form it is stored in, so the
+// same identifier written differently is still recognised as a duplicate.
+func NormalizeExternalId(scheme ExternalIdScheme, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch scheme {
+	case Wikidata:
+		value = strings.ToUpper(value)
+	case ISNI:
+		value = strings.ToUpper(strings.ReplaceAll(value, " ", ""))
 	}
-	return ""
-}
 
-func (r RecordStatus) ToInt16() int16 {
-	switch r {
-	case Removed:
-		return 0
-	case Draft:
-		return 1
-	case PendingReview:
-		return 2
-	case Reviewed:
-		return 3
+	pattern, ok := externalIdPatterns[scheme]
+	if !ok || !pattern.MatchString(value) {
+		return "", fmt.Errorf("%w: %q is not a valid %s identifier", common.ErrInvalidExternalId, value, scheme)
 	}
-	return -1
+	return value, nil
 }
 
 func (s RecordService) Create(context context.Context, command createRecordCommandBody) (recordResponseBody, error) {
-	response, err := s.recordRepository.Create(context, RecordAggregate{
+	// A new record has no sources yet, so nothing backs its facts
+	if command.RecordStatus == Reviewed {
+		return recordResponseBody{}, fmt.Errorf("%w: create the record first and add sources before reviewing it", common.ErrMissingProvenance)
+	}
+
+	externalIds, err := s.externalIds(context, uuid.Nil, command.ExternalIds)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+	labels, err := s.labels(command.Labels)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+	aliases, err := s.aliases(command.Aliases)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+	attributes, err := s.attributes(context, command.Type, command.Attributes)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+
+	record := RecordAggregate{
 		Record: model.Record{
 			Title:        command.Title,
 			Description:  command.Description,
@@ -159,6 +205,8 @@ func (s RecordService) Create(context context.Context, command createRecordComma
 			EndDate:      common.ToTime(command.EndDate),
 			Type:         command.Type.ToInt16(),
 			Status:       command.RecordStatus.ToInt16(),
+			Attributes:   attributes,
+			Confidence:   ConfidenceToNullInt16(command.Confidence),
 		},
 		Impacts: lo.Map(command.Impacts, func(impact createImpactCommandBody, index int) ImpactEntity {
 			return ImpactEntity{
@@ -166,28 +214,135 @@ func (s RecordService) Create(context context.Context, command createRecordComma
 					Description: impact.Description,
 					Value:       impact.Value,
 					Category:    impact.Category.ToInt16(),
+					Confidence:  ConfidenceToNullInt16(impact.Confidence),
 				},
 			}
 		}),
-	})
+		ExternalIds: externalIds,
+		Labels:      labels,
+		Aliases:     aliases,
+	}
+
+	// Look for duplicates before inserting, so the new record is not
+	// reported as a duplicate of itself.
+	duplicates, err := s.recordRepository.GetDuplicates(context, record.Record, duplicateWarningLimit)
 	if err != nil {
 		return recordResponseBody{}, err
 	}
-	return response.toResponse(), nil
+
+	response, err := s.recordRepository.Create(context, record)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+
+	result := response.toResponse()
+	result.Warnings = lo.Map(duplicates, func(duplicate DuplicateCandidate, index int) recordWarning {
+		d := duplicate.toResponse()
+		return recordWarning{
+			Code:      PossibleDuplicate,
+			Message:   fmt.Sprintf("Record %q might describe the same %s", duplicate.Title, TypeFromInt16(duplicate.Type)),
+			Duplicate: &d,
+		}
+	})
+	return result, nil
 }
 
-func (s RecordService) GetById(id uuid.UUID) (recordResponseBody, error) {
+func (s RecordService) GetById(c context.Context, id uuid.UUID) (recordResponseBody, error) {
 	record, err := s.recordRepository.GetById(id)
 	if err != nil {
 		return recordResponseBody{}, err
 	}
-	return record.toResponse(), nil
+	return s.withProvenance(c, record)
+}
+
+// withProvenance gives the response for a single record, which lists its
+// claims and the values competing with the values it holds.
+func (s RecordService) withProvenance(c context.Context, record RecordAggregate) (recordResponseBody, error) {
+	claims, err := s.recordRepository.GetClaims(c, record.ID)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+	alternatives, err := s.recordRepository.GetAlternatives(c, record.ID)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+
+	response := record.toResponse()
+	response.Claims = claimResponses(claims)
+	response.Alternatives = alternativeResponses(alternatives)
+	return response, nil
 }
 
 func (s RecordService) Update(c context.Context, id uuid.UUID, command updateRecordCommandBody) error {
 	if id != command.ID {
 		return errors.New("id mismatch")
 	}
+
+	var externalIds []ExternalIdEntity
+	if command.ExternalIds != nil {
+		var err error
+		if externalIds, err = s.externalIds(c, id, command.ExternalIds); err != nil {
+			return err
+		}
+		if externalIds == nil {
+			externalIds = []ExternalIdEntity{}
+		}
+	}
+
+	var labels []LabelEntity
+	if command.Labels != nil {
+		var err error
+		if labels, err = s.labels(command.Labels); err != nil {
+			return err
+		}
+	}
+
+	var aliases []AliasEntity
+	if command.Aliases != nil {
+		var err error
+		if aliases, err = s.aliases(command.Aliases); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.recordRepository.GetById(id)
+	if err != nil {
+		return err
+	}
+
+	values := command.Attributes
+	if values == nil {
+		values = existing.attributes()
+	}
+	attributes, err := s.attributes(c, command.Type, values)
+	if err != nil {
+		return err
+	}
+
+	impacts := lo.Map(command.Impacts, func(impact updateImpactCommandBody, index int) ImpactEntity {
+		return ImpactEntity{
+			Impact: model.Impact{
+				ID:          impact.ID,
+				Description: impact.Description,
+				Value:       impact.Value,
+				Category:    impact.Category.ToInt16(),
+				Confidence:  ConfidenceToNullInt16(impact.Confidence),
+			},
+		}
+	})
+
+	// Reviewed records stay backed by sources through every update, not only
+	// the one reviewing them
+	if command.RecordStatus == Reviewed {
+		claims, err := s.recordRepository.GetClaims(c, id)
+		if err != nil {
+			return err
+		}
+		if err = CheckProvenance(claims, impacts); err != nil {
+			return err
+		}
+	}
+
 	return s.recordRepository.Update(c, RecordAggregate{
 		Record: model.Record{
 			ID:           command.ID,
@@ -200,22 +355,147 @@ func (s RecordService) Update(c context.Context, id uuid.UUID, command updateRec
 			EndDate:      common.ToTime(command.EndDate),
 			Type:         command.Type.ToInt16(),
 			Status:       command.RecordStatus.ToInt16(),
+			Attributes:   attributes,
+			Confidence:   ConfidenceToNullInt16(command.Confidence),
 		},
-		Impacts: lo.Map(command.Impacts, func(impact updateImpactCommandBody, index int) ImpactEntity {
-			return ImpactEntity{
-				Impact: model.Impact{
-					ID:          impact.ID,
-					Description: impact.Description,
-					Value:       impact.Value,
-					Category:    impact.Category.ToInt16(),
-				},
-			}
-		}),
+		Impacts:     impacts,
+		ExternalIds: externalIds,
+		Labels:      labels,
+		Aliases:     aliases,
 	})
 }
 
-func (s RecordService) GetPaged(c context.Context, page, pageSize int) ([]recordResponseBody, int, error) {
-	records, total, err := s.recordRepository.GetPaged(c, pageSize, (page-1)*pageSize)
+// attributes checks attributes against the schema of the record type and
+// encodes them for storage.
+func (s RecordService) attributes(c context.Context, recordType Type, attributes map[string]any) (string, error) {
+	raw, err := s.recordRepository.GetAttributeSchema(c, recordType.ToInt16())
+	if err != nil {
+		return "", err
+	}
+
+	var schema *huma.Schema
+	if raw != nil {
+		if schema, err = ParseAttributeSchema([]byte(*raw)); err != nil {
+			return "", err
+		}
+	}
+	if err = validateAttributes(recordType, schema, attributes); err != nil {
+		return "", err
+	}
+
+	if attributes == nil {
+		attributes = map[string]any{}
+	}
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return "", fmt.Errorf("error encoding attributes: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// labels normalizes the language tags of the given labels, refusing labels
+// in the default language as the title and description already hold that.
+// The result is only nil when commands is.
+func (s RecordService) labels(commands []labelCommandBody) ([]LabelEntity, error) {
+	if commands == nil {
+		return nil, nil
+	}
+
+	labels := make([]LabelEntity, 0, len(commands))
+	seen := make(map[string]bool)
+	for _, command := range commands {
+		tag, err := language.Parse(command.Language)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a valid language tag", common.ErrInvalidLabel, command.Language)
+		}
+		lang := tag.String()
+		if lang == s.defaultLanguage {
+			return nil, fmt.Errorf("%w: %s is the default language, set the title and description instead", common.ErrInvalidLabel, lang)
+		}
+		if seen[lang] {
+			return nil, fmt.Errorf("%w: more than one label in %s", common.ErrInvalidLabel, lang)
+		}
+		seen[lang] = true
+
+		labels = append(labels, LabelEntity{
+			RecordLabel: model.RecordLabel{
+				Language:    lang,
+				Title:       command.Title,
+				Description: command.Description,
+			},
+		})
+	}
+	return labels, nil
+}
+
+// aliases normalizes the language tags of the given aliases and drops
+// repeated names. The result is only nil when commands is.
+func (s RecordService) aliases(commands []aliasCommandBody) ([]AliasEntity, error) {
+	if commands == nil {
+		return nil, nil
+	}
+
+	aliases := make([]AliasEntity, 0, len(commands))
+	seen := make(map[string]bool)
+	for _, command := range commands {
+		name := strings.TrimSpace(command.Name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		alias := AliasEntity{RecordAlias: model.RecordAlias{Name: name}}
+		if command.Language != nil {
+			tag, err := language.Parse(*command.Language)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not a valid language tag", common.ErrInvalidLabel, *command.Language)
+			}
+			lang := tag.String()
+			alias.Language = &lang
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, nil
+}
+
+// externalIds normalizes the identifiers given for a record and makes sure
+// none of them already belongs to another record.
+func (s RecordService) externalIds(c context.Context, id uuid.UUID, commands []externalIdCommandBody) ([]ExternalIdEntity, error) {
+	var externalIds []ExternalIdEntity
+	seen := make(map[externalIdKey]bool)
+	for _, command := range commands {
+		value, err := NormalizeExternalId(command.Scheme, command.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		externalId := ExternalIdEntity{
+			ExternalIdentifier: model.ExternalIdentifier{
+				Scheme: command.Scheme.ToInt16(),
+				Value:  value,
+			},
+		}
+		if seen[externalId.key()] {
+			continue
+		}
+		seen[externalId.key()] = true
+
+		owner, err := s.recordRepository.GetByExternalId(c, externalId.Scheme, externalId.Value)
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+		case err != nil:
+			return nil, err
+		case owner.ID != id:
+			return nil, fmt.Errorf("%w: %s %s belongs to record %s", common.ErrExternalIdAlreadyExists, command.Scheme, value, owner.ID)
+		}
+
+		externalIds = append(externalIds, externalId)
+	}
+	return externalIds, nil
+}
+
+func (s RecordService) GetPaged(c context.Context, page, pageSize int, sort RecordSort, filter RecordFilter) ([]recordResponseBody, int, error) {
+	records, total, err := s.recordRepository.GetPaged(c, pageSize, (page-1)*pageSize, sort, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -225,6 +505,155 @@ func (s RecordService) GetPaged(c context.Context, page, pageSize int) ([]record
 	}), total, nil
 }
 
+func (s RecordService) GetByExternalId(c context.Context, scheme ExternalIdScheme, value string) (recordResponseBody, error) {
+	value, err := NormalizeExternalId(scheme, value)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+
+	record, err := s.recordRepository.GetByExternalId(c, scheme.ToInt16(), value)
+	if err != nil {
+		return recordResponseBody{}, err
+	}
+	return s.withProvenance(c, record)
+}
+
+func (s RecordService) GetDuplicates(c context.Context, id uuid.UUID, limit int) ([]duplicateResponse, error) {
+	record, err := s.recordRepository.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates, err := s.recordRepository.GetDuplicates(c, record.Record, limit)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(duplicates, func(duplicate DuplicateCandidate, index int) duplicateResponse {
+		return duplicate.toResponse()
+	}), nil
+}
+
+func (s RecordService) GetRedirect(c context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return s.recordRepository.GetRedirect(c, id)
+}
+
+func (s RecordService) Merge(c context.Context, id uuid.UUID, command mergeRecordCommandBody) (recordResponseBody, error) {
+	if id == command.DuplicateID {
+		return recordResponseBody{}, common.ErrMergeWithItself
+	}
+
+	if err := s.recordRepository.Merge(c, id, command.DuplicateID); err != nil {
+		return recordResponseBody{}, err
+	}
+	s.logger.Info("merged records", "survivor", id, "duplicate", command.DuplicateID)
+
+	return s.GetById(c, id)
+}
+
+// RunInfluence keeps the stored influence scores up to date until the context
+// is done, so reads only ever serve the scores saved last. Scores are
+// recomputed once the graph stopped changing for a whole poll, or once they
+// have been stale for influenceMaxDelay while it kept changing.
+func (s RecordService) RunInfluence(c context.Context) error {
+	poll := time.NewTicker(influencePollInterval)
+	defer poll.Stop()
+
+	// seen is the state the scores were last found stale at
+	var seen *model.GraphRevision
+	var staleSince time.Time
+	for {
+		if err := s.recordRepository.FoldGraphChanges(c); err != nil {
+			s.logger.Error(err.Error())
+		}
+
+		state, err := s.recordRepository.GetInfluenceState(c)
+		switch {
+		case err != nil:
+			s.logger.Error(err.Error())
+		case !s.influenceStale(state):
+			seen = nil
+		case seen == nil:
+			seen, staleSince = &state, time.Now()
+		case !sameInfluenceInputs(*seen, state) && time.Since(staleSince) < influenceMaxDelay:
+			seen = &state
+		default:
+			if err = s.refreshInfluence(c); err != nil {
+				s.logger.Error(err.Error())
+			}
+			seen = nil
+		}
+
+		select {
+		case <-c.Done():
+			return nil
+		case <-poll.C:
+		}
+	}
+}
+
+// influenceStale tells whether links, impacts or statuses changed since the
+// stored influence scores were computed, or whether they were computed with
+// a different decay.
+func (s RecordService) influenceStale(state model.GraphRevision) bool {
+	return state.InfluenceRevision == nil || *state.InfluenceRevision != state.Revision ||
+		state.InfluenceComputedInputsRevision == nil || *state.InfluenceComputedInputsRevision != state.InfluenceInputsRevision ||
+		state.InfluenceDecay == nil || *state.InfluenceDecay != s.influenceDecay
+}
+
+// sameInfluenceInputs tells whether neither links nor impacts and statuses
+// changed between two states.
+func sameInfluenceInputs(a model.GraphRevision, b model.GraphRevision) bool {
+	return a.Revision == b.Revision && a.InfluenceInputsRevision == b.InfluenceInputsRevision
+}
+
+// refreshInfluence recomputes the influence of every record and saves it.
+func (s RecordService) refreshInfluence(c context.Context) error {
+	state, links, impacts, err := s.recordRepository.GetInfluenceInput(c)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	influence := computeInfluence(links, impacts, s.influenceDecay)
+	if err = s.recordRepository.SaveInfluence(c, state, s.influenceDecay, influence); err != nil {
+		return err
+	}
+	s.logger.Info("computed record influence", "revision", state.Revision, "inputsRevision", state.InfluenceInputsRevision, "decay", s.influenceDecay, "records", len(influence), "duration", time.Since(start))
+
+	return nil
+}
+
 func (s RecordService) Delete(c context.Context, id uuid.UUID) error {
 	return s.recordRepository.Delete(c, id)
 }
+
+// GetLinkedData renders a record with its links and sources as JSON-LD or
+// Turtle, using baseURL to build the identifiers of records.
+func (s RecordService) GetLinkedData(c context.Context, id uuid.UUID, contentType string, baseURL string) ([]byte, error) {
+	record, err := s.recordRepository.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := s.recordRepository.GetSources(c, id)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.recordRepository.GetLinks(c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	node := linkedDataAggregate{
+		RecordAggregate: record,
+		Sources:         sources,
+		Links:           links,
+	}.toLinkedData(baseURL)
+
+	switch contentType {
+	case JSONLD:
+		return marshalJSONLD(node, baseURL)
+	case Turtle:
+		return marshalTurtle(node, baseURL), nil
+	}
+	return nil, fmt.Errorf("unsupported linked data content type %q", contentType)
+}
@@ -0,0 +1,49 @@
+package record
+
+import (
+	"errors"
+	"testing"
+
+	"historylink/internal/common"
+)
+
+func TestNormalizeExternalId(t *testing.T) {
+	tests := []struct {
+		name     string
+		scheme   ExternalIdScheme
+		value    string
+		expected string
+		valid    bool
+	}{
+		{name: "wikidata", scheme: Wikidata, value: "Q361", expected: "Q361", valid: true},
+		{name: "wikidata lower case and padded", scheme: Wikidata, value: " q361 ", expected: "Q361", valid: true},
+		{name: "wikidata property", scheme: Wikidata, value: "P31", valid: false},
+		{name: "viaf", scheme: VIAF, value: "102333412", expected: "102333412", valid: true},
+		{name: "viaf leading zero", scheme: VIAF, value: "0102333412", valid: false},
+		{name: "isni with spaces", scheme: ISNI, value: "0000 0001 2103 2683", expected: "0000000121032683", valid: true},
+		{name: "isni check character", scheme: ISNI, value: "000000012146438x", expected: "000000012146438X", valid: true},
+		{name: "isni too short", scheme: ISNI, value: "00000001210326", valid: false},
+		{name: "custom", scheme: Custom, value: "museum:inv-1914.2", expected: "museum:inv-1914.2", valid: true},
+		{name: "custom without namespace", scheme: Custom, value: "inv-1914", valid: false},
+		{name: "custom with slash", scheme: Custom, value: "museum:inv/1914", valid: false},
+		{name: "unknown scheme", scheme: "doi", value: "10.1000/182", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeExternalId(tt.scheme, tt.value)
+			if !tt.valid {
+				if !errors.Is(err, common.ErrInvalidExternalId) {
+					t.Fatalf("NormalizeExternalId(%q) error = %v, expected ErrInvalidExternalId", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeExternalId(%q) error = %v", tt.value, err)
+			}
+			if got != tt.expected {
+				t.Errorf("NormalizeExternalId(%q) = %q, expected %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package record
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Vocabulary maps the values of an enumeration onto the codes they are stored
+// as. Values live in a reference table so new ones can be added at runtime;
+// the values the code refers to by name are built in and always present.
+type Vocabulary struct {
+	mu    sync.RWMutex
+	names map[int16]string
+	codes map[string]int16
+}
+
+func newVocabulary(values map[int16]string) *Vocabulary {
+	v := &Vocabulary{}
+	v.Replace(values)
+	return v
+}
+
+var (
+	Categories = newVocabulary(map[int16]string{
+		0: string(Political),
+		1: string(Social),
+		2: string(Economic),
+		3: string(Cultural),
+		4: string(Tech),
+	})
+	Types = newVocabulary(map[int16]string{
+		0: string(Arc),
+		1: string(Event),
+		2: string(Person),
+		3: string(Object),
+	})
+	Statuses = newVocabulary(map[int16]string{
+		0: string(Removed),
+		1: string(Draft),
+		2: string(PendingReview),
+		3: string(Reviewed),
+	})
+)
+
+// Replace swaps the values of the vocabulary for the given ones, telling
+// whether they differ from the values held until then.
+func (v *Vocabulary) Replace(values map[int16]string) bool {
+	codes := make(map[string]int16, len(values))
+	for code, name := range values {
+		codes[name] = code
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if maps.Equal(v.names, values) {
+		return false
+	}
+	v.names = values
+	v.codes = codes
+	return true
+}
+
+func (v *Vocabulary) name(code int16) string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.names[code]
+}
+
+func (v *Vocabulary) code(name string) int16 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if code, ok := v.codes[name]; ok {
+		return code
+	}
+	return -1
+}
+
+// Names lists the values of the vocabulary in the order of their codes.
+func (v *Vocabulary) Names() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	codes := make([]int16, 0, len(v.names))
+	for code := range v.names {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	names := make([]string, len(codes))
+	for i, code := range codes {
+		names[i] = v.names[code]
+	}
+	return names
+}
+
+// schema describes the vocabulary as a string enumeration, so the OpenAPI
+// document and request validation follow the values known when the API is
+// mounted. The API is mounted again once the values change.
+func (v *Vocabulary) schema() *huma.Schema {
+	names := v.Names()
+	enum := make([]any, len(names))
+	for i, name := range names {
+		enum[i] = name
+	}
+	return &huma.Schema{Type: huma.TypeString, Enum: enum}
+}
+
+func (c Category) Schema(r huma.Registry) *huma.Schema     { return Categories.schema() }
+func (t Type) Schema(r huma.Registry) *huma.Schema         { return Types.schema() }
+func (s RecordStatus) Schema(r huma.Registry) *huma.Schema { return Statuses.schema() }
+
+func CategoryFromInt16(v int16) Category { return Category(Categories.name(v)) }
+func (c Category) ToInt16() int16        { return Categories.code(string(c)) }
+
+func TypeFromInt16(v int16) Type { return Type(Types.name(v)) }
+func (t Type) ToInt16() int16    { return Types.code(string(t)) }
+
+func RecordStatusFromInt16(v int16) RecordStatus { return RecordStatus(Statuses.name(v)) }
+func (r RecordStatus) ToInt16() int16            { return Statuses.code(string(r)) }
@@ -0,0 +1,216 @@
+package source
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+func NewSourceResources(conn *sql.DB, logger *slog.Logger) SourceResources {
+	return SourceResources{
+		logger:        logger,
+		SourceService: NewSourceService(NewRepository(conn, logger), logger),
+	}
+}
+
+type SourceResources struct {
+	SourceService ISourceService
+	logger        *slog.Logger
+}
+
+func (rs SourceResources) getSources(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body []sourceResponseBody
+}, error) {
+	sources, err := rs.SourceService.GetSources(c, input.ID)
+	if err != nil {
+		return nil, sourceError(err)
+	}
+
+	if sources == nil {
+		sources = []sourceResponseBody{}
+	}
+
+	return &struct {
+		Body []sourceResponseBody
+	}{
+		Body: sources,
+	}, nil
+}
+
+func (rs SourceResources) createSource(c context.Context, input *struct {
+	ID   uuid.UUID `path:"id"`
+	Body createSourceCommandBody
+}) (*struct {
+	Body sourceResponseBody
+}, error) {
+	source, err := rs.SourceService.CreateSource(c, input.ID, input.Body)
+	if err != nil {
+		return nil, sourceError(err)
+	}
+
+	return &struct {
+		Body sourceResponseBody
+	}{
+		Body: source,
+	}, nil
+}
+
+func (rs SourceResources) deleteSource(c context.Context, input *struct {
+	common.ActorInput
+	ID       uuid.UUID `path:"id"`
+	SourceID uuid.UUID `path:"sourceId"`
+}) (*struct{}, error) {
+	if err := rs.SourceService.DeleteSource(input.WithActor(c), input.ID, input.SourceID); err != nil {
+		return nil, sourceError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs SourceResources) createClaim(c context.Context, input *struct {
+	ID       uuid.UUID `path:"id"`
+	SourceID uuid.UUID `path:"sourceId"`
+	Body     createClaimCommandBody
+}) (*struct {
+	Body sourceResponseBody
+}, error) {
+	source, err := rs.SourceService.CreateClaim(c, input.ID, input.SourceID, input.Body)
+	if err != nil {
+		return nil, sourceError(err)
+	}
+
+	return &struct {
+		Body sourceResponseBody
+	}{
+		Body: source,
+	}, nil
+}
+
+func (rs SourceResources) deleteClaim(c context.Context, input *struct {
+	common.ActorInput
+	ID      uuid.UUID `path:"id"`
+	ClaimID uuid.UUID `path:"claimId"`
+}) (*struct{}, error) {
+	if err := rs.SourceService.DeleteClaim(input.WithActor(c), input.ID, input.ClaimID); err != nil {
+		return nil, sourceError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs SourceResources) createAlternative(c context.Context, input *struct {
+	ID   uuid.UUID `path:"id"`
+	Body createAlternativeCommandBody
+}) (*struct {
+	Body alternativeResponseBody
+}, error) {
+	alternative, err := rs.SourceService.CreateAlternative(c, input.ID, input.Body)
+	if err != nil {
+		return nil, sourceError(err)
+	}
+
+	return &struct {
+		Body alternativeResponseBody
+	}{
+		Body: alternative,
+	}, nil
+}
+
+func (rs SourceResources) deleteAlternative(c context.Context, input *struct {
+	ID            uuid.UUID `path:"id"`
+	AlternativeID uuid.UUID `path:"alternativeId"`
+}) (*struct{}, error) {
+	if err := rs.SourceService.DeleteAlternative(c, input.ID, input.AlternativeID); err != nil {
+		return nil, sourceError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs SourceResources) preferAlternative(c context.Context, input *struct {
+	common.ActorInput
+	ID            uuid.UUID `path:"id"`
+	AlternativeID uuid.UUID `path:"alternativeId"`
+}) (*struct{}, error) {
+	if err := rs.SourceService.PreferAlternative(input.WithActor(c), input.ID, input.AlternativeID); err != nil {
+		return nil, sourceError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func sourceError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound),
+		errors.Is(err, common.ErrSourceNotFound),
+		errors.Is(err, common.ErrClaimNotFound),
+		errors.Is(err, common.ErrAlternativeNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, common.ErrInvalidClaim),
+		errors.Is(err, common.ErrImpactNotFound),
+		errors.Is(err, common.ErrInvalidAlternative):
+		return huma.Error422UnprocessableEntity(err.Error())
+	case errors.Is(err, common.ErrAlternativeAlreadyExists):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return err
+	}
+}
+
+func (rs SourceResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "get-record-sources",
+		Method:      http.MethodGet,
+		Path:        "/records/{id}/sources",
+		Description: "Lists the sources of a record with the claims each makes about its fields and impacts.",
+	}, rs.getSources)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-record-source",
+		Method:      http.MethodPost,
+		Path:        "/records/{id}/sources",
+	}, rs.createSource)
+	huma.Register(s, huma.Operation{
+		OperationID: "delete-record-source",
+		Method:      http.MethodDelete,
+		Path:        "/records/{id}/sources/{sourceId}",
+		Description: "Deletes a source along with its claims. A reviewed record left with a start date, end date or impact no source backs goes back to pending.",
+	}, rs.deleteSource)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-claim",
+		Method:      http.MethodPost,
+		Path:        "/records/{id}/sources/{sourceId}/claims",
+		Description: "States that the source backs a field or an impact of the record. Before a record can be reviewed its start date, end date and every impact need a claim.",
+	}, rs.createClaim)
+	huma.Register(s, huma.Operation{
+		OperationID: "delete-claim",
+		Method:      http.MethodDelete,
+		Path:        "/records/{id}/claims/{claimId}",
+		Description: "Deletes a claim. A reviewed record left with a start date, end date or impact no source backs goes back to pending.",
+	}, rs.deleteClaim)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-alternative-value",
+		Method:      http.MethodPost,
+		Path:        "/records/{id}/alternatives",
+		Description: "Proposes a value competing with the value a field of the record holds. Sources back it through claims on the alternative value.",
+	}, rs.createAlternative)
+	huma.Register(s, huma.Operation{
+		OperationID: "delete-alternative-value",
+		Method:      http.MethodDelete,
+		Path:        "/records/{id}/alternatives/{alternativeId}",
+		Description: "Deletes a competing value along with the claims backing it.",
+	}, rs.deleteAlternative)
+	huma.Register(s, huma.Operation{
+		OperationID: "prefer-alternative-value",
+		Method:      http.MethodPost,
+		Path:        "/records/{id}/alternatives/{alternativeId}/prefer",
+		Description: "Makes the competing value the value the field holds. The value held until then becomes a competing value, and the claims backing either value move along. A reviewed record left with a start date, end date or impact no source backs goes back to pending.",
+	}, rs.preferAlternative)
+}
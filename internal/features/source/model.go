@@ -0,0 +1,172 @@
+package source
+
+import (
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// SourceAggregate is a source with the claims it makes about its record.
+type SourceAggregate struct {
+	model.Source
+	Claims []model.Claim
+}
+
+// recordField reads and writes a field of a record that can hold competing
+// values, as the string alternative values are stored as.
+type recordField struct {
+	column Column
+	get    func(r model.Record) *string
+	set    func(r *model.Record, value string)
+}
+
+var recordFields = map[record.ClaimField]recordField{
+	record.ClaimTitle: {
+		Record.Title,
+		func(r model.Record) *string { return &r.Title },
+		func(r *model.Record, value string) { r.Title = value },
+	},
+	record.ClaimDescription: {
+		Record.Description,
+		func(r model.Record) *string { return &r.Description },
+		func(r *model.Record, value string) { r.Description = value },
+	},
+	record.ClaimLocation: {
+		Record.Location,
+		func(r model.Record) *string { return r.Location },
+		func(r *model.Record, value string) { r.Location = &value },
+	},
+	record.ClaimSignificance: {
+		Record.Significance,
+		func(r model.Record) *string { return r.Significance },
+		func(r *model.Record, value string) { r.Significance = &value },
+	},
+	record.ClaimStartDate: {
+		Record.StartDate,
+		func(r model.Record) *string { return date(r.StartDate) },
+		func(r *model.Record, value string) { r.StartDate = common.ToTime(value) },
+	},
+	record.ClaimEndDate: {
+		Record.EndDate,
+		func(r model.Record) *string { return date(r.EndDate) },
+		func(r *model.Record, value string) { r.EndDate = common.ToTime(value) },
+	},
+}
+
+func date(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	value := common.ToDateString(t)
+	return &value
+}
+
+type createSourceCommandBody struct {
+	Title          string            `json:"title" minLength:"1" maxLength:"255"`
+	Type           record.SourceType `json:"type" enum:"book,article,chapter,website,archive,other"`
+	Authors        []string          `json:"authors,omitempty" maxItems:"50" doc:"Names written as Family, Given"`
+	Year           *int16            `json:"year,omitempty" doc:"Year of publication"`
+	Publisher      *string           `json:"publisher,omitempty" maxLength:"255"`
+	ContainerTitle *string           `json:"containerTitle,omitempty" maxLength:"255" doc:"Journal of an article or book of a chapter"`
+	Url            string            `json:"url" maxLength:"255"`
+	Description    *string           `json:"description,omitempty" maxLength:"255"`
+}
+
+type createClaimCommandBody struct {
+	Field         *record.ClaimField `json:"field,omitempty" enum:"title,description,location,significance,startDate,endDate,type" doc:"Field of the record the source backs, left out when it backs an impact"`
+	ImpactID      *uuid.UUID         `json:"impactId,omitempty" doc:"Impact of the record the source backs, left out when it backs a field"`
+	AlternativeID *uuid.UUID         `json:"alternativeId,omitempty" doc:"Competing value the source backs instead of the value the field holds, the field may then be left out"`
+	Confidence    record.Confidence  `json:"confidence" enum:"low,medium,high"`
+	Page          *string            `json:"page,omitempty" maxLength:"50" doc:"Page or page range of the source, such as 12-14"`
+	Quote         *string            `json:"quote,omitempty" maxLength:"1000"`
+}
+
+type claimResponseBody struct {
+	ID            uuid.UUID          `json:"id"`
+	Field         *record.ClaimField `json:"field"`
+	ImpactID      *uuid.UUID         `json:"impactId"`
+	AlternativeID *uuid.UUID         `json:"alternativeId"`
+	Confidence    record.Confidence  `json:"confidence"`
+	Page          *string            `json:"page"`
+	Quote         *string            `json:"quote"`
+}
+
+type createAlternativeCommandBody struct {
+	Field record.ClaimField `json:"field" enum:"title,description,location,significance,startDate,endDate"`
+	Value string            `json:"value" minLength:"1" maxLength:"255" doc:"Competing value, dates formatted as YYYY-MM-DD"`
+}
+
+type alternativeResponseBody struct {
+	ID    uuid.UUID         `json:"id"`
+	Field record.ClaimField `json:"field"`
+	Value string            `json:"value"`
+}
+
+type sourceResponseBody struct {
+	ID             uuid.UUID           `json:"id"`
+	RecordID       uuid.UUID           `json:"recordId"`
+	Title          string              `json:"title"`
+	Type           record.SourceType   `json:"type"`
+	Authors        []string            `json:"authors"`
+	Year           *int16              `json:"year"`
+	Publisher      *string             `json:"publisher"`
+	ContainerTitle *string             `json:"containerTitle"`
+	Url            string              `json:"url"`
+	Description    *string             `json:"description"`
+	Claims         []claimResponseBody `json:"claims"`
+}
+
+func mapClaimResponseBody(claim model.Claim, index int) claimResponseBody {
+	response := claimResponseBody{
+		ID:            claim.ID,
+		ImpactID:      claim.ImpactID,
+		AlternativeID: claim.AlternativeID,
+		Confidence:    record.ConfidenceFromInt16(claim.Confidence),
+		Page:          claim.Page,
+		Quote:         claim.Quote,
+	}
+	if claim.Field != nil {
+		field := record.ClaimField(*claim.Field)
+		response.Field = &field
+	}
+	return response
+}
+
+func mapAlternativeResponseBody(alternative model.AlternativeValue, index int) alternativeResponseBody {
+	return alternativeResponseBody{
+		ID:    alternative.ID,
+		Field: record.ClaimField(alternative.Field),
+		Value: alternative.Value,
+	}
+}
+
+func mapSourceResponseBody(source SourceAggregate, index int) sourceResponseBody {
+	claims := lo.Map(source.Claims, mapClaimResponseBody)
+	if claims == nil {
+		claims = []claimResponseBody{}
+	}
+	authors := record.SourceAuthors(source.Source)
+	if authors == nil {
+		authors = []string{}
+	}
+	return sourceResponseBody{
+		ID:             source.ID,
+		RecordID:       source.RecordID,
+		Title:          source.Title,
+		Type:           record.SourceTypeFromInt16(source.Type),
+		Authors:        authors,
+		Year:           source.Year,
+		Publisher:      source.Publisher,
+		ContainerTitle: source.ContainerTitle,
+		Url:            source.URL,
+		Description:    source.Description,
+		Claims:         claims,
+	}
+}
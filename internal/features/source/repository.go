@@ -0,0 +1,418 @@
+package source
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ISourceRepository interface {
+	GetRecord(c context.Context, id uuid.UUID) (model.Record, error)
+	GetImpact(c context.Context, id uuid.UUID) (model.Impact, error)
+	GetSources(c context.Context, recordId uuid.UUID) ([]SourceAggregate, error)
+	GetSource(c context.Context, recordId uuid.UUID, id uuid.UUID) (SourceAggregate, error)
+	CreateSource(c context.Context, source model.Source) (model.Source, error)
+	DeleteSource(c context.Context, recordId uuid.UUID, id uuid.UUID) error
+	CreateClaim(c context.Context, claim model.Claim) (model.Claim, error)
+	DeleteClaim(c context.Context, recordId uuid.UUID, id uuid.UUID) error
+	GetAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) (model.AlternativeValue, error)
+	CreateAlternative(c context.Context, alternative model.AlternativeValue) (model.AlternativeValue, error)
+	DeleteAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) error
+	PreferAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) error
+}
+
+type SourceRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) ISourceRepository {
+	return SourceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r SourceRepository) GetRecord(c context.Context, id uuid.UUID) (model.Record, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.EQ(UUID(id)))
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return model.Record{}, fmt.Errorf("failed to get record: %w", err)
+	}
+	if len(records) == 0 {
+		return model.Record{}, common.ErrRecordNotFound
+	}
+	return records[0], nil
+}
+
+func (r SourceRepository) GetImpact(c context.Context, id uuid.UUID) (model.Impact, error) {
+	stmt := SELECT(Impact.AllColumns).
+		FROM(Impact).
+		WHERE(Impact.ID.EQ(UUID(id)))
+
+	var impacts []model.Impact
+	if err := stmt.QueryContext(c, r.db, &impacts); err != nil {
+		return model.Impact{}, fmt.Errorf("failed to get impact: %w", err)
+	}
+	if len(impacts) == 0 {
+		return model.Impact{}, common.ErrImpactNotFound
+	}
+	return impacts[0], nil
+}
+
+func (r SourceRepository) GetSources(c context.Context, recordId uuid.UUID) ([]SourceAggregate, error) {
+	stmt := SELECT(Source.AllColumns, Claim.AllColumns).
+		FROM(Source.LEFT_JOIN(Claim, Claim.SourceID.EQ(Source.ID))).
+		WHERE(Source.RecordID.EQ(UUID(recordId))).
+		ORDER_BY(Source.Title, Source.ID, Claim.CreatedAt)
+
+	var dest []SourceAggregate
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("failed to get sources: %w", err)
+	}
+	return dest, nil
+}
+
+func (r SourceRepository) GetSource(c context.Context, recordId uuid.UUID, id uuid.UUID) (SourceAggregate, error) {
+	stmt := SELECT(Source.AllColumns, Claim.AllColumns).
+		FROM(Source.LEFT_JOIN(Claim, Claim.SourceID.EQ(Source.ID))).
+		WHERE(Source.ID.EQ(UUID(id)).AND(Source.RecordID.EQ(UUID(recordId)))).
+		ORDER_BY(Claim.CreatedAt)
+
+	var dest []SourceAggregate
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return SourceAggregate{}, fmt.Errorf("failed to get source: %w", err)
+	}
+	if len(dest) == 0 {
+		return SourceAggregate{}, common.ErrSourceNotFound
+	}
+	return dest[0], nil
+}
+
+func (r SourceRepository) CreateSource(c context.Context, source model.Source) (model.Source, error) {
+	stmt := Source.INSERT(Source.MutableColumns).
+		MODEL(source).
+		RETURNING(Source.AllColumns)
+
+	var dest model.Source
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return model.Source{}, fmt.Errorf("failed to create source: %w", err)
+	}
+	return dest, nil
+}
+
+// DeleteSource deletes a source of a record along with its claims. A
+// reviewed record left with facts no source backs goes back to pending.
+func (r SourceRepository) DeleteSource(c context.Context, recordId uuid.UUID, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = common.SetActor(c, tx); err != nil {
+		return err
+	}
+
+	record, err := lockRecord(c, tx, recordId)
+	if err != nil {
+		return err
+	}
+
+	stmt := Source.DELETE().
+		WHERE(Source.ID.EQ(UUID(id)).AND(Source.RecordID.EQ(UUID(recordId))))
+
+	result, err := stmt.ExecContext(c, tx)
+	if err != nil {
+		return fmt.Errorf("failed to delete source: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete source: %w", err)
+	} else if rows == 0 {
+		return common.ErrSourceNotFound
+	}
+
+	if err = r.reopenUnbacked(c, tx, record); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r SourceRepository) CreateClaim(c context.Context, claim model.Claim) (model.Claim, error) {
+	stmt := Claim.INSERT(Claim.SourceID, Claim.Field, Claim.ImpactID, Claim.Confidence, Claim.Page, Claim.Quote).
+		MODEL(claim).
+		RETURNING(Claim.AllColumns)
+
+	var dest model.Claim
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return model.Claim{}, fmt.Errorf("failed to create claim: %w", err)
+	}
+	return dest, nil
+}
+
+// DeleteClaim deletes a claim made by one of the sources of a record. A
+// reviewed record left with facts no source backs goes back to pending.
+func (r SourceRepository) DeleteClaim(c context.Context, recordId uuid.UUID, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = common.SetActor(c, tx); err != nil {
+		return err
+	}
+
+	record, err := lockRecord(c, tx, recordId)
+	if err != nil {
+		return err
+	}
+
+	stmt := Claim.DELETE().
+		WHERE(
+			Claim.ID.EQ(UUID(id)).
+				AND(Claim.SourceID.IN(
+					SELECT(Source.ID).
+						FROM(Source).
+						WHERE(Source.RecordID.EQ(UUID(recordId))),
+				)),
+		)
+
+	result, err := stmt.ExecContext(c, tx)
+	if err != nil {
+		return fmt.Errorf("failed to delete claim: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete claim: %w", err)
+	} else if rows == 0 {
+		return common.ErrClaimNotFound
+	}
+
+	if err = r.reopenUnbacked(c, tx, record); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r SourceRepository) GetAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) (model.AlternativeValue, error) {
+	stmt := SELECT(AlternativeValue.AllColumns).
+		FROM(AlternativeValue).
+		WHERE(AlternativeValue.ID.EQ(UUID(id)).AND(AlternativeValue.RecordID.EQ(UUID(recordId))))
+
+	var dest []model.AlternativeValue
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return model.AlternativeValue{}, fmt.Errorf("failed to get alternative value: %w", err)
+	}
+	if len(dest) == 0 {
+		return model.AlternativeValue{}, common.ErrAlternativeNotFound
+	}
+	return dest[0], nil
+}
+
+func (r SourceRepository) CreateAlternative(c context.Context, alternative model.AlternativeValue) (model.AlternativeValue, error) {
+	stmt := AlternativeValue.INSERT(AlternativeValue.RecordID, AlternativeValue.Field, AlternativeValue.Value).
+		MODEL(alternative).
+		RETURNING(AlternativeValue.AllColumns)
+
+	var dest model.AlternativeValue
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return model.AlternativeValue{}, alternativeWriteError(err)
+	}
+	return dest, nil
+}
+
+// DeleteAlternative deletes a competing value along with the claims backing
+// it.
+func (r SourceRepository) DeleteAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) error {
+	stmt := AlternativeValue.DELETE().
+		WHERE(AlternativeValue.ID.EQ(UUID(id)).AND(AlternativeValue.RecordID.EQ(UUID(recordId))))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to delete alternative value: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete alternative value: %w", err)
+	} else if rows == 0 {
+		return common.ErrAlternativeNotFound
+	}
+	return nil
+}
+
+// PreferAlternative makes a competing value the value the record holds. The
+// value held until then becomes the competing value, and the claims backing
+// either value move along with it. A reviewed record left with facts no
+// source backs goes back to pending.
+func (r SourceRepository) PreferAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = common.SetActor(c, tx); err != nil {
+		return err
+	}
+
+	held, err := lockRecord(c, tx, recordId)
+	if err != nil {
+		return err
+	}
+
+	alternativeStmt := SELECT(AlternativeValue.AllColumns).
+		FROM(AlternativeValue).
+		WHERE(AlternativeValue.ID.EQ(UUID(id)).AND(AlternativeValue.RecordID.EQ(UUID(recordId))))
+
+	var alternatives []model.AlternativeValue
+	if err = alternativeStmt.QueryContext(c, tx, &alternatives); err != nil {
+		return fmt.Errorf("failed to get alternative value: %w", err)
+	}
+	if len(alternatives) == 0 {
+		return common.ErrAlternativeNotFound
+	}
+	alternative := alternatives[0]
+
+	field := recordFields[record.ClaimField(alternative.Field)]
+	heldValue := field.get(held)
+	preferred := held
+	field.set(&preferred, alternative.Value)
+
+	recordStmt := Record.UPDATE(field.column).
+		MODEL(preferred).
+		WHERE(Record.ID.EQ(UUID(recordId)))
+	if _, err = recordStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to update record: %w", err)
+	}
+
+	claimStmt := Claim.UPDATE(Claim.AlternativeID).
+		SET(CASE().WHEN(Claim.AlternativeID.IS_NULL()).THEN(CAST(UUID(id)).AS("uuid")).ELSE(NULL)).
+		WHERE(
+			Claim.Field.EQ(String(alternative.Field)).
+				AND(Claim.AlternativeID.IS_NULL().OR(Claim.AlternativeID.EQ(UUID(id)))).
+				AND(Claim.SourceID.IN(
+					SELECT(Source.ID).
+						FROM(Source).
+						WHERE(Source.RecordID.EQ(UUID(recordId))),
+				)),
+		)
+	if _, err = claimStmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to move claims: %w", err)
+	}
+
+	// An empty value is not worth keeping as a competing value, nor are the
+	// claims backing it
+	if heldValue == nil || *heldValue == "" {
+		_, err = AlternativeValue.DELETE().
+			WHERE(AlternativeValue.ID.EQ(UUID(id))).
+			ExecContext(c, tx)
+	} else {
+		_, err = AlternativeValue.UPDATE(AlternativeValue.Value).
+			SET(String(*heldValue)).
+			WHERE(AlternativeValue.ID.EQ(UUID(id))).
+			ExecContext(c, tx)
+	}
+	if err != nil {
+		return alternativeWriteError(err)
+	}
+
+	// The value now held may lack the sources the one held until then had
+	if err = r.reopenUnbacked(c, tx, preferred); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// lockRecord locks a record against concurrent changes until the transaction
+// ends.
+func lockRecord(c context.Context, tx *sql.Tx, id uuid.UUID) (model.Record, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.EQ(UUID(id))).
+		FOR(UPDATE())
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, tx, &records); err != nil {
+		return model.Record{}, fmt.Errorf("failed to lock record: %w", err)
+	}
+	if len(records) == 0 {
+		return model.Record{}, common.ErrRecordNotFound
+	}
+	return records[0], nil
+}
+
+// reopenUnbacked moves a reviewed record back to pending when some of the
+// facts a reviewed record needs sources for are no longer backed by any.
+func (r SourceRepository) reopenUnbacked(c context.Context, tx *sql.Tx, rec model.Record) error {
+	if rec.Status != record.Reviewed.ToInt16() {
+		return nil
+	}
+
+	claimStmt := SELECT(Claim.AllColumns, Source.AllColumns).
+		FROM(Claim.INNER_JOIN(Source, Source.ID.EQ(Claim.SourceID))).
+		WHERE(Source.RecordID.EQ(UUID(rec.ID)))
+
+	var claims []record.ClaimEntity
+	if err := claimStmt.QueryContext(c, tx, &claims); err != nil {
+		return fmt.Errorf("failed to get claims: %w", err)
+	}
+
+	impactStmt := SELECT(Impact.AllColumns).
+		FROM(Impact).
+		WHERE(Impact.RecordID.EQ(UUID(rec.ID)))
+
+	var impacts []record.ImpactEntity
+	if err := impactStmt.QueryContext(c, tx, &impacts); err != nil {
+		return fmt.Errorf("failed to get impacts: %w", err)
+	}
+
+	missing := record.CheckProvenance(claims, impacts)
+	if missing == nil {
+		return nil
+	}
+	if !errors.Is(missing, common.ErrMissingProvenance) {
+		return missing
+	}
+
+	stmt := Record.UPDATE(Record.Status).
+		SET(Int16(record.PendingReview.ToInt16())).
+		WHERE(Record.ID.EQ(UUID(rec.ID)))
+	if _, err := stmt.ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to reopen record: %w", err)
+	}
+	r.logger.Info("moved record back to pending", "record", rec.ID, "reason", missing)
+	return nil
+}
+
+func alternativeWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return common.ErrAlternativeAlreadyExists
+	}
+	return fmt.Errorf("failed to write alternative value: %w", err)
+}
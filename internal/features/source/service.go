@@ -0,0 +1,209 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ISourceService interface {
+	GetSources(c context.Context, recordId uuid.UUID) ([]sourceResponseBody, error)
+	CreateSource(c context.Context, recordId uuid.UUID, command createSourceCommandBody) (sourceResponseBody, error)
+	DeleteSource(c context.Context, recordId uuid.UUID, id uuid.UUID) error
+	CreateClaim(c context.Context, recordId uuid.UUID, sourceId uuid.UUID, command createClaimCommandBody) (sourceResponseBody, error)
+	DeleteClaim(c context.Context, recordId uuid.UUID, id uuid.UUID) error
+	CreateAlternative(c context.Context, recordId uuid.UUID, command createAlternativeCommandBody) (alternativeResponseBody, error)
+	DeleteAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) error
+	PreferAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) error
+}
+
+type SourceService struct {
+	sourceRepository ISourceRepository
+	logger           *slog.Logger
+}
+
+func NewSourceService(sourceRepository ISourceRepository, logger *slog.Logger) ISourceService {
+	return SourceService{
+		sourceRepository: sourceRepository,
+		logger:           logger,
+	}
+}
+
+func (s SourceService) GetSources(c context.Context, recordId uuid.UUID) ([]sourceResponseBody, error) {
+	if _, err := s.sourceRepository.GetRecord(c, recordId); err != nil {
+		return nil, err
+	}
+
+	sources, err := s.sourceRepository.GetSources(c, recordId)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(sources, mapSourceResponseBody), nil
+}
+
+func (s SourceService) CreateSource(c context.Context, recordId uuid.UUID, command createSourceCommandBody) (sourceResponseBody, error) {
+	if _, err := s.sourceRepository.GetRecord(c, recordId); err != nil {
+		return sourceResponseBody{}, err
+	}
+
+	authors := command.Authors
+	if authors == nil {
+		authors = []string{}
+	}
+	encoded, err := json.Marshal(authors)
+	if err != nil {
+		return sourceResponseBody{}, fmt.Errorf("failed to encode authors: %w", err)
+	}
+
+	source, err := s.sourceRepository.CreateSource(c, model.Source{
+		RecordID:       recordId,
+		Title:          command.Title,
+		Type:           command.Type.ToInt16(),
+		Authors:        string(encoded),
+		Year:           command.Year,
+		Publisher:      command.Publisher,
+		ContainerTitle: command.ContainerTitle,
+		URL:            command.Url,
+		Description:    command.Description,
+	})
+	if err != nil {
+		return sourceResponseBody{}, err
+	}
+	s.logger.Info("created source", "record", recordId, "source", source.ID)
+
+	return mapSourceResponseBody(SourceAggregate{Source: source}, 0), nil
+}
+
+func (s SourceService) DeleteSource(c context.Context, recordId uuid.UUID, id uuid.UUID) error {
+	if err := s.sourceRepository.DeleteSource(c, recordId, id); err != nil {
+		return err
+	}
+	s.logger.Info("deleted source", "record", recordId, "source", id)
+	return nil
+}
+
+// CreateClaim states that a source of a record backs one of its fields, a
+// value competing with it or one of its impacts.
+func (s SourceService) CreateClaim(c context.Context, recordId uuid.UUID, sourceId uuid.UUID, command createClaimCommandBody) (sourceResponseBody, error) {
+	backsField := command.Field != nil || command.AlternativeID != nil
+	if backsField == (command.ImpactID != nil) {
+		return sourceResponseBody{}, common.ErrInvalidClaim
+	}
+	if _, err := s.sourceRepository.GetSource(c, recordId, sourceId); err != nil {
+		return sourceResponseBody{}, err
+	}
+	if command.AlternativeID != nil {
+		alternative, err := s.sourceRepository.GetAlternative(c, recordId, *command.AlternativeID)
+		if err != nil {
+			return sourceResponseBody{}, err
+		}
+		field := record.ClaimField(alternative.Field)
+		if command.Field != nil && *command.Field != field {
+			return sourceResponseBody{}, common.ErrInvalidClaim
+		}
+		command.Field = &field
+	}
+	if command.ImpactID != nil {
+		impact, err := s.sourceRepository.GetImpact(c, *command.ImpactID)
+		if err != nil {
+			return sourceResponseBody{}, err
+		}
+		if impact.RecordID != recordId {
+			return sourceResponseBody{}, common.ErrInvalidClaim
+		}
+	}
+
+	claim := model.Claim{
+		SourceID:      sourceId,
+		ImpactID:      command.ImpactID,
+		AlternativeID: command.AlternativeID,
+		Confidence:    command.Confidence.ToInt16(),
+		Page:          command.Page,
+		Quote:         command.Quote,
+	}
+	if command.Field != nil {
+		field := string(*command.Field)
+		claim.Field = &field
+	}
+
+	claim, err := s.sourceRepository.CreateClaim(c, claim)
+	if err != nil {
+		return sourceResponseBody{}, err
+	}
+	s.logger.Info("created claim", "record", recordId, "source", sourceId, "claim", claim.ID)
+
+	source, err := s.sourceRepository.GetSource(c, recordId, sourceId)
+	if err != nil {
+		return sourceResponseBody{}, err
+	}
+	return mapSourceResponseBody(source, 0), nil
+}
+
+func (s SourceService) DeleteClaim(c context.Context, recordId uuid.UUID, id uuid.UUID) error {
+	if err := s.sourceRepository.DeleteClaim(c, recordId, id); err != nil {
+		return err
+	}
+	s.logger.Info("deleted claim", "record", recordId, "claim", id)
+	return nil
+}
+
+// CreateAlternative proposes a value competing with the value a field of a
+// record holds.
+func (s SourceService) CreateAlternative(c context.Context, recordId uuid.UUID, command createAlternativeCommandBody) (alternativeResponseBody, error) {
+	current, err := s.sourceRepository.GetRecord(c, recordId)
+	if err != nil {
+		return alternativeResponseBody{}, err
+	}
+
+	field, ok := recordFields[command.Field]
+	if !ok {
+		return alternativeResponseBody{}, fmt.Errorf("%w: %s cannot hold competing values", common.ErrInvalidAlternative, command.Field)
+	}
+	value := command.Value
+	if command.Field == record.ClaimStartDate || command.Field == record.ClaimEndDate {
+		date := common.ToTime(value)
+		if date == nil {
+			return alternativeResponseBody{}, fmt.Errorf("%w: %q is not a date formatted as YYYY-MM-DD", common.ErrInvalidAlternative, value)
+		}
+		value = common.ToDateString(date)
+	}
+	if held := field.get(current); held != nil && *held == value {
+		return alternativeResponseBody{}, fmt.Errorf("%w: %s already holds %q", common.ErrInvalidAlternative, command.Field, value)
+	}
+
+	alternative, err := s.sourceRepository.CreateAlternative(c, model.AlternativeValue{
+		RecordID: recordId,
+		Field:    string(command.Field),
+		Value:    value,
+	})
+	if err != nil {
+		return alternativeResponseBody{}, err
+	}
+	s.logger.Info("created alternative value", "record", recordId, "field", command.Field, "alternative", alternative.ID)
+
+	return mapAlternativeResponseBody(alternative, 0), nil
+}
+
+func (s SourceService) DeleteAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) error {
+	if err := s.sourceRepository.DeleteAlternative(c, recordId, id); err != nil {
+		return err
+	}
+	s.logger.Info("deleted alternative value", "record", recordId, "alternative", id)
+	return nil
+}
+
+func (s SourceService) PreferAlternative(c context.Context, recordId uuid.UUID, id uuid.UUID) error {
+	if err := s.sourceRepository.PreferAlternative(c, recordId, id); err != nil {
+		return err
+	}
+	s.logger.Info("preferred alternative value", "record", recordId, "alternative", id)
+	return nil
+}
@@ -0,0 +1,203 @@
+package term
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+func NewTermResources(conn *sql.DB, logger *slog.Logger) TermResources {
+	return TermResources{
+		logger:      logger,
+		TermService: NewTermService(NewRepository(conn, logger), logger),
+	}
+}
+
+type TermResources struct {
+	TermService ITermService
+	logger      *slog.Logger
+}
+
+func (rs TermResources) getTerms(c context.Context, input *struct {
+	Kind TermKind `query:"kind" enum:"tag,theme,period,region" doc:"Only list terms of this kind"`
+}) (*struct {
+	Body []termResponseBody
+}, error) {
+	terms, err := rs.TermService.GetTerms(c, input.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if terms == nil {
+		terms = []termResponseBody{}
+	}
+
+	return &struct {
+		Body []termResponseBody
+	}{
+		Body: terms,
+	}, nil
+}
+
+func (rs TermResources) getById(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body termResponseBody
+}, error) {
+	term, err := rs.TermService.GetById(c, input.ID)
+	if err != nil {
+		return nil, termError(err)
+	}
+
+	return &struct {
+		Body termResponseBody
+	}{
+		Body: term,
+	}, nil
+}
+
+func (rs TermResources) create(c context.Context, input *struct {
+	Body createTermCommandBody
+}) (*struct {
+	Body termResponseBody
+}, error) {
+	term, err := rs.TermService.Create(c, input.Body)
+	if err != nil {
+		return nil, termError(err)
+	}
+
+	return &struct {
+		Body termResponseBody
+	}{
+		Body: term,
+	}, nil
+}
+
+func (rs TermResources) update(c context.Context, input *struct {
+	ID   uuid.UUID `path:"id"`
+	Body updateTermCommandBody
+}) (*struct {
+	Body termResponseBody
+}, error) {
+	term, err := rs.TermService.Update(c, input.ID, input.Body)
+	if err != nil {
+		return nil, termError(err)
+	}
+
+	return &struct {
+		Body termResponseBody
+	}{
+		Body: term,
+	}, nil
+}
+
+func (rs TermResources) delete(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct{}, error) {
+	if err := rs.TermService.Delete(c, input.ID); err != nil {
+		return nil, termError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs TermResources) getRecordTerms(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body []termResponseBody
+}, error) {
+	terms, err := rs.TermService.GetRecordTerms(c, input.ID)
+	if err != nil {
+		return nil, termError(err)
+	}
+
+	if terms == nil {
+		terms = []termResponseBody{}
+	}
+
+	return &struct {
+		Body []termResponseBody
+	}{
+		Body: terms,
+	}, nil
+}
+
+func (rs TermResources) replaceRecordTerms(c context.Context, input *struct {
+	ID   uuid.UUID `path:"id"`
+	Body replaceRecordTermsCommandBody
+}) (*struct {
+	Body []termResponseBody
+}, error) {
+	terms, err := rs.TermService.ReplaceRecordTerms(c, input.ID, input.Body)
+	if err != nil {
+		return nil, termError(err)
+	}
+
+	if terms == nil {
+		terms = []termResponseBody{}
+	}
+
+	return &struct {
+		Body []termResponseBody
+	}{
+		Body: terms,
+	}, nil
+}
+
+func termError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrTermNotFound), errors.Is(err, common.ErrRecordNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, common.ErrInvalidTermParent):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, common.ErrTermAlreadyExists), errors.Is(err, common.ErrTermHasChildren):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return err
+	}
+}
+
+func (rs TermResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "get-terms",
+		Method:      http.MethodGet,
+		Path:        "/terms/",
+	}, rs.getTerms)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-term",
+		Method:      http.MethodGet,
+		Path:        "/terms/{id}",
+	}, rs.getById)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-term",
+		Method:      http.MethodPost,
+		Path:        "/terms/",
+	}, rs.create)
+	huma.Register(s, huma.Operation{
+		OperationID: "update-term",
+		Method:      http.MethodPut,
+		Path:        "/terms/{id}",
+	}, rs.update)
+	huma.Register(s, huma.Operation{
+		OperationID: "delete-term",
+		Method:      http.MethodDelete,
+		Path:        "/terms/{id}",
+		Description: "Deletes a term and untags the records tagged with it. Terms with narrower terms cannot be deleted.",
+	}, rs.delete)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-record-terms",
+		Method:      http.MethodGet,
+		Path:        "/records/{id}/terms",
+	}, rs.getRecordTerms)
+	huma.Register(s, huma.Operation{
+		OperationID: "replace-record-terms",
+		Method:      http.MethodPut,
+		Path:        "/records/{id}/terms",
+	}, rs.replaceRecordTerms)
+}
@@ -0,0 +1,46 @@
+package term
+
+import (
+	"historylink/.gen/historylink/public/model"
+
+	"github.com/google/uuid"
+)
+
+type createTermCommandBody struct {
+	Kind        TermKind   `json:"kind" enum:"tag,theme,period,region"`
+	Name        string     `json:"name" minLength:"1" maxLength:"255"`
+	Description *string    `json:"description,omitempty" maxLength:"255"`
+	ParentID    *uuid.UUID `json:"parentId,omitempty" doc:"Broader term of the same kind"`
+}
+
+type updateTermCommandBody struct {
+	Name        string     `json:"name" minLength:"1" maxLength:"255"`
+	Description *string    `json:"description,omitempty" maxLength:"255"`
+	ParentID    *uuid.UUID `json:"parentId,omitempty" doc:"Broader term of the same kind"`
+}
+
+type replaceRecordTermsCommandBody struct {
+	TermIDs []uuid.UUID `json:"termIds"`
+}
+
+type termResponseBody struct {
+	ID          uuid.UUID  `json:"id"`
+	Kind        TermKind   `json:"kind"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	ParentID    *uuid.UUID `json:"parentId"`
+	// RecordCount is the number of records tagged with the term or with a
+	// narrower term, leaving out removed records
+	RecordCount int `json:"recordCount"`
+}
+
+func mapTermResponseBody(term model.Term, recordCount int) termResponseBody {
+	return termResponseBody{
+		ID:          term.ID,
+		Kind:        TermKindFromInt16(term.Kind),
+		Name:        term.Name,
+		Description: term.Description,
+		ParentID:    term.ParentID,
+		RecordCount: recordCount,
+	}
+}
@@ -0,0 +1,275 @@
+package term
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+)
+
+type ITermRepository interface {
+	GetRecord(c context.Context, id uuid.UUID) (model.Record, error)
+	GetTerm(c context.Context, id uuid.UUID) (model.Term, error)
+	GetTerms(c context.Context) ([]model.Term, error)
+	GetUsage(c context.Context) ([]model.RecordTerm, error)
+	GetRecordTerms(c context.Context, recordId uuid.UUID) ([]model.Term, error)
+	Create(c context.Context, term model.Term) (model.Term, error)
+	Update(c context.Context, term model.Term) error
+	Delete(c context.Context, id uuid.UUID) error
+	ReplaceRecordTerms(c context.Context, recordId uuid.UUID, termIds []uuid.UUID) error
+}
+
+type TermRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) ITermRepository {
+	return TermRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r TermRepository) GetRecord(c context.Context, id uuid.UUID) (model.Record, error) {
+	stmt := SELECT(Record.AllColumns).
+		FROM(Record).
+		WHERE(Record.ID.EQ(UUID(id)))
+
+	var records []model.Record
+	if err := stmt.QueryContext(c, r.db, &records); err != nil {
+		return model.Record{}, fmt.Errorf("failed to get record: %w", err)
+	}
+	if len(records) == 0 {
+		return model.Record{}, common.ErrRecordNotFound
+	}
+	return records[0], nil
+}
+
+func (r TermRepository) GetTerm(c context.Context, id uuid.UUID) (model.Term, error) {
+	stmt := SELECT(Term.AllColumns).
+		FROM(Term).
+		WHERE(Term.ID.EQ(UUID(id)))
+
+	var dest []model.Term
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return model.Term{}, fmt.Errorf("failed to get term: %w", err)
+	}
+	if len(dest) == 0 {
+		return model.Term{}, common.ErrTermNotFound
+	}
+	return dest[0], nil
+}
+
+func (r TermRepository) GetTerms(c context.Context) ([]model.Term, error) {
+	stmt := SELECT(Term.AllColumns).
+		FROM(Term).
+		ORDER_BY(Term.Kind, Term.Name)
+
+	var dest []model.Term
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("failed to get terms: %w", err)
+	}
+	return dest, nil
+}
+
+// GetUsage returns which terms every record is tagged with, leaving out
+// removed records.
+func (r TermRepository) GetUsage(c context.Context) ([]model.RecordTerm, error) {
+	stmt := SELECT(RecordTerm.AllColumns).
+		FROM(RecordTerm.INNER_JOIN(Record, Record.ID.EQ(RecordTerm.RecordID))).
+		WHERE(Record.Status.NOT_EQ(Int16(record.Removed.ToInt16())))
+
+	var dest []model.RecordTerm
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("failed to get term usage: %w", err)
+	}
+	return dest, nil
+}
+
+func (r TermRepository) GetRecordTerms(c context.Context, recordId uuid.UUID) ([]model.Term, error) {
+	stmt := SELECT(Term.AllColumns).
+		FROM(Term.INNER_JOIN(RecordTerm, RecordTerm.TermID.EQ(Term.ID))).
+		WHERE(RecordTerm.RecordID.EQ(UUID(recordId))).
+		ORDER_BY(Term.Kind, Term.Name)
+
+	var dest []model.Term
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("failed to get record terms: %w", err)
+	}
+	return dest, nil
+}
+
+func (r TermRepository) Create(c context.Context, term model.Term) (model.Term, error) {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return model.Term{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = r.checkParent(c, tx, term); err != nil {
+		return model.Term{}, err
+	}
+
+	stmt := Term.INSERT(Term.MutableColumns).
+		MODEL(term).
+		RETURNING(Term.AllColumns)
+
+	var dest model.Term
+	if err = stmt.QueryContext(c, tx, &dest); err != nil {
+		return model.Term{}, termWriteError(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return model.Term{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return dest, nil
+}
+
+func (r TermRepository) Update(c context.Context, term model.Term) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = r.checkParent(c, tx, term); err != nil {
+		return err
+	}
+
+	stmt := Term.UPDATE(Term.Name, Term.Description, Term.ParentID).
+		MODEL(term).
+		WHERE(Term.ID.EQ(UUID(term.ID)))
+
+	result, err := stmt.ExecContext(c, tx)
+	if err != nil {
+		return termWriteError(err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to update term: %w", err)
+	} else if rows == 0 {
+		return common.ErrTermNotFound
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// checkParent makes sure the broader term of a term exists, is of the same
+// kind and is not the term itself or one of its narrower terms.
+func (r TermRepository) checkParent(c context.Context, tx *sql.Tx, term model.Term) error {
+	if term.ParentID == nil {
+		return nil
+	}
+
+	// Two terms becoming each other's parent at the same time would each pass
+	// the check on their own, so hierarchy changes run one at a time
+	if _, err := Term.LOCK().IN(LOCK_SHARE_ROW_EXCLUSIVE).ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to lock terms: %w", err)
+	}
+
+	stmt := SELECT(Term.ID, Term.Kind, Term.ParentID).FROM(Term)
+
+	var terms []model.Term
+	if err := stmt.QueryContext(c, tx, &terms); err != nil {
+		return fmt.Errorf("failed to get terms: %w", err)
+	}
+	byId := lo.KeyBy(terms, func(t model.Term) uuid.UUID { return t.ID })
+
+	parent, ok := byId[*term.ParentID]
+	if !ok {
+		return common.ErrTermNotFound
+	}
+	if parent.Kind != term.Kind {
+		return common.ErrInvalidTermParent
+	}
+
+	// Walk up from the new parent; reaching the term means it would end up
+	// below itself
+	for ancestor := &parent; ancestor != nil; {
+		if ancestor.ID == term.ID {
+			return common.ErrInvalidTermParent
+		}
+		if ancestor.ParentID == nil {
+			break
+		}
+		next, ok := byId[*ancestor.ParentID]
+		if !ok {
+			break
+		}
+		ancestor = &next
+	}
+	return nil
+}
+
+func (r TermRepository) Delete(c context.Context, id uuid.UUID) error {
+	result, err := Term.DELETE().WHERE(Term.ID.EQ(UUID(id))).ExecContext(c, r.db)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "term_parent_id_fkey" {
+			return common.ErrTermHasChildren
+		}
+		return fmt.Errorf("failed to delete term: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete term: %w", err)
+	} else if rows == 0 {
+		return common.ErrTermNotFound
+	}
+	return nil
+}
+
+func (r TermRepository) ReplaceRecordTerms(c context.Context, recordId uuid.UUID, termIds []uuid.UUID) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = RecordTerm.DELETE().WHERE(RecordTerm.RecordID.EQ(UUID(recordId))).ExecContext(c, tx); err != nil {
+		return fmt.Errorf("failed to delete record terms: %w", err)
+	}
+
+	if len(termIds) > 0 {
+		recordTerms := lo.Map(termIds, func(id uuid.UUID, index int) model.RecordTerm {
+			return model.RecordTerm{RecordID: recordId, TermID: id}
+		})
+		insertStmt := RecordTerm.INSERT(RecordTerm.AllColumns).
+			MODELS(recordTerms)
+		if _, err = insertStmt.ExecContext(c, tx); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "record_term_term_id_fkey" {
+				return common.ErrTermNotFound
+			}
+			return fmt.Errorf("failed to insert record terms: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// termWriteError reports a clash with the name of another term of the same
+// kind as ErrTermAlreadyExists.
+func termWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return common.ErrTermAlreadyExists
+	}
+	return fmt.Errorf("failed to save term: %w", err)
+}
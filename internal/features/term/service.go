@@ -0,0 +1,218 @@
+package term
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type ITermService interface {
+	GetTerms(c context.Context, kind TermKind) ([]termResponseBody, error)
+	GetById(c context.Context, id uuid.UUID) (termResponseBody, error)
+	Create(c context.Context, command createTermCommandBody) (termResponseBody, error)
+	Update(c context.Context, id uuid.UUID, command updateTermCommandBody) (termResponseBody, error)
+	Delete(c context.Context, id uuid.UUID) error
+	GetRecordTerms(c context.Context, recordId uuid.UUID) ([]termResponseBody, error)
+	ReplaceRecordTerms(c context.Context, recordId uuid.UUID, command replaceRecordTermsCommandBody) ([]termResponseBody, error)
+}
+
+type TermService struct {
+	termRepository ITermRepository
+	logger         *slog.Logger
+}
+
+func NewTermService(termRepository ITermRepository, logger *slog.Logger) ITermService {
+	return TermService{
+		termRepository: termRepository,
+		logger:         logger,
+	}
+}
+
+// TermKind is the vocabulary a term belongs to. Tags are free labels, the
+// other kinds form thesauri of broader and narrower terms.
+type TermKind string
+
+const (
+	Tag    TermKind = "tag"
+	Theme  TermKind = "theme"
+	Period TermKind = "period"
+	Region TermKind = "region"
+)
+
+func TermKindFromInt16(v int16) TermKind {
+	switch v {
+	case 0:
+		return Tag
+	case 1:
+		return Theme
+	case 2:
+		return Period
+	case 3:
+		return Region
+	}
+	return ""
+}
+
+func (k TermKind) ToInt16() int16 {
+	switch k {
+	case Tag:
+		return 0
+	case Theme:
+		return 1
+	case Period:
+		return 2
+	case Region:
+		return 3
+	}
+	return -1
+}
+
+// GetTerms lists the vocabulary, or only the terms of one kind when a kind is
+// given, with the number of records tagged with each term.
+func (s TermService) GetTerms(c context.Context, kind TermKind) ([]termResponseBody, error) {
+	terms, counts, err := s.getVocabulary(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind != "" {
+		terms = lo.Filter(terms, func(term model.Term, index int) bool { return term.Kind == kind.ToInt16() })
+	}
+	return lo.Map(terms, func(term model.Term, index int) termResponseBody {
+		return mapTermResponseBody(term, counts[term.ID])
+	}), nil
+}
+
+func (s TermService) GetById(c context.Context, id uuid.UUID) (termResponseBody, error) {
+	terms, counts, err := s.getVocabulary(c)
+	if err != nil {
+		return termResponseBody{}, err
+	}
+
+	term, ok := lo.Find(terms, func(term model.Term) bool { return term.ID == id })
+	if !ok {
+		return termResponseBody{}, common.ErrTermNotFound
+	}
+	return mapTermResponseBody(term, counts[term.ID]), nil
+}
+
+func (s TermService) Create(c context.Context, command createTermCommandBody) (termResponseBody, error) {
+	term, err := s.termRepository.Create(c, model.Term{
+		Kind:        command.Kind.ToInt16(),
+		Name:        strings.TrimSpace(command.Name),
+		Description: command.Description,
+		ParentID:    command.ParentID,
+	})
+	if err != nil {
+		return termResponseBody{}, err
+	}
+	s.logger.Info("created term", "term", term.ID, "kind", command.Kind)
+
+	return mapTermResponseBody(term, 0), nil
+}
+
+// Update renames a term or moves it in the hierarchy. The kind of a term is
+// fixed, as its broader and narrower terms have to share it.
+func (s TermService) Update(c context.Context, id uuid.UUID, command updateTermCommandBody) (termResponseBody, error) {
+	term, err := s.termRepository.GetTerm(c, id)
+	if err != nil {
+		return termResponseBody{}, err
+	}
+
+	term.Name = strings.TrimSpace(command.Name)
+	term.Description = command.Description
+	term.ParentID = command.ParentID
+	if err = s.termRepository.Update(c, term); err != nil {
+		return termResponseBody{}, err
+	}
+
+	return s.GetById(c, id)
+}
+
+func (s TermService) Delete(c context.Context, id uuid.UUID) error {
+	if err := s.termRepository.Delete(c, id); err != nil {
+		return err
+	}
+	s.logger.Info("deleted term", "term", id)
+	return nil
+}
+
+func (s TermService) GetRecordTerms(c context.Context, recordId uuid.UUID) ([]termResponseBody, error) {
+	if _, err := s.termRepository.GetRecord(c, recordId); err != nil {
+		return nil, err
+	}
+
+	recordTerms, err := s.termRepository.GetRecordTerms(c, recordId)
+	if err != nil {
+		return nil, err
+	}
+	_, counts, err := s.getVocabulary(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(recordTerms, func(term model.Term, index int) termResponseBody {
+		return mapTermResponseBody(term, counts[term.ID])
+	}), nil
+}
+
+func (s TermService) ReplaceRecordTerms(c context.Context, recordId uuid.UUID, command replaceRecordTermsCommandBody) ([]termResponseBody, error) {
+	if _, err := s.termRepository.GetRecord(c, recordId); err != nil {
+		return nil, err
+	}
+
+	termIds := lo.Uniq(command.TermIDs)
+	if err := s.termRepository.ReplaceRecordTerms(c, recordId, termIds); err != nil {
+		return nil, err
+	}
+	s.logger.Info("replaced record terms", "record", recordId, "terms", len(termIds))
+
+	return s.GetRecordTerms(c, recordId)
+}
+
+// getVocabulary returns all terms with the number of records tagged with each
+// term or a narrower one, counting every record once per term.
+func (s TermService) getVocabulary(c context.Context) ([]model.Term, map[uuid.UUID]int, error) {
+	terms, err := s.termRepository.GetTerms(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	usage, err := s.termRepository.GetUsage(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make(map[uuid.UUID]map[uuid.UUID]bool)
+	for _, recordTerm := range usage {
+		if records[recordTerm.TermID] == nil {
+			records[recordTerm.TermID] = map[uuid.UUID]bool{}
+		}
+		records[recordTerm.TermID][recordTerm.RecordID] = true
+	}
+
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, term := range terms {
+		if term.ParentID != nil {
+			children[*term.ParentID] = append(children[*term.ParentID], term.ID)
+		}
+	}
+
+	counts := make(map[uuid.UUID]int, len(terms))
+	for _, term := range terms {
+		tagged := map[uuid.UUID]bool{}
+		for narrower := []uuid.UUID{term.ID}; len(narrower) > 0; narrower = narrower[1:] {
+			for id := range records[narrower[0]] {
+				tagged[id] = true
+			}
+			narrower = append(narrower, children[narrower[0]]...)
+		}
+		counts[term.ID] = len(tagged)
+	}
+	return terms, counts, nil
+}
@@ -0,0 +1,196 @@
+package vocabulary
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// NewVocabularyResources creates the admin API for the configurable
+// vocabularies.
+func NewVocabularyResources(conn *sql.DB, connStr string, logger *slog.Logger) VocabularyResources {
+	return VocabularyResources{
+		logger:            logger,
+		VocabularyService: NewVocabularyService(NewRepository(conn, connStr, logger), conn, logger),
+	}
+}
+
+type VocabularyResources struct {
+	VocabularyService IVocabularyService
+	logger            *slog.Logger
+}
+
+func (rs VocabularyResources) getValues(c context.Context, input *struct {
+	Vocabulary Vocabulary `path:"vocabulary" enum:"categories,types,statuses"`
+}) (*struct {
+	Body []valueResponseBody
+}, error) {
+	values, err := rs.VocabularyService.GetValues(c, input.Vocabulary)
+	if err != nil {
+		return nil, err
+	}
+
+	return &struct {
+		Body []valueResponseBody
+	}{
+		Body: values,
+	}, nil
+}
+
+func (rs VocabularyResources) create(c context.Context, input *struct {
+	Vocabulary Vocabulary `path:"vocabulary" enum:"categories,types,statuses"`
+	Body       valueCommandBody
+}) (*struct {
+	Body []valueResponseBody
+}, error) {
+	values, err := rs.VocabularyService.Create(c, input.Vocabulary, input.Body)
+	if err != nil {
+		return nil, vocabularyError(err)
+	}
+
+	return &struct {
+		Body []valueResponseBody
+	}{
+		Body: values,
+	}, nil
+}
+
+func (rs VocabularyResources) rename(c context.Context, input *struct {
+	Vocabulary Vocabulary `path:"vocabulary" enum:"categories,types,statuses"`
+	Name       string     `path:"name"`
+	Body       valueCommandBody
+}) (*struct {
+	Body []valueResponseBody
+}, error) {
+	values, err := rs.VocabularyService.Rename(c, input.Vocabulary, input.Name, input.Body)
+	if err != nil {
+		return nil, vocabularyError(err)
+	}
+
+	return &struct {
+		Body []valueResponseBody
+	}{
+		Body: values,
+	}, nil
+}
+
+func (rs VocabularyResources) delete(c context.Context, input *struct {
+	Vocabulary Vocabulary `path:"vocabulary" enum:"categories,types,statuses"`
+	Name       string     `path:"name"`
+}) (*struct{}, error) {
+	if err := rs.VocabularyService.Delete(c, input.Vocabulary, input.Name); err != nil {
+		return nil, vocabularyError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs VocabularyResources) getAttributeSchema(c context.Context, input *struct {
+	Name string `path:"name"`
+}) (*struct {
+	Body map[string]any
+}, error) {
+	schema, err := rs.VocabularyService.GetAttributeSchema(c, input.Name)
+	if err != nil {
+		return nil, vocabularyError(err)
+	}
+
+	return &struct {
+		Body map[string]any
+	}{
+		Body: schema,
+	}, nil
+}
+
+func (rs VocabularyResources) setAttributeSchema(c context.Context, input *struct {
+	Name string `path:"name"`
+	Body map[string]any
+}) (*struct {
+	Body map[string]any
+}, error) {
+	schema, err := rs.VocabularyService.SetAttributeSchema(c, input.Name, input.Body)
+	if err != nil {
+		return nil, vocabularyError(err)
+	}
+
+	return &struct {
+		Body map[string]any
+	}{
+		Body: schema,
+	}, nil
+}
+
+func (rs VocabularyResources) deleteAttributeSchema(c context.Context, input *struct {
+	Name string `path:"name"`
+}) (*struct{}, error) {
+	if err := rs.VocabularyService.DeleteAttributeSchema(c, input.Name); err != nil {
+		return nil, vocabularyError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func vocabularyError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrInvalidAttributeSchema):
+		return huma.Error422UnprocessableEntity(err.Error())
+	case errors.Is(err, common.ErrVocabularyValueNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, common.ErrVocabularyValueAlreadyExists),
+		errors.Is(err, common.ErrVocabularyValueBuiltin),
+		errors.Is(err, common.ErrVocabularyValueInUse):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return err
+	}
+}
+
+func (rs VocabularyResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "get-vocabulary",
+		Method:      http.MethodGet,
+		Path:        "/admin/vocabularies/{vocabulary}",
+		Description: "Lists the impact categories, record types or record statuses.",
+	}, rs.getValues)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-vocabulary-value",
+		Method:      http.MethodPost,
+		Path:        "/admin/vocabularies/{vocabulary}",
+		Description: "Adds a value, which is accepted and listed in the OpenAPI document right away. Other instances of the API follow as soon as they are notified of the change.",
+	}, rs.create)
+	huma.Register(s, huma.Operation{
+		OperationID: "rename-vocabulary-value",
+		Method:      http.MethodPut,
+		Path:        "/admin/vocabularies/{vocabulary}/{name}",
+		Description: "Renames a value that is not built in. Records keep the value under its new name.",
+	}, rs.rename)
+	huma.Register(s, huma.Operation{
+		OperationID: "delete-vocabulary-value",
+		Method:      http.MethodDelete,
+		Path:        "/admin/vocabularies/{vocabulary}/{name}",
+		Description: "Deletes a value that is not built in and no longer used.",
+	}, rs.delete)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-type-attribute-schema",
+		Method:      http.MethodGet,
+		Path:        "/admin/vocabularies/types/{name}/attribute-schema",
+		Description: "Returns the JSON Schema the attributes of records of this type are checked against. It is empty when the type takes no attributes.",
+	}, rs.getAttributeSchema)
+	huma.Register(s, huma.Operation{
+		OperationID: "set-type-attribute-schema",
+		Method:      http.MethodPut,
+		Path:        "/admin/vocabularies/types/{name}/attribute-schema",
+		Description: "Replaces the JSON Schema of the attributes of records of this type. The schema must describe an object. Records already stored are not checked again until they are updated.",
+	}, rs.setAttributeSchema)
+	huma.Register(s, huma.Operation{
+		OperationID: "delete-type-attribute-schema",
+		Method:      http.MethodDelete,
+		Path:        "/admin/vocabularies/types/{name}/attribute-schema",
+		Description: "Removes the attribute schema of this type, after which its records take no attributes.",
+	}, rs.deleteAttributeSchema)
+}
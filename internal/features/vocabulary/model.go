@@ -0,0 +1,29 @@
+package vocabulary
+
+type valueCommandBody struct {
+	Name string `json:"name" minLength:"1" maxLength:"50" pattern:"^[a-z][a-z0-9-]*$"`
+}
+
+type valueResponseBody struct {
+	Name string `json:"name"`
+	// Builtin values are relied on by the API and cannot be renamed or deleted
+	Builtin bool `json:"builtin"`
+	// Usage is the number of records, or impacts for categories, using the value
+	Usage int `json:"usage"`
+}
+
+// Definition is a value of a vocabulary as stored in its reference table.
+type Definition struct {
+	ID      int16 `sql:"primary_key"`
+	Name    string
+	Builtin bool
+	Usage   int
+}
+
+func mapValueResponseBody(d Definition, index int) valueResponseBody {
+	return valueResponseBody{
+		Name:    d.Name,
+		Builtin: d.Builtin,
+		Usage:   d.Usage,
+	}
+}
@@ -0,0 +1,291 @@
+package vocabulary
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/lib/pq"
+)
+
+type IVocabularyRepository interface {
+	// Listen sends on changes whenever a vocabulary changed, or may have
+	// changed while the connection was lost, until the context ends.
+	Listen(c context.Context, changes chan<- struct{}) error
+	GetValues(c context.Context, vocabulary Vocabulary) ([]Definition, error)
+	Create(c context.Context, vocabulary Vocabulary, name string) error
+	Rename(c context.Context, vocabulary Vocabulary, name string, newName string) error
+	Delete(c context.Context, vocabulary Vocabulary, name string) error
+	GetAttributeSchema(c context.Context, recordType string) (*string, error)
+	SetAttributeSchema(c context.Context, recordType string, schema *string) error
+}
+
+type VocabularyRepository struct {
+	db      *sql.DB
+	connStr string
+	logger  *slog.Logger
+}
+
+// NewRepository takes the connection string to listen for changes on a
+// connection of its own, as notifications are delivered to the connection
+// that asked for them.
+func NewRepository(db *sql.DB, connStr string, logger *slog.Logger) IVocabularyRepository {
+	return VocabularyRepository{
+		db:      db,
+		connStr: connStr,
+		logger:  logger,
+	}
+}
+
+// channel is the channel the notify_vocabulary_change trigger notifies
+// changes on.
+const channel = "vocabulary"
+
+const (
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+	// pingInterval is how long to go without notifications before checking
+	// the connection is still alive
+	pingInterval = 90 * time.Second
+)
+
+// definitionTable is the reference table of a vocabulary together with the
+// column storing its codes.
+type definitionTable struct {
+	table   Table
+	id      ColumnInteger
+	name    ColumnString
+	builtin ColumnBool
+	usedBy  Table
+	usedIn  ColumnInteger
+}
+
+var tables = map[Vocabulary]definitionTable{
+	Categories: {CategoryDefinition, CategoryDefinition.ID, CategoryDefinition.Name, CategoryDefinition.Builtin, Impact, Impact.Category},
+	Types:      {TypeDefinition, TypeDefinition.ID, TypeDefinition.Name, TypeDefinition.Builtin, Record, Record.Type},
+	Statuses:   {StatusDefinition, StatusDefinition.ID, StatusDefinition.Name, StatusDefinition.Builtin, Record, Record.Status},
+}
+
+// vocabularies are the in-memory vocabularies kept in line with the tables.
+var vocabularies = map[Vocabulary]*record.Vocabulary{
+	Categories: record.Categories,
+	Types:      record.Types,
+	Statuses:   record.Statuses,
+}
+
+// revision counts the times the values in memory changed.
+var revision atomic.Int64
+
+// Revision tells which values the vocabularies in memory hold. It changes
+// whenever Load finds values that differ from the ones held until then.
+func Revision() int64 {
+	return revision.Load()
+}
+
+// Load reads the values of every vocabulary into memory. It runs before the
+// API is mounted, so the stored values end up in the OpenAPI document.
+func Load(c context.Context, db *sql.DB) error {
+	changed := false
+	for vocabulary, t := range tables {
+		stmt := SELECT(t.id.AS("definition.id"), t.name.AS("definition.name")).
+			FROM(t.table)
+
+		var dest []Definition
+		if err := stmt.QueryContext(c, db, &dest); err != nil {
+			return fmt.Errorf("error loading %s: %w", vocabulary, err)
+		}
+
+		values := make(map[int16]string, len(dest))
+		for _, d := range dest {
+			values[d.ID] = d.Name
+		}
+		if vocabularies[vocabulary].Replace(values) {
+			changed = true
+		}
+	}
+	if changed {
+		revision.Add(1)
+	}
+	return nil
+}
+
+func (r VocabularyRepository) Listen(c context.Context, changes chan<- struct{}) error {
+	listener := pq.NewListener(r.connStr, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("vocabulary listener: %v", err))
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(channel); err != nil {
+		return fmt.Errorf("error listening for vocabulary changes: %w", err)
+	}
+
+	for {
+		// The listener also sends nil once it has reconnected, after which
+		// changes may have been missed
+		select {
+		case <-c.Done():
+			return nil
+		case <-time.After(pingInterval):
+			go listener.Ping()
+			continue
+		case <-listener.Notify:
+		}
+
+		select {
+		case <-c.Done():
+			return nil
+		case changes <- struct{}{}:
+		}
+	}
+}
+
+func (r VocabularyRepository) GetValues(c context.Context, vocabulary Vocabulary) ([]Definition, error) {
+	t := tables[vocabulary]
+	usage := SELECT(COUNT(STAR)).
+		FROM(t.usedBy).
+		WHERE(t.usedIn.EQ(t.id))
+
+	stmt := SELECT(
+		t.id.AS("definition.id"),
+		t.name.AS("definition.name"),
+		t.builtin.AS("definition.builtin"),
+		IntExp(usage).AS("definition.usage"),
+	).FROM(t.table).
+		ORDER_BY(t.id)
+
+	var dest []Definition
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting %s: %w", vocabulary, err)
+	}
+	return dest, nil
+}
+
+// Create adds a value to a vocabulary. Its code comes from a sequence, so
+// codes of deleted values are never handed out again and history written
+// with them keeps its meaning.
+func (r VocabularyRepository) Create(c context.Context, vocabulary Vocabulary, name string) error {
+	t := tables[vocabulary]
+	stmt := t.table.INSERT(t.name, t.builtin).
+		VALUES(String(name), Bool(false))
+
+	if _, err := stmt.ExecContext(c, r.db); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return common.ErrVocabularyValueAlreadyExists
+		}
+		return fmt.Errorf("error creating value: %w", err)
+	}
+	return nil
+}
+
+func (r VocabularyRepository) Rename(c context.Context, vocabulary Vocabulary, name string, newName string) error {
+	t := tables[vocabulary]
+	stmt := t.table.UPDATE(t.name).
+		SET(String(newName)).
+		WHERE(t.name.EQ(String(name)).AND(t.builtin.IS_FALSE()))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return common.ErrVocabularyValueAlreadyExists
+		}
+		return fmt.Errorf("error renaming value: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("error renaming value: %w", err)
+	} else if rows == 0 {
+		return r.missingOrBuiltin(c, vocabulary, name)
+	}
+	return nil
+}
+
+func (r VocabularyRepository) Delete(c context.Context, vocabulary Vocabulary, name string) error {
+	t := tables[vocabulary]
+	stmt := t.table.DELETE().
+		WHERE(t.name.EQ(String(name)).AND(t.builtin.IS_FALSE()))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return common.ErrVocabularyValueInUse
+		}
+		return fmt.Errorf("error deleting value: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("error deleting value: %w", err)
+	} else if rows == 0 {
+		return r.missingOrBuiltin(c, vocabulary, name)
+	}
+	return nil
+}
+
+// GetAttributeSchema returns the attribute schema of a record type, or nil
+// when the type has none.
+func (r VocabularyRepository) GetAttributeSchema(c context.Context, recordType string) (*string, error) {
+	stmt := SELECT(TypeDefinition.AttributeSchema).
+		FROM(TypeDefinition).
+		WHERE(TypeDefinition.Name.EQ(String(recordType)))
+
+	var dest []model.TypeDefinition
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return nil, fmt.Errorf("error getting attribute schema: %w", err)
+	}
+	if len(dest) == 0 {
+		return nil, common.ErrVocabularyValueNotFound
+	}
+	return dest[0].AttributeSchema, nil
+}
+
+// SetAttributeSchema replaces the attribute schema of a record type, nil
+// removes it.
+func (r VocabularyRepository) SetAttributeSchema(c context.Context, recordType string, schema *string) error {
+	value := NULL
+	if schema != nil {
+		value = CAST(String(*schema)).AS("jsonb")
+	}
+	stmt := TypeDefinition.UPDATE(TypeDefinition.AttributeSchema).
+		SET(value).
+		WHERE(TypeDefinition.Name.EQ(String(recordType)))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return fmt.Errorf("error setting attribute schema: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("error setting attribute schema: %w", err)
+	} else if rows == 0 {
+		return common.ErrVocabularyValueNotFound
+	}
+	return nil
+}
+
+// missingOrBuiltin tells why a value could not be changed.
+func (r VocabularyRepository) missingOrBuiltin(c context.Context, vocabulary Vocabulary, name string) error {
+	t := tables[vocabulary]
+	stmt := SELECT(t.id.AS("definition.id")).
+		FROM(t.table).
+		WHERE(t.name.EQ(String(name)))
+
+	var dest []Definition
+	if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+		return fmt.Errorf("error getting value: %w", err)
+	}
+	if len(dest) == 0 {
+		return common.ErrVocabularyValueNotFound
+	}
+	return common.ErrVocabularyValueBuiltin
+}
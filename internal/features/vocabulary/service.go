@@ -0,0 +1,160 @@
+package vocabulary
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"historylink/internal/features/record"
+	"log/slog"
+
+	"github.com/samber/lo"
+)
+
+type IVocabularyService interface {
+	// Run loads the vocabularies again whenever they change, including
+	// changes made through other instances of the API, until the context
+	// ends.
+	Run(c context.Context) error
+	GetValues(c context.Context, vocabulary Vocabulary) ([]valueResponseBody, error)
+	Create(c context.Context, vocabulary Vocabulary, command valueCommandBody) ([]valueResponseBody, error)
+	Rename(c context.Context, vocabulary Vocabulary, name string, command valueCommandBody) ([]valueResponseBody, error)
+	Delete(c context.Context, vocabulary Vocabulary, name string) error
+	GetAttributeSchema(c context.Context, recordType string) (map[string]any, error)
+	SetAttributeSchema(c context.Context, recordType string, schema map[string]any) (map[string]any, error)
+	DeleteAttributeSchema(c context.Context, recordType string) error
+}
+
+type VocabularyService struct {
+	vocabularyRepository IVocabularyRepository
+	db                   *sql.DB
+	logger               *slog.Logger
+}
+
+func NewVocabularyService(vocabularyRepository IVocabularyRepository, db *sql.DB, logger *slog.Logger) IVocabularyService {
+	return VocabularyService{
+		vocabularyRepository: vocabularyRepository,
+		db:                   db,
+		logger:               logger,
+	}
+}
+
+// Vocabulary names a configurable enumeration.
+type Vocabulary string
+
+const (
+	Categories Vocabulary = "categories"
+	Types      Vocabulary = "types"
+	Statuses   Vocabulary = "statuses"
+)
+
+func (s VocabularyService) Run(c context.Context) error {
+	changes := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- s.vocabularyRepository.Listen(c, changes)
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-changes:
+			if err := Load(c, s.db); err != nil {
+				s.logger.Error(err.Error())
+			}
+		}
+	}
+}
+
+func (s VocabularyService) GetValues(c context.Context, vocabulary Vocabulary) ([]valueResponseBody, error) {
+	values, err := s.vocabularyRepository.GetValues(c, vocabulary)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(values, mapValueResponseBody), nil
+}
+
+func (s VocabularyService) Create(c context.Context, vocabulary Vocabulary, command valueCommandBody) ([]valueResponseBody, error) {
+	if err := s.vocabularyRepository.Create(c, vocabulary, command.Name); err != nil {
+		return nil, err
+	}
+	s.logger.Info("created vocabulary value", "vocabulary", vocabulary, "name", command.Name)
+
+	if err := s.reload(c); err != nil {
+		return nil, err
+	}
+	return s.GetValues(c, vocabulary)
+}
+
+func (s VocabularyService) Rename(c context.Context, vocabulary Vocabulary, name string, command valueCommandBody) ([]valueResponseBody, error) {
+	if err := s.vocabularyRepository.Rename(c, vocabulary, name, command.Name); err != nil {
+		return nil, err
+	}
+	s.logger.Info("renamed vocabulary value", "vocabulary", vocabulary, "name", name, "newName", command.Name)
+
+	if err := s.reload(c); err != nil {
+		return nil, err
+	}
+	return s.GetValues(c, vocabulary)
+}
+
+func (s VocabularyService) Delete(c context.Context, vocabulary Vocabulary, name string) error {
+	if err := s.vocabularyRepository.Delete(c, vocabulary, name); err != nil {
+		return err
+	}
+	s.logger.Info("deleted vocabulary value", "vocabulary", vocabulary, "name", name)
+
+	return s.reload(c)
+}
+
+// GetAttributeSchema returns the attribute schema of a record type, which is
+// empty when records of the type take no attributes.
+func (s VocabularyService) GetAttributeSchema(c context.Context, recordType string) (map[string]any, error) {
+	raw, err := s.vocabularyRepository.GetAttributeSchema(c, recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := map[string]any{}
+	if raw != nil {
+		if err = json.Unmarshal([]byte(*raw), &schema); err != nil {
+			return nil, fmt.Errorf("error decoding attribute schema: %w", err)
+		}
+	}
+	return schema, nil
+}
+
+// SetAttributeSchema replaces the attribute schema of a record type. Records
+// already stored are checked against it the next time they are updated.
+func (s VocabularyService) SetAttributeSchema(c context.Context, recordType string, schema map[string]any) (map[string]any, error) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding attribute schema: %w", err)
+	}
+	if _, err = record.ParseAttributeSchema(raw); err != nil {
+		return nil, err
+	}
+
+	encoded := string(raw)
+	if err = s.vocabularyRepository.SetAttributeSchema(c, recordType, &encoded); err != nil {
+		return nil, err
+	}
+	s.logger.Info("set attribute schema", "type", recordType)
+
+	return s.GetAttributeSchema(c, recordType)
+}
+
+func (s VocabularyService) DeleteAttributeSchema(c context.Context, recordType string) error {
+	if err := s.vocabularyRepository.SetAttributeSchema(c, recordType, nil); err != nil {
+		return err
+	}
+	s.logger.Info("deleted attribute schema", "type", recordType)
+	return nil
+}
+
+// reload loads the values changed through this instance right away, so the
+// response already lists them; other instances load them once notified.
+func (s VocabularyService) reload(c context.Context) error {
+	return Load(c, s.db)
+}
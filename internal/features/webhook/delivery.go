@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+)
+
+// Headers sent with every delivery. The signature is the hex encoded
+// HMAC-SHA256 of the timestamp, a dot and the body, keyed with the secret of
+// the webhook, so receivers can check both who sent a delivery and when.
+const (
+	headerEvent     = "X-Webhook-Event"
+	headerDelivery  = "X-Webhook-Delivery"
+	headerTimestamp = "X-Webhook-Timestamp"
+	headerSignature = "X-Webhook-Signature"
+)
+
+const (
+	// maxAttempts is how often a delivery is attempted before it is dead
+	maxAttempts = 8
+	// The wait before the next attempt starts at firstRetry and doubles with
+	// every failed attempt, up to maxRetry
+	firstRetry = 30 * time.Second
+	maxRetry   = 6 * time.Hour
+	// attemptTimeout bounds how long a receiver may take to answer
+	attemptTimeout = 10 * time.Second
+	maxErrorLength = 1000
+)
+
+// sign computes the signature of a delivery.
+func sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff is how long to wait after the given number of failed attempts.
+func backoff(attempts int16) time.Duration {
+	wait := firstRetry
+	for i := int16(1); i < attempts && wait < maxRetry; i++ {
+		wait *= 2
+	}
+	return min(wait, maxRetry)
+}
+
+// attempt sends a delivery once, returning it with the outcome of the
+// attempt and how long to wait before the next one.
+func (s WebhookService) attempt(c context.Context, due DueDelivery) (model.WebhookDelivery, time.Duration) {
+	delivery := due.WebhookDelivery
+	delivery.Attempts++
+	delivery.ResponseCode = nil
+	delivery.Error = nil
+
+	code, err := s.send(c, due)
+	if code != 0 {
+		delivery.ResponseCode = &code
+	}
+	if err == nil {
+		delivery.Status = Delivered.ToInt16()
+		return delivery, 0
+	}
+
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+	delivery.Error = &message
+
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = Dead.ToInt16()
+		s.logger.Warn("webhook delivery dead", "webhook", due.Webhook.ID, "delivery", delivery.ID, "error", message)
+		return delivery, 0
+	}
+	return delivery, backoff(delivery.Attempts)
+}
+
+// send posts a delivery to its webhook, returning the status code answered
+// and an error unless it was a success.
+func (s WebhookService) send(c context.Context, due DueDelivery) (int16, error) {
+	body, err := json.Marshal(eventPayload{
+		ID:        due.Outbox.ID,
+		Type:      EventType(due.Outbox.EventType),
+		CreatedAt: due.Outbox.CreatedAt,
+		Data:      json.RawMessage(due.Outbox.Payload),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode delivery: %w", err)
+	}
+
+	c, cancel := context.WithTimeout(c, attemptTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(c, http.MethodPost, due.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(headerEvent, due.Outbox.EventType)
+	request.Header.Set(headerDelivery, due.ID.String())
+	request.Header.Set(headerTimestamp, timestamp)
+	request.Header.Set(headerSignature, sign(due.Webhook.Secret, timestamp, body))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return int16(response.StatusCode), fmt.Errorf("receiver answered %s", response.Status)
+	}
+	return int16(response.StatusCode), nil
+}
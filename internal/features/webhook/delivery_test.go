@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+
+	"github.com/google/uuid"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int16
+		expected time.Duration
+	}{
+		{attempts: 0, expected: firstRetry},
+		{attempts: 1, expected: firstRetry},
+		{attempts: 2, expected: 2 * firstRetry},
+		{attempts: 3, expected: 4 * firstRetry},
+		{attempts: 7, expected: 64 * firstRetry},
+		{attempts: 10, expected: 512 * firstRetry},
+		{attempts: 11, expected: maxRetry},
+		{attempts: 1000, expected: maxRetry},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.expected {
+			t.Errorf("backoff(%d) = %s, expected %s", tt.attempts, got, tt.expected)
+		}
+	}
+}
+
+func newTestService(server *httptest.Server) WebhookService {
+	return WebhookService{
+		client: server.Client(),
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+func newDueDelivery(url string, attempts int16) DueDelivery {
+	return DueDelivery{
+		WebhookDelivery: model.WebhookDelivery{
+			ID:       uuid.New(),
+			Status:   Pending.ToInt16(),
+			Attempts: attempts,
+		},
+		Webhook: model.Webhook{
+			ID:     uuid.New(),
+			URL:    url,
+			Secret: "s3cret",
+			Active: true,
+		},
+		Outbox: model.Outbox{
+			ID:        uuid.New(),
+			EventType: string(RecordUpdated),
+			Payload:   `{"id":"e0d5b4a6-1c2f-4b8e-9a57-3f1d2c4b5a69"}`,
+			CreatedAt: time.Date(2025, 6, 21, 9, 45, 12, 0, time.UTC),
+		},
+	}
+}
+
+func TestSendSignsDelivery(t *testing.T) {
+	var request *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	due := newDueDelivery(server.URL, 0)
+	code, err := newTestService(server).send(context.Background(), due)
+	if err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	if code != http.StatusNoContent {
+		t.Errorf("send() code = %d, expected %d", code, http.StatusNoContent)
+	}
+
+	if got := request.Header.Get(headerEvent); got != string(RecordUpdated) {
+		t.Errorf("%s = %q, expected %q", headerEvent, got, RecordUpdated)
+	}
+	if got := request.Header.Get(headerDelivery); got != due.ID.String() {
+		t.Errorf("%s = %q, expected %q", headerDelivery, got, due.ID)
+	}
+	timestamp := request.Header.Get(headerTimestamp)
+	if expected := sign(due.Webhook.Secret, timestamp, body); request.Header.Get(headerSignature) != expected {
+		t.Errorf("%s = %q, expected %q", headerSignature, request.Header.Get(headerSignature), expected)
+	}
+	if sign("other", timestamp, body) == request.Header.Get(headerSignature) {
+		t.Errorf("signature does not depend on the secret")
+	}
+
+	var payload eventPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("body is not an event: %v", err)
+	}
+	if payload.ID != due.Outbox.ID || payload.Type != RecordUpdated || string(payload.Data) != due.Outbox.Payload {
+		t.Errorf("body = %s, expected the outbox event", body)
+	}
+}
+
+func TestAttempt(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		attempts     int16
+		expected     DeliveryStatus
+		expectedWait time.Duration
+	}{
+		{name: "success", status: http.StatusOK, attempts: 0, expected: Delivered},
+		{name: "first failure", status: http.StatusBadGateway, attempts: 0, expected: Pending, expectedWait: backoff(1)},
+		{name: "later failure", status: http.StatusInternalServerError, attempts: 3, expected: Pending, expectedWait: backoff(4)},
+		{name: "client error", status: http.StatusGone, attempts: 0, expected: Pending, expectedWait: backoff(1)},
+		{name: "last attempt", status: http.StatusServiceUnavailable, attempts: maxAttempts - 1, expected: Dead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			delivery, wait := newTestService(server).attempt(context.Background(), newDueDelivery(server.URL, tt.attempts))
+			if got := DeliveryStatusFromInt16(delivery.Status); got != tt.expected {
+				t.Errorf("status = %s, expected %s", got, tt.expected)
+			}
+			if wait != tt.expectedWait {
+				t.Errorf("wait = %s, expected %s", wait, tt.expectedWait)
+			}
+			if delivery.Attempts != tt.attempts+1 {
+				t.Errorf("attempts = %d, expected %d", delivery.Attempts, tt.attempts+1)
+			}
+			if delivery.ResponseCode == nil || int(*delivery.ResponseCode) != tt.status {
+				t.Errorf("response code = %v, expected %d", delivery.ResponseCode, tt.status)
+			}
+			if failed := tt.expected != Delivered; failed != (delivery.Error != nil) {
+				t.Errorf("error = %v, expected one only on failure", delivery.Error)
+			}
+		})
+	}
+}
+
+func TestAttemptUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	delivery, wait := newTestService(server).attempt(context.Background(), newDueDelivery(url, 0))
+	if got := DeliveryStatusFromInt16(delivery.Status); got != Pending {
+		t.Errorf("status = %s, expected %s", got, Pending)
+	}
+	if wait != backoff(1) {
+		t.Errorf("wait = %s, expected %s", wait, backoff(1))
+	}
+	if delivery.ResponseCode != nil || delivery.Error == nil {
+		t.Errorf("response code = %v, error = %v, expected only an error", delivery.ResponseCode, delivery.Error)
+	}
+}
@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"historylink/internal/common"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/google/uuid"
+)
+
+func NewWebhookResources(conn *sql.DB, logger *slog.Logger) WebhookResources {
+	return WebhookResources{
+		logger:         logger,
+		WebhookService: NewWebhookService(NewRepository(conn, logger), &http.Client{}, logger),
+	}
+}
+
+type WebhookResources struct {
+	WebhookService IWebhookService
+	logger         *slog.Logger
+}
+
+func (rs WebhookResources) getWebhooks(c context.Context, input *struct{}) (*struct {
+	Body []webhookResponseBody
+}, error) {
+	webhooks, err := rs.WebhookService.GetWebhooks(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &struct {
+		Body []webhookResponseBody
+	}{
+		Body: webhooks,
+	}, nil
+}
+
+func (rs WebhookResources) getWebhook(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct {
+	Body webhookResponseBody
+}, error) {
+	webhook, err := rs.WebhookService.GetWebhook(c, input.ID)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	return &struct {
+		Body webhookResponseBody
+	}{
+		Body: webhook,
+	}, nil
+}
+
+func (rs WebhookResources) create(c context.Context, input *struct {
+	Body createWebhookCommandBody
+}) (*struct {
+	Body webhookResponseBody
+}, error) {
+	webhook, err := rs.WebhookService.CreateWebhook(c, input.Body)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	return &struct {
+		Body webhookResponseBody
+	}{
+		Body: webhook,
+	}, nil
+}
+
+func (rs WebhookResources) update(c context.Context, input *struct {
+	ID   uuid.UUID `path:"id"`
+	Body updateWebhookCommandBody
+}) (*struct {
+	Body webhookResponseBody
+}, error) {
+	webhook, err := rs.WebhookService.UpdateWebhook(c, input.ID, input.Body)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	return &struct {
+		Body webhookResponseBody
+	}{
+		Body: webhook,
+	}, nil
+}
+
+func (rs WebhookResources) delete(c context.Context, input *struct {
+	ID uuid.UUID `path:"id"`
+}) (*struct{}, error) {
+	if err := rs.WebhookService.DeleteWebhook(c, input.ID); err != nil {
+		return nil, webhookError(err)
+	}
+
+	return &struct{}{}, nil
+}
+
+func (rs WebhookResources) getDeliveries(c context.Context, input *struct {
+	ID       uuid.UUID      `path:"id"`
+	Status   DeliveryStatus `query:"status" enum:"pending,delivered,dead" doc:"Only list deliveries in this status"`
+	Page     int            `query:"page" minimum:"1" default:"1"`
+	PageSize int            `query:"pageSize" minimum:"1" maximum:"100" default:"20"`
+}) (*struct {
+	Body pagedDeliveriesResponse
+}, error) {
+	deliveries, err := rs.WebhookService.GetDeliveries(c, DeliveryFilter{WebhookID: input.ID, Status: input.Status}, input.Page, input.PageSize)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	return &struct {
+		Body pagedDeliveriesResponse
+	}{
+		Body: deliveries,
+	}, nil
+}
+
+func (rs WebhookResources) getDeadLetters(c context.Context, input *struct {
+	Page     int `query:"page" minimum:"1" default:"1"`
+	PageSize int `query:"pageSize" minimum:"1" maximum:"100" default:"20"`
+}) (*struct {
+	Body pagedDeliveriesResponse
+}, error) {
+	deliveries, err := rs.WebhookService.GetDeliveries(c, DeliveryFilter{Status: Dead}, input.Page, input.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &struct {
+		Body pagedDeliveriesResponse
+	}{
+		Body: deliveries,
+	}, nil
+}
+
+func (rs WebhookResources) retryDelivery(c context.Context, input *struct {
+	ID         uuid.UUID `path:"id"`
+	DeliveryID uuid.UUID `path:"deliveryId"`
+}) (*struct {
+	Body deliveryResponseBody
+}, error) {
+	delivery, err := rs.WebhookService.RetryDelivery(c, input.ID, input.DeliveryID)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	return &struct {
+		Body deliveryResponseBody
+	}{
+		Body: delivery,
+	}, nil
+}
+
+func webhookError(err error) error {
+	switch {
+	case errors.Is(err, common.ErrWebhookNotFound),
+		errors.Is(err, common.ErrDeliveryNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, common.ErrInvalidWebhook):
+		return huma.Error422UnprocessableEntity(err.Error())
+	case errors.Is(err, common.ErrDeliveryNotDead):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return err
+	}
+}
+
+func (rs WebhookResources) MountRoutes(s huma.API) {
+	huma.Register(s, huma.Operation{
+		OperationID: "get-webhooks",
+		Method:      http.MethodGet,
+		Path:        "/admin/webhooks",
+	}, rs.getWebhooks)
+	huma.Register(s, huma.Operation{
+		OperationID: "create-webhook",
+		Method:      http.MethodPost,
+		Path:        "/admin/webhooks",
+		Description: fmt.Sprintf("Subscribes a URL to changes of records, impacts and links. Each change is posted to it as JSON, "+
+			"with the %s header holding sha256= and the hex encoded HMAC-SHA256 of the %s header, a dot and the body, keyed with the secret. "+
+			"Failed deliveries are attempted again with a doubling wait, and end up dead after %d attempts.", headerSignature, headerTimestamp, maxAttempts),
+	}, rs.create)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-webhook",
+		Method:      http.MethodGet,
+		Path:        "/admin/webhooks/{id}",
+	}, rs.getWebhook)
+	huma.Register(s, huma.Operation{
+		OperationID: "update-webhook",
+		Method:      http.MethodPut,
+		Path:        "/admin/webhooks/{id}",
+		Description: "Changes a webhook. Deactivating it holds back its deliveries until it is activated again.",
+	}, rs.update)
+	huma.Register(s, huma.Operation{
+		OperationID: "delete-webhook",
+		Method:      http.MethodDelete,
+		Path:        "/admin/webhooks/{id}",
+		Description: "Deletes a webhook along with its deliveries.",
+	}, rs.delete)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-webhook-deliveries",
+		Method:      http.MethodGet,
+		Path:        "/admin/webhooks/{id}/deliveries",
+		Description: "Lists the deliveries of a webhook newest first, with how their last attempt went.",
+	}, rs.getDeliveries)
+	huma.Register(s, huma.Operation{
+		OperationID: "get-webhook-dead-letters",
+		Method:      http.MethodGet,
+		Path:        "/admin/webhooks/dead-letters",
+		Description: "Lists the deliveries of every webhook that failed all their attempts, newest first.",
+	}, rs.getDeadLetters)
+	huma.Register(s, huma.Operation{
+		OperationID: "retry-webhook-delivery",
+		Method:      http.MethodPost,
+		Path:        "/admin/webhooks/{id}/deliveries/{deliveryId}/retry",
+		Description: "Attempts a dead delivery again, with a fresh set of attempts.",
+	}, rs.retryDelivery)
+}
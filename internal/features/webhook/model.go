@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// EventType is a kind of change webhooks subscribe to, written as the thing
+// changed and what happened to it.
+type EventType string
+
+const (
+	RecordCreated EventType = "record.created"
+	RecordUpdated EventType = "record.updated"
+	RecordDeleted EventType = "record.deleted"
+	ImpactCreated EventType = "impact.created"
+	ImpactUpdated EventType = "impact.updated"
+	ImpactDeleted EventType = "impact.deleted"
+	LinkCreated   EventType = "link.created"
+	LinkUpdated   EventType = "link.updated"
+	LinkDeleted   EventType = "link.deleted"
+)
+
+type DeliveryStatus string
+
+const (
+	// Pending deliveries are waiting for their first or next attempt
+	Pending   DeliveryStatus = "pending"
+	Delivered DeliveryStatus = "delivered"
+	// Dead deliveries failed every attempt and are only retried on request
+	Dead DeliveryStatus = "dead"
+)
+
+func DeliveryStatusFromInt16(v int16) DeliveryStatus {
+	switch v {
+	case 0:
+		return Pending
+	case 1:
+		return Delivered
+	case 2:
+		return Dead
+	}
+	return ""
+}
+
+func (s DeliveryStatus) ToInt16() int16 {
+	switch s {
+	case Pending:
+		return 0
+	case Delivered:
+		return 1
+	case Dead:
+		return 2
+	}
+	return -1
+}
+
+// DeliveryEntity is a delivery together with the change it delivers.
+type DeliveryEntity struct {
+	model.WebhookDelivery
+	Outbox model.Outbox
+}
+
+// DueDelivery is a delivery to attempt, with what sending it needs.
+type DueDelivery struct {
+	model.WebhookDelivery
+	Webhook model.Webhook
+	Outbox  model.Outbox
+}
+
+// DeliveryFilter narrows down the deliveries listed.
+type DeliveryFilter struct {
+	// WebhookID only lists the deliveries of this webhook, those of every
+	// webhook when nil
+	WebhookID uuid.UUID
+	// Status only lists deliveries in this status, all of them when empty
+	Status DeliveryStatus
+}
+
+type webhookResponseBody struct {
+	ID         uuid.UUID   `json:"id"`
+	Url        string      `json:"url"`
+	EventTypes []EventType `json:"eventTypes"`
+	// Active webhooks are sent changes, inactive ones keep their pending
+	// deliveries until they are activated again
+	Active    bool   `json:"active"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type createWebhookCommandBody struct {
+	Url        string      `json:"url" format:"uri" minLength:"1" maxLength:"2048"`
+	EventTypes []EventType `json:"eventTypes" minItems:"1" uniqueItems:"true" enum:"record.created,record.updated,record.deleted,impact.created,impact.updated,impact.deleted,link.created,link.updated,link.deleted"`
+	Secret     string      `json:"secret" minLength:"16" maxLength:"255" doc:"Key deliveries are signed with, never returned"`
+}
+
+type updateWebhookCommandBody struct {
+	Url        string      `json:"url" format:"uri" minLength:"1" maxLength:"2048"`
+	EventTypes []EventType `json:"eventTypes" minItems:"1" uniqueItems:"true" enum:"record.created,record.updated,record.deleted,impact.created,impact.updated,impact.deleted,link.created,link.updated,link.deleted"`
+	Secret     string      `json:"secret,omitempty" minLength:"16" maxLength:"255" doc:"Key deliveries are signed with, kept when left out"`
+	Active     bool        `json:"active"`
+}
+
+type deliveryResponseBody struct {
+	ID        uuid.UUID      `json:"id"`
+	WebhookID uuid.UUID      `json:"webhookId"`
+	EventID   uuid.UUID      `json:"eventId"`
+	EventType EventType      `json:"eventType"`
+	Status    DeliveryStatus `json:"status"`
+	Attempts  int16          `json:"attempts"`
+	// NextAttemptAt is only given for pending deliveries
+	NextAttemptAt *string `json:"nextAttemptAt"`
+	// ResponseCode and Error tell how the last attempt went
+	ResponseCode *int16  `json:"responseCode"`
+	Error        *string `json:"error"`
+	CreatedAt    string  `json:"createdAt"`
+	UpdatedAt    string  `json:"updatedAt"`
+}
+
+type pagedDeliveriesResponse struct {
+	Page       int                    `json:"page"`
+	Size       int                    `json:"size"`
+	Total      int                    `json:"total"`
+	Deliveries []deliveryResponseBody `json:"deliveries"`
+}
+
+// eventPayload is the body of a delivery. ID is the same for every attempt
+// and every webhook, so receivers can tell repeated deliveries apart.
+type eventPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      EventType       `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// webhookEventTypes decodes the event types of a webhook, which are stored
+// as a JSON array.
+func webhookEventTypes(webhook model.Webhook) []EventType {
+	eventTypes := []EventType{}
+	if webhook.EventTypes != "" {
+		_ = json.Unmarshal([]byte(webhook.EventTypes), &eventTypes)
+	}
+	return eventTypes
+}
+
+func mapWebhookResponseBody(webhook model.Webhook, index int) webhookResponseBody {
+	return webhookResponseBody{
+		ID:         webhook.ID,
+		Url:        webhook.URL,
+		EventTypes: webhookEventTypes(webhook),
+		Active:     webhook.Active,
+		CreatedAt:  common.ToDateTimeString(&webhook.CreatedAt),
+	}
+}
+
+func mapDeliveryResponseBody(delivery DeliveryEntity, index int) deliveryResponseBody {
+	status := DeliveryStatusFromInt16(delivery.Status)
+	var nextAttemptAt *string
+	if status == Pending {
+		nextAttemptAt = lo.ToPtr(common.ToDateTimeString(&delivery.NextAttemptAt))
+	}
+	return deliveryResponseBody{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.OutboxID,
+		EventType:     EventType(delivery.Outbox.EventType),
+		Status:        status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: nextAttemptAt,
+		ResponseCode:  delivery.ResponseCode,
+		Error:         delivery.Error,
+		CreatedAt:     common.ToDateTimeString(&delivery.CreatedAt),
+		UpdatedAt:     common.ToDateTimeString(&delivery.UpdatedAt),
+	}
+}
@@ -0,0 +1,349 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/common"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+type IWebhookRepository interface {
+	GetWebhooks(c context.Context) ([]model.Webhook, error)
+	GetWebhook(c context.Context, id uuid.UUID) (model.Webhook, error)
+	CreateWebhook(c context.Context, webhook model.Webhook) (model.Webhook, error)
+	UpdateWebhook(c context.Context, webhook model.Webhook) (model.Webhook, error)
+	DeleteWebhook(c context.Context, id uuid.UUID) error
+	GetDeliveries(c context.Context, filter DeliveryFilter, limit int, offset int) ([]DeliveryEntity, int, error)
+	GetDelivery(c context.Context, webhookId uuid.UUID, id uuid.UUID) (DeliveryEntity, error)
+	RetryDelivery(c context.Context, id uuid.UUID) error
+	Dispatch(c context.Context, limit int) (int, error)
+	ClaimDue(c context.Context, limit int, lease time.Duration) ([]DueDelivery, error)
+	RecordAttempt(c context.Context, delivery model.WebhookDelivery, retryIn time.Duration) error
+	Prune(c context.Context, retention time.Duration) (int64, error)
+}
+
+type WebhookRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) IWebhookRepository {
+	return WebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r WebhookRepository) GetWebhooks(c context.Context) ([]model.Webhook, error) {
+	stmt := SELECT(Webhook.AllColumns).
+		FROM(Webhook).
+		ORDER_BY(Webhook.CreatedAt, Webhook.ID)
+
+	var webhooks []model.Webhook
+	if err := stmt.QueryContext(c, r.db, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r WebhookRepository) GetWebhook(c context.Context, id uuid.UUID) (model.Webhook, error) {
+	stmt := SELECT(Webhook.AllColumns).
+		FROM(Webhook).
+		WHERE(Webhook.ID.EQ(UUID(id)))
+
+	var webhooks []model.Webhook
+	if err := stmt.QueryContext(c, r.db, &webhooks); err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return model.Webhook{}, common.ErrWebhookNotFound
+	}
+	return webhooks[0], nil
+}
+
+func (r WebhookRepository) CreateWebhook(c context.Context, webhook model.Webhook) (model.Webhook, error) {
+	stmt := Webhook.INSERT(Webhook.URL, Webhook.EventTypes, Webhook.Secret).
+		MODEL(webhook).
+		RETURNING(Webhook.AllColumns)
+
+	var created model.Webhook
+	if err := stmt.QueryContext(c, r.db, &created); err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return created, nil
+}
+
+func (r WebhookRepository) UpdateWebhook(c context.Context, webhook model.Webhook) (model.Webhook, error) {
+	stmt := Webhook.UPDATE(Webhook.URL, Webhook.EventTypes, Webhook.Secret, Webhook.Active).
+		MODEL(webhook).
+		WHERE(Webhook.ID.EQ(UUID(webhook.ID))).
+		RETURNING(Webhook.AllColumns)
+
+	var updated []model.Webhook
+	if err := stmt.QueryContext(c, r.db, &updated); err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if len(updated) == 0 {
+		return model.Webhook{}, common.ErrWebhookNotFound
+	}
+	return updated[0], nil
+}
+
+func (r WebhookRepository) DeleteWebhook(c context.Context, id uuid.UUID) error {
+	stmt := Webhook.DELETE().
+		WHERE(Webhook.ID.EQ(UUID(id)))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	} else if rows == 0 {
+		return common.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// GetDeliveries lists deliveries newest first.
+func (r WebhookRepository) GetDeliveries(c context.Context, filter DeliveryFilter, limit int, offset int) ([]DeliveryEntity, int, error) {
+	condition := Bool(true)
+	if filter.WebhookID != uuid.Nil {
+		condition = condition.AND(WebhookDelivery.WebhookID.EQ(UUID(filter.WebhookID)))
+	}
+	if filter.Status != "" {
+		condition = condition.AND(WebhookDelivery.Status.EQ(Int16(filter.Status.ToInt16())))
+	}
+
+	var total struct {
+		C int
+	}
+	countStmt := SELECT(COUNT(STAR).AS("c")).
+		FROM(WebhookDelivery).
+		WHERE(condition)
+	if err := countStmt.QueryContext(c, r.db, &total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deliveries: %w", err)
+	}
+
+	stmt := SELECT(WebhookDelivery.AllColumns, Outbox.AllColumns).
+		FROM(WebhookDelivery.INNER_JOIN(Outbox, Outbox.ID.EQ(WebhookDelivery.OutboxID))).
+		WHERE(condition).
+		ORDER_BY(WebhookDelivery.CreatedAt.DESC(), Outbox.CreatedAt.DESC(), WebhookDelivery.ID).
+		LIMIT(int64(limit)).
+		OFFSET(int64(offset))
+
+	var deliveries []DeliveryEntity
+	if err := stmt.QueryContext(c, r.db, &deliveries); err != nil {
+		return nil, 0, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+	return deliveries, total.C, nil
+}
+
+func (r WebhookRepository) GetDelivery(c context.Context, webhookId uuid.UUID, id uuid.UUID) (DeliveryEntity, error) {
+	stmt := SELECT(WebhookDelivery.AllColumns, Outbox.AllColumns).
+		FROM(WebhookDelivery.INNER_JOIN(Outbox, Outbox.ID.EQ(WebhookDelivery.OutboxID))).
+		WHERE(WebhookDelivery.ID.EQ(UUID(id)).AND(WebhookDelivery.WebhookID.EQ(UUID(webhookId))))
+
+	var deliveries []DeliveryEntity
+	if err := stmt.QueryContext(c, r.db, &deliveries); err != nil {
+		return DeliveryEntity{}, fmt.Errorf("failed to get delivery: %w", err)
+	}
+	if len(deliveries) == 0 {
+		return DeliveryEntity{}, common.ErrDeliveryNotFound
+	}
+	return deliveries[0], nil
+}
+
+// RetryDelivery makes a dead delivery pending again, to be attempted right
+// away with a fresh set of attempts.
+func (r WebhookRepository) RetryDelivery(c context.Context, id uuid.UUID) error {
+	stmt := WebhookDelivery.UPDATE().
+		SET(
+			WebhookDelivery.Status.SET(Int16(Pending.ToInt16())),
+			WebhookDelivery.Attempts.SET(Int16(0)),
+			WebhookDelivery.NextAttemptAt.SET(LOCALTIMESTAMP()),
+			WebhookDelivery.UpdatedAt.SET(LOCALTIMESTAMP()),
+		).
+		WHERE(WebhookDelivery.ID.EQ(UUID(id)).AND(WebhookDelivery.Status.EQ(Int16(Dead.ToInt16()))))
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to retry delivery: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to retry delivery: %w", err)
+	} else if rows == 0 {
+		return common.ErrDeliveryNotDead
+	}
+	return nil
+}
+
+// Dispatch hands up to limit changes from the outbox to the active webhooks
+// subscribed to them, as pending deliveries. It returns the number of
+// changes handed out.
+func (r WebhookRepository) Dispatch(c context.Context, limit int) (int, error) {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Skipping locked changes lets several instances dispatch side by side
+	pendingStmt := SELECT(Outbox.ID).
+		FROM(Outbox).
+		WHERE(Outbox.DispatchedAt.IS_NULL()).
+		ORDER_BY(Outbox.CreatedAt).
+		LIMIT(int64(limit)).
+		FOR(UPDATE().SKIP_LOCKED())
+
+	var pending []model.Outbox
+	if err := pendingStmt.QueryContext(c, tx, &pending); err != nil {
+		return 0, fmt.Errorf("failed to get pending changes: %w", err)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	ids := lo.Map(pending, func(change model.Outbox, index int) Expression {
+		return UUID(change.ID)
+	})
+
+	deliveryStmt := WebhookDelivery.INSERT(WebhookDelivery.WebhookID, WebhookDelivery.OutboxID).
+		QUERY(
+			SELECT(Webhook.ID, Outbox.ID).
+				FROM(Outbox.INNER_JOIN(
+					Webhook,
+					Webhook.Active.IS_TRUE().AND(RawBool("webhook.event_types @> jsonb_build_array(outbox.event_type)")),
+				)).
+				WHERE(Outbox.ID.IN(ids...)),
+		).
+		ON_CONFLICT(WebhookDelivery.WebhookID, WebhookDelivery.OutboxID).
+		DO_NOTHING()
+	if _, err := deliveryStmt.ExecContext(c, tx); err != nil {
+		return 0, fmt.Errorf("failed to create deliveries: %w", err)
+	}
+
+	dispatchedStmt := Outbox.UPDATE().
+		SET(Outbox.DispatchedAt.SET(LOCALTIMESTAMP())).
+		WHERE(Outbox.ID.IN(ids...))
+	if _, err := dispatchedStmt.ExecContext(c, tx); err != nil {
+		return 0, fmt.Errorf("failed to mark changes dispatched: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(pending), nil
+}
+
+// ClaimDue returns up to limit pending deliveries of active webhooks that
+// are due, pushing their next attempt back by lease so no other instance
+// attempts them meanwhile.
+func (r WebhookRepository) ClaimDue(c context.Context, limit int, lease time.Duration) ([]DueDelivery, error) {
+	claimStmt := WebhookDelivery.UPDATE().
+		SET(WebhookDelivery.NextAttemptAt.SET(LOCALTIMESTAMP().ADD(INTERVALd(lease)))).
+		WHERE(WebhookDelivery.ID.IN(
+			SELECT(WebhookDelivery.ID).
+				FROM(WebhookDelivery.INNER_JOIN(Webhook, Webhook.ID.EQ(WebhookDelivery.WebhookID))).
+				WHERE(
+					WebhookDelivery.Status.EQ(Int16(Pending.ToInt16())).
+						AND(WebhookDelivery.NextAttemptAt.LT_EQ(LOCALTIMESTAMP())).
+						AND(Webhook.Active.IS_TRUE()),
+				).
+				ORDER_BY(WebhookDelivery.NextAttemptAt).
+				LIMIT(int64(limit)).
+				FOR(UPDATE().OF(WebhookDelivery).SKIP_LOCKED()),
+		)).
+		RETURNING(WebhookDelivery.ID)
+
+	var claimed []model.WebhookDelivery
+	if err := claimStmt.QueryContext(c, r.db, &claimed); err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	stmt := SELECT(WebhookDelivery.AllColumns, Webhook.AllColumns, Outbox.AllColumns).
+		FROM(WebhookDelivery.
+			INNER_JOIN(Webhook, Webhook.ID.EQ(WebhookDelivery.WebhookID)).
+			INNER_JOIN(Outbox, Outbox.ID.EQ(WebhookDelivery.OutboxID))).
+		WHERE(WebhookDelivery.ID.IN(lo.Map(claimed, func(delivery model.WebhookDelivery, index int) Expression {
+			return UUID(delivery.ID)
+		})...))
+
+	var due []DueDelivery
+	if err := stmt.QueryContext(c, r.db, &due); err != nil {
+		return nil, fmt.Errorf("failed to get claimed deliveries: %w", err)
+	}
+	return due, nil
+}
+
+// RecordAttempt stores the outcome of an attempt, with the next one due
+// after retryIn when the delivery is still pending.
+func (r WebhookRepository) RecordAttempt(c context.Context, delivery model.WebhookDelivery, retryIn time.Duration) error {
+	stmt := WebhookDelivery.UPDATE().
+		SET(
+			WebhookDelivery.Status.SET(Int16(delivery.Status)),
+			WebhookDelivery.Attempts.SET(Int16(delivery.Attempts)),
+			WebhookDelivery.ResponseCode.SET(nullInt16(delivery.ResponseCode)),
+			WebhookDelivery.Error.SET(nullString(delivery.Error)),
+			WebhookDelivery.NextAttemptAt.SET(LOCALTIMESTAMP().ADD(INTERVALd(retryIn))),
+			WebhookDelivery.UpdatedAt.SET(LOCALTIMESTAMP()),
+		).
+		WHERE(WebhookDelivery.ID.EQ(UUID(delivery.ID)))
+
+	if _, err := stmt.ExecContext(c, r.db); err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes the changes dispatched longer than retention ago, along with
+// their deliveries, unless one of those is still pending or dead.
+func (r WebhookRepository) Prune(c context.Context, retention time.Duration) (int64, error) {
+	stmt := Outbox.DELETE().
+		WHERE(
+			Outbox.DispatchedAt.LT(LOCALTIMESTAMP().SUB(INTERVALd(retention))).
+				AND(NOT(EXISTS(
+					SELECT(WebhookDelivery.ID).
+						FROM(WebhookDelivery).
+						WHERE(
+							WebhookDelivery.OutboxID.EQ(Outbox.ID).
+								AND(WebhookDelivery.Status.IN(Int16(Pending.ToInt16()), Int16(Dead.ToInt16()))),
+						),
+				))),
+		)
+
+	result, err := stmt.ExecContext(c, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune outbox: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune outbox: %w", err)
+	}
+	return rows, nil
+}
+
+func nullInt16(v *int16) IntegerExpression {
+	if v == nil {
+		return CAST(NULL).AS_SMALLINT()
+	}
+	return Int16(*v)
+}
+
+func nullString(v *string) StringExpression {
+	if v == nil {
+		return CAST(NULL).AS_TEXT()
+	}
+	return String(*v)
+}
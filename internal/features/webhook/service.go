@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/common"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const (
+	// pollInterval is how often the outbox and the due deliveries are
+	// looked at
+	pollInterval = 5 * time.Second
+	// pruneInterval is how often changes older than retention are deleted
+	pruneInterval = time.Hour
+	retention     = 30 * 24 * time.Hour
+	dispatchBatch = 500
+	deliveryBatch = 50
+	// lease must outlast attempting a whole batch of deliveries
+	lease = 2 * time.Minute
+)
+
+type IWebhookService interface {
+	GetWebhooks(c context.Context) ([]webhookResponseBody, error)
+	GetWebhook(c context.Context, id uuid.UUID) (webhookResponseBody, error)
+	CreateWebhook(c context.Context, command createWebhookCommandBody) (webhookResponseBody, error)
+	UpdateWebhook(c context.Context, id uuid.UUID, command updateWebhookCommandBody) (webhookResponseBody, error)
+	DeleteWebhook(c context.Context, id uuid.UUID) error
+	GetDeliveries(c context.Context, filter DeliveryFilter, page int, size int) (pagedDeliveriesResponse, error)
+	RetryDelivery(c context.Context, webhookId uuid.UUID, id uuid.UUID) (deliveryResponseBody, error)
+	// Run hands changes to the webhooks and delivers them until the context
+	// ends.
+	Run(c context.Context) error
+}
+
+type WebhookService struct {
+	webhookRepository IWebhookRepository
+	client            *http.Client
+	logger            *slog.Logger
+}
+
+func NewWebhookService(webhookRepository IWebhookRepository, client *http.Client, logger *slog.Logger) IWebhookService {
+	return WebhookService{
+		webhookRepository: webhookRepository,
+		client:            client,
+		logger:            logger,
+	}
+}
+
+func (s WebhookService) GetWebhooks(c context.Context) ([]webhookResponseBody, error) {
+	webhooks, err := s.webhookRepository.GetWebhooks(c)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(webhooks, mapWebhookResponseBody), nil
+}
+
+func (s WebhookService) GetWebhook(c context.Context, id uuid.UUID) (webhookResponseBody, error) {
+	webhook, err := s.webhookRepository.GetWebhook(c, id)
+	if err != nil {
+		return webhookResponseBody{}, err
+	}
+	return mapWebhookResponseBody(webhook, 0), nil
+}
+
+func (s WebhookService) CreateWebhook(c context.Context, command createWebhookCommandBody) (webhookResponseBody, error) {
+	webhook, err := newWebhook(command.Url, command.EventTypes)
+	if err != nil {
+		return webhookResponseBody{}, err
+	}
+	webhook.Secret = command.Secret
+
+	created, err := s.webhookRepository.CreateWebhook(c, webhook)
+	if err != nil {
+		return webhookResponseBody{}, err
+	}
+	s.logger.Info("created webhook", "webhook", created.ID, "url", created.URL)
+	return mapWebhookResponseBody(created, 0), nil
+}
+
+func (s WebhookService) UpdateWebhook(c context.Context, id uuid.UUID, command updateWebhookCommandBody) (webhookResponseBody, error) {
+	existing, err := s.webhookRepository.GetWebhook(c, id)
+	if err != nil {
+		return webhookResponseBody{}, err
+	}
+
+	webhook, err := newWebhook(command.Url, command.EventTypes)
+	if err != nil {
+		return webhookResponseBody{}, err
+	}
+	webhook.ID = id
+	webhook.Active = command.Active
+	webhook.Secret = existing.Secret
+	if command.Secret != "" {
+		webhook.Secret = command.Secret
+	}
+
+	updated, err := s.webhookRepository.UpdateWebhook(c, webhook)
+	if err != nil {
+		return webhookResponseBody{}, err
+	}
+	return mapWebhookResponseBody(updated, 0), nil
+}
+
+// newWebhook checks the url and encodes the event types of a webhook.
+func newWebhook(rawURL string, eventTypes []EventType) (model.Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return model.Webhook{}, common.ErrInvalidWebhook
+	}
+
+	encoded, err := json.Marshal(lo.Uniq(eventTypes))
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to encode event types: %w", err)
+	}
+	return model.Webhook{
+		URL:        rawURL,
+		EventTypes: string(encoded),
+	}, nil
+}
+
+func (s WebhookService) DeleteWebhook(c context.Context, id uuid.UUID) error {
+	return s.webhookRepository.DeleteWebhook(c, id)
+}
+
+func (s WebhookService) GetDeliveries(c context.Context, filter DeliveryFilter, page int, size int) (pagedDeliveriesResponse, error) {
+	if filter.WebhookID != uuid.Nil {
+		if _, err := s.webhookRepository.GetWebhook(c, filter.WebhookID); err != nil {
+			return pagedDeliveriesResponse{}, err
+		}
+	}
+
+	deliveries, total, err := s.webhookRepository.GetDeliveries(c, filter, size, (page-1)*size)
+	if err != nil {
+		return pagedDeliveriesResponse{}, err
+	}
+	return pagedDeliveriesResponse{
+		Page:       page,
+		Size:       size,
+		Total:      total,
+		Deliveries: lo.Map(deliveries, mapDeliveryResponseBody),
+	}, nil
+}
+
+func (s WebhookService) RetryDelivery(c context.Context, webhookId uuid.UUID, id uuid.UUID) (deliveryResponseBody, error) {
+	if _, err := s.webhookRepository.GetDelivery(c, webhookId, id); err != nil {
+		return deliveryResponseBody{}, err
+	}
+	if err := s.webhookRepository.RetryDelivery(c, id); err != nil {
+		return deliveryResponseBody{}, err
+	}
+
+	delivery, err := s.webhookRepository.GetDelivery(c, webhookId, id)
+	if err != nil {
+		return deliveryResponseBody{}, err
+	}
+	return mapDeliveryResponseBody(delivery, 0), nil
+}
+
+func (s WebhookService) Run(c context.Context) error {
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			return nil
+		case <-prune.C:
+			if pruned, err := s.webhookRepository.Prune(c, retention); err != nil {
+				s.logger.Error(err.Error())
+			} else if pruned > 0 {
+				s.logger.Info("pruned outbox", "changes", pruned)
+			}
+		case <-poll.C:
+			if err := s.dispatch(c); err != nil {
+				s.logger.Error(err.Error())
+			}
+			if err := s.deliverDue(c); err != nil {
+				s.logger.Error(err.Error())
+			}
+		}
+	}
+}
+
+// dispatch hands every change waiting in the outbox to the webhooks.
+func (s WebhookService) dispatch(c context.Context) error {
+	for {
+		dispatched, err := s.webhookRepository.Dispatch(c, dispatchBatch)
+		if err != nil || dispatched < dispatchBatch {
+			return err
+		}
+	}
+}
+
+// deliverDue attempts the deliveries that are due, side by side.
+func (s WebhookService) deliverDue(c context.Context) error {
+	due, err := s.webhookRepository.ClaimDue(c, deliveryBatch, lease)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempted, retryIn := s.attempt(c, delivery)
+			if err := s.webhookRepository.RecordAttempt(c, attempted, retryIn); err != nil {
+				s.logger.Error(err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
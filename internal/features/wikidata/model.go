@@ -0,0 +1,249 @@
+package wikidata
+
+import (
+	"encoding/json"
+	"fmt"
+	"historylink/internal/features/record"
+	"strings"
+	"time"
+)
+
+// entity is the subset of a Wikidata dump entity the importer reads.
+type entity struct {
+	ID           string                 `json:"id"`
+	Type         string                 `json:"type"`
+	Labels       map[string]monolingual `json:"labels"`
+	Descriptions map[string]monolingual `json:"descriptions"`
+	Claims       map[string][]statement `json:"claims"`
+	Sitelinks    map[string]sitelink    `json:"sitelinks"`
+}
+
+type monolingual struct {
+	Language string `json:"language"`
+	Value    string `json:"value"`
+}
+
+type sitelink struct {
+	Site  string `json:"site"`
+	Title string `json:"title"`
+}
+
+type statement struct {
+	Mainsnak snak   `json:"mainsnak"`
+	Rank     string `json:"rank"`
+}
+
+type snak struct {
+	Snaktype  string    `json:"snaktype"`
+	Property  string    `json:"property"`
+	Datavalue datavalue `json:"datavalue"`
+}
+
+type datavalue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type entityIdValue struct {
+	ID string `json:"id"`
+}
+
+type timeValue struct {
+	Time      string `json:"time"`
+	Precision int    `json:"precision"`
+}
+
+type coordinateValue struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// values returns the statements of a property that have a value, leaving out
+// deprecated ones and putting preferred ones first.
+func (e entity) values(property string) []datavalue {
+	var preferred, normal []datavalue
+	for _, s := range e.Claims[property] {
+		if s.Mainsnak.Snaktype != "value" {
+			continue
+		}
+		switch s.Rank {
+		case "deprecated":
+			continue
+		case "preferred":
+			preferred = append(preferred, s.Mainsnak.Datavalue)
+		default:
+			normal = append(normal, s.Mainsnak.Datavalue)
+		}
+	}
+	return append(preferred, normal...)
+}
+
+func (e entity) entityIds(property string) []string {
+	var ids []string
+	for _, v := range e.values(property) {
+		var value entityIdValue
+		if v.Type != "wikibase-entityid" || json.Unmarshal(v.Value, &value) != nil {
+			continue
+		}
+		ids = append(ids, value.ID)
+	}
+	return ids
+}
+
+// firstTime returns the first usable date among the given properties.
+func (e entity) firstTime(properties []string) *time.Time {
+	for _, property := range properties {
+		for _, v := range e.values(property) {
+			var value timeValue
+			if v.Type != "time" || json.Unmarshal(v.Value, &value) != nil {
+				continue
+			}
+			if t := parseTime(value); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+func (e entity) coordinates() *coordinateValue {
+	for _, v := range e.values(coordinateLocation) {
+		var value coordinateValue
+		if v.Type != "globecoordinate" || json.Unmarshal(v.Value, &value) != nil {
+			continue
+		}
+		return &value
+	}
+	return nil
+}
+
+func (e entity) label(language string) string {
+	return monolingualText(e.Labels, language)
+}
+
+func (e entity) description(language string) string {
+	return monolingualText(e.Descriptions, language)
+}
+
+// monolingualText prefers the requested language, then English, then any
+// language so items without a translation still get a title.
+func monolingualText(texts map[string]monolingual, language string) string {
+	if t, ok := texts[language]; ok {
+		return t.Value
+	}
+	if t, ok := texts["en"]; ok {
+		return t.Value
+	}
+	for _, t := range texts {
+		return t.Value
+	}
+	return ""
+}
+
+// parseTime reads Wikidata's "+1815-06-18T00:00:00Z" timestamps, in which
+// month and day are zero when the precision is a year or a month. Anything
+// less precise than a year cannot be represented as a date and is ignored.
+// Wikidata has no year zero, so "-0490" is 490 BC, astronomical year -489.
+func parseTime(value timeValue) *time.Time {
+	if value.Precision < 9 {
+		return nil
+	}
+
+	var sign byte = '+'
+	s := value.Time
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		sign, s = s[0], s[1:]
+	}
+	date, _, _ := strings.Cut(s, "T")
+
+	var year, month, day int
+	if _, err := fmt.Sscanf(date, "%d-%d-%d", &year, &month, &day); err != nil {
+		return nil
+	}
+	if sign == '-' {
+		year = 1 - year
+	}
+	if month == 0 || value.Precision < 10 {
+		month = 1
+	}
+	if day == 0 || value.Precision < 11 {
+		day = 1
+	}
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// ClassMapping selects items that are an instance of Class and imports them
+// as records of Type.
+type ClassMapping struct {
+	Class string
+	Type  record.Type
+}
+
+var DefaultClasses = []ClassMapping{
+	{Class: "Q5", Type: record.Person},      // human
+	{Class: "Q198", Type: record.Event},     // war
+	{Class: "Q178561", Type: record.Event},  // battle
+	{Class: "Q1656682", Type: record.Event}, // event
+	{Class: "Q39546", Type: record.Object},  // tool
+	{Class: "Q11019", Type: record.Object},  // machine
+}
+
+// DefaultLinkProperties are the item properties turned into links when both
+// ends are imported or already known.
+var DefaultLinkProperties = []string{
+	"P607",  // conflict
+	"P710",  // participant
+	"P1344", // participant in
+	"P61",   // discoverer or inventor
+	"P170",  // creator
+	"P112",  // founded by
+	"P793",  // significant event
+	"P361",  // part of
+	"P527",  // has part
+	"P1365", // replaces
+	"P1366", // replaced by
+}
+
+const (
+	instanceOf         = "P31"
+	coordinateLocation = "P625"
+)
+
+// dateProperties lists, per record type, where the start and end dates are
+// taken from in order of preference.
+var dateProperties = map[record.Type]struct{ start, end []string }{
+	record.Person: {
+		start: []string{"P569"}, // date of birth
+		end:   []string{"P570"}, // date of death
+	},
+	record.Event: {
+		start: []string{"P580", "P585", "P571"}, // start time, point in time, inception
+		end:   []string{"P582", "P585", "P576"}, // end time, point in time, dissolved
+	},
+	record.Arc: {
+		start: []string{"P580", "P571"},
+		end:   []string{"P582", "P576"},
+	},
+	record.Object: {
+		start: []string{"P575", "P571", "P577"}, // time of discovery or invention, inception, publication date
+		end:   []string{"P576", "P2669"},        // dissolved, discontinued date
+	},
+}
+
+type ImportOptions struct {
+	Classes        []ClassMapping
+	LinkProperties []string
+	Language       string
+	LinkStrength   int16
+	DryRun         bool
+}
+
+type ImportReport struct {
+	Scanned  int
+	Matched  int
+	Created  int
+	Existing int
+	Links    int
+}
@@ -0,0 +1,43 @@
+package wikidata
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	date := func(year int, month time.Month, day int) *time.Time {
+		t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		value    timeValue
+		expected *time.Time
+	}{
+		{name: "day", value: timeValue{Time: "+1815-06-18T00:00:00Z", Precision: 11}, expected: date(1815, time.June, 18)},
+		{name: "month", value: timeValue{Time: "+1815-06-00T00:00:00Z", Precision: 10}, expected: date(1815, time.June, 1)},
+		{name: "year", value: timeValue{Time: "+1815-00-00T00:00:00Z", Precision: 9}, expected: date(1815, time.January, 1)},
+		{name: "year with a day given", value: timeValue{Time: "+1815-06-18T00:00:00Z", Precision: 9}, expected: date(1815, time.January, 1)},
+		{name: "without sign", value: timeValue{Time: "1066-10-14T00:00:00Z", Precision: 11}, expected: date(1066, time.October, 14)},
+		{name: "first century", value: timeValue{Time: "+0079-08-24T00:00:00Z", Precision: 11}, expected: date(79, time.August, 24)},
+		{name: "BCE year", value: timeValue{Time: "-0490-00-00T00:00:00Z", Precision: 9}, expected: date(-489, time.January, 1)},
+		{name: "BCE day", value: timeValue{Time: "-0044-03-15T00:00:00Z", Precision: 11}, expected: date(-43, time.March, 15)},
+		{name: "1 BCE", value: timeValue{Time: "-0001-00-00T00:00:00Z", Precision: 9}, expected: date(0, time.January, 1)},
+		{name: "decade", value: timeValue{Time: "+1810-00-00T00:00:00Z", Precision: 8}},
+		{name: "geological", value: timeValue{Time: "-13798000000-00-00T00:00:00Z", Precision: 3}},
+		{name: "malformed", value: timeValue{Time: "June 1815", Precision: 11}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTime(tt.value)
+			switch {
+			case got == nil && tt.expected == nil:
+			case got == nil || tt.expected == nil || !got.Equal(*tt.expected):
+				t.Errorf("parseTime(%q) = %v, expected %v", tt.value.Time, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package wikidata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"historylink/.gen/historylink/public/model"
+	. "historylink/.gen/historylink/public/table"
+	"historylink/internal/features/record"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const lookupChunkSize = 1000
+
+type IWikidataRepository interface {
+	GetRecordIdsByQids(c context.Context, qids []string) (map[string]uuid.UUID, error)
+	GetLinksOf(c context.Context, ids []uuid.UUID) ([]model.Link, error)
+	Insert(c context.Context, batch importBatch) error
+}
+
+type WikidataRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewRepository(db *sql.DB, logger *slog.Logger) IWikidataRepository {
+	return WikidataRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type importBatch struct {
+	Records             []model.Record
+	ExternalIdentifiers []model.ExternalIdentifier
+	Links               []model.Link
+}
+
+func (r WikidataRepository) GetRecordIdsByQids(c context.Context, qids []string) (map[string]uuid.UUID, error) {
+	ids := map[string]uuid.UUID{}
+	for _, chunk := range lo.Chunk(qids, lookupChunkSize) {
+		stmt := SELECT(ExternalIdentifier.AllColumns).
+			FROM(ExternalIdentifier).
+			WHERE(ExternalIdentifier.Scheme.EQ(Int16(record.Wikidata.ToInt16())).
+				AND(ExternalIdentifier.Value.IN(lo.Map(chunk, func(qid string, index int) Expression {
+					return String(qid)
+				})...)))
+
+		var dest []model.ExternalIdentifier
+		if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+			return nil, fmt.Errorf("error getting wikidata identifiers: %w", err)
+		}
+		for _, identifier := range dest {
+			ids[identifier.Value] = identifier.RecordID
+		}
+	}
+	return ids, nil
+}
+
+// GetLinksOf returns every link touching one of the given records.
+func (r WikidataRepository) GetLinksOf(c context.Context, ids []uuid.UUID) ([]model.Link, error) {
+	var links []model.Link
+	for _, chunk := range lo.Chunk(ids, lookupChunkSize) {
+		expressions := lo.Map(chunk, func(id uuid.UUID, index int) Expression {
+			return UUID(id)
+		})
+		stmt := SELECT(Link.AllColumns).
+			FROM(Link).
+			WHERE(Link.RecordID.IN(expressions...).
+				OR(Link.RecordId2.IN(expressions...)))
+
+		var dest []model.Link
+		if err := stmt.QueryContext(c, r.db, &dest); err != nil {
+			return nil, fmt.Errorf("error getting existing links: %w", err)
+		}
+		links = append(links, dest...)
+	}
+	return lo.UniqBy(links, func(l model.Link) uuid.UUID { return l.ID }), nil
+}
+
+func (r WikidataRepository) Insert(c context.Context, batch importBatch) error {
+	tx, err := r.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if len(batch.Records) > 0 {
+		stmt := Record.INSERT(Record.AllColumns.Except(Record.Attributes)).
+			MODELS(batch.Records)
+
+		if _, err = stmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error inserting records: %w", err)
+		}
+	}
+
+	if len(batch.ExternalIdentifiers) > 0 {
+		stmt := ExternalIdentifier.INSERT(ExternalIdentifier.MutableColumns).
+			MODELS(batch.ExternalIdentifiers)
+
+		if _, err = stmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error inserting external identifiers: %w", err)
+		}
+	}
+
+	if len(batch.Links) > 0 {
+		stmt := Link.INSERT(Link.MutableColumns).
+			MODELS(batch.Links)
+
+		if _, err = stmt.ExecContext(c, tx); err != nil {
+			return fmt.Errorf("error inserting links: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package wikidata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"historylink/.gen/historylink/public/model"
+	"historylink/internal/features/record"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const (
+	insertChunkSize = 1000
+	maxEntitySize   = 64 * 1024 * 1024
+)
+
+type IWikidataService interface {
+	Import(c context.Context, r io.Reader, options ImportOptions) (ImportReport, error)
+}
+
+type WikidataService struct {
+	wikidataRepository IWikidataRepository
+	logger             *slog.Logger
+}
+
+func NewWikidataService(wikidataRepository IWikidataRepository, logger *slog.Logger) IWikidataService {
+	return WikidataService{
+		wikidataRepository: wikidataRepository,
+		logger:             logger,
+	}
+}
+
+// candidate is an item of a selected class, kept in memory between reading
+// the dump and writing the records so links between items can be resolved.
+type candidate struct {
+	qid     string
+	record  model.Record
+	targets []string
+}
+
+// Import reads a Wikidata JSON dump, either the official array with one
+// entity per line or a filtered extract with one entity per line, and creates
+// a draft record for every item of one of the selected classes. Items whose
+// QID is already stored are not created again, but their links are still
+// added, so importing a newer dump only fills in what is missing.
+func (s WikidataService) Import(c context.Context, r io.Reader, options ImportOptions) (ImportReport, error) {
+	var report ImportReport
+
+	candidates, err := s.scan(r, options, &report)
+	if err != nil {
+		return report, err
+	}
+	report.Matched = len(candidates)
+
+	qids := lo.Map(candidates, func(candidate candidate, index int) string { return candidate.qid })
+	for _, candidate := range candidates {
+		qids = append(qids, candidate.targets...)
+	}
+	known, err := s.wikidataRepository.GetRecordIdsByQids(c, lo.Uniq(qids))
+	if err != nil {
+		return report, err
+	}
+
+	ids := map[string]uuid.UUID{}
+	var batch importBatch
+	for _, candidate := range candidates {
+		if id, ok := known[candidate.qid]; ok {
+			ids[candidate.qid] = id
+			report.Existing++
+			continue
+		}
+		if _, ok := ids[candidate.qid]; ok {
+			continue
+		}
+
+		candidate.record.ID = uuid.New()
+		ids[candidate.qid] = candidate.record.ID
+		batch.Records = append(batch.Records, candidate.record)
+		batch.ExternalIdentifiers = append(batch.ExternalIdentifiers, model.ExternalIdentifier{
+			RecordID: candidate.record.ID,
+			Scheme:   record.Wikidata.ToInt16(),
+			Value:    candidate.qid,
+		})
+	}
+	for qid, id := range known {
+		ids[qid] = id
+	}
+	report.Created = len(batch.Records)
+
+	batch.Links, err = s.links(c, candidates, ids, known, options.LinkStrength)
+	if err != nil {
+		return report, err
+	}
+	report.Links = len(batch.Links)
+
+	if options.DryRun {
+		return report, nil
+	}
+
+	// Records go in before links so every chunk only references records that
+	// are already committed or part of the same transaction.
+	// Identifiers were appended alongside their records, so chunks line up.
+	identifiers := lo.Chunk(batch.ExternalIdentifiers, insertChunkSize)
+	for i, records := range lo.Chunk(batch.Records, insertChunkSize) {
+		err := s.wikidataRepository.Insert(c, importBatch{
+			Records:             records,
+			ExternalIdentifiers: identifiers[i],
+		})
+		if err != nil {
+			return report, err
+		}
+	}
+	for _, links := range lo.Chunk(batch.Links, insertChunkSize) {
+		if err := s.wikidataRepository.Insert(c, importBatch{Links: links}); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func (s WikidataService) scan(r io.Reader, options ImportOptions, report *ImportReport) ([]candidate, error) {
+	classes := lo.SliceToMap(options.Classes, func(m ClassMapping) (string, record.Type) { return m.Class, m.Type })
+	markers := lo.Map(options.Classes, func(m ClassMapping, index int) []byte {
+		return []byte(fmt.Sprintf(`"id":"%s"`, m.Class))
+	})
+
+	var candidates []candidate
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1024*1024), maxEntitySize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		line = bytes.TrimSuffix(line, []byte(","))
+		if len(line) == 0 || bytes.Equal(line, []byte("[")) || bytes.Equal(line, []byte("]")) {
+			continue
+		}
+		report.Scanned++
+
+		// Decoding every entity of a full dump is slow, so skip lines that
+		// cannot mention any of the classes before parsing them.
+		if !lo.SomeBy(markers, func(marker []byte) bool { return bytes.Contains(line, marker) }) {
+			continue
+		}
+
+		var e entity
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("error decoding entity on line %d: %w", report.Scanned, err)
+		}
+		if e.Type != "item" {
+			continue
+		}
+
+		var recordType record.Type
+		ok := false
+		for _, class := range e.entityIds(instanceOf) {
+			if t, matches := classes[class]; matches {
+				recordType, ok = t, true
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		candidates = append(candidates, s.toCandidate(e, recordType, options))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading dump: %w", err)
+	}
+
+	return candidates, nil
+}
+
+func (s WikidataService) toCandidate(e entity, recordType record.Type, options ImportOptions) candidate {
+	title := e.label(options.Language)
+	if title == "" {
+		title = e.ID
+	}
+	description := e.description(options.Language)
+	if description == "" {
+		description = title
+	}
+
+	location := ""
+	if coordinates := e.coordinates(); coordinates != nil {
+		location = fmt.Sprintf("%.6f, %.6f", coordinates.Latitude, coordinates.Longitude)
+	}
+	significance := ""
+
+	recordUrl := fmt.Sprintf("https://www.wikidata.org/wiki/%s", e.ID)
+	for _, site := range []string{options.Language + "wiki", "enwiki"} {
+		if link, ok := e.Sitelinks[site]; ok {
+			recordUrl = fmt.Sprintf("https://%s.wikipedia.org/wiki/%s", site[:len(site)-len("wiki")], wikiTitle(link.Title))
+			break
+		}
+	}
+
+	dates := dateProperties[recordType]
+	var targets []string
+	for _, property := range options.LinkProperties {
+		targets = append(targets, e.entityIds(property)...)
+	}
+
+	return candidate{
+		qid: e.ID,
+		record: model.Record{
+			Title:        truncate(title),
+			Description:  truncate(description),
+			Location:     &location,
+			Significance: &significance,
+			URL:          truncate(recordUrl),
+			StartDate:    e.firstTime(dates.start),
+			EndDate:      e.firstTime(dates.end),
+			Type:         recordType.ToInt16(),
+			Status:       record.Draft.ToInt16(),
+		},
+		targets: lo.Uniq(targets),
+	}
+}
+
+// links turns the selected properties into links between records, skipping
+// targets that are neither imported nor known, self references and links
+// that already exist in either direction.
+func (s WikidataService) links(c context.Context, candidates []candidate, ids map[string]uuid.UUID, known map[string]uuid.UUID, strength int16) ([]model.Link, error) {
+	existing, err := s.wikidataRepository.GetLinksOf(c, lo.Uniq(lo.Values(known)))
+	if err != nil {
+		return nil, err
+	}
+	seen := lo.SliceToMap(existing, func(l model.Link) ([2]uuid.UUID, bool) {
+		return linkKey(l.RecordID, l.RecordId2), true
+	})
+
+	var links []model.Link
+	for _, candidate := range candidates {
+		from := ids[candidate.qid]
+		for _, target := range candidate.targets {
+			to, ok := ids[target]
+			if !ok || to == from || seen[linkKey(from, to)] {
+				continue
+			}
+			seen[linkKey(from, to)] = true
+			links = append(links, model.Link{
+				RecordID:  from,
+				RecordId2: to,
+				Strength:  strength,
+			})
+		}
+	}
+	return links, nil
+}
+
+// linkKey identifies a link regardless of its direction.
+func linkKey(a, b uuid.UUID) [2]uuid.UUID {
+	if a.String() > b.String() {
+		a, b = b, a
+	}
+	return [2]uuid.UUID{a, b}
+}
+
+func wikiTitle(title string) string {
+	return url.PathEscape(strings.ReplaceAll(title, " ", "_"))
+}
+
+// truncate shortens text to the 255 characters the record columns hold.
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= 255 {
+		return s
+	}
+	return string(runes[:255])
+}